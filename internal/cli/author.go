@@ -0,0 +1,82 @@
+package cli
+
+import (
+	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+func newAuthorCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "author <steamid|item-id>",
+		Short: "List the Workshop items published by a mod's author",
+		Long: "List the Project Zomboid Workshop items published by an author, given their\n" +
+			"SteamID64 or the ID of any of their items. Items already in the target\n" +
+			"config are marked.",
+		Example: "  pzmod author 2392709985\n" +
+			"  pzmod author 76561198000000000 --json",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString("profile")
+			if err := requireKey(cmd, st, profile); err != nil {
+				return err
+			}
+			svc := service.New(newSteam(cmd, st, profile), st)
+
+			author, err := svc.AuthorItems(cmd.Context(), args[0])
+			if err != nil {
+				return err
+			}
+			installed := installedItems(cmd, st)
+
+			if jsonEnabled(cmd) {
+				out := authorJSON{SteamID: author.SteamID, Name: author.Name, Total: author.Total, Items: make([]authorItemJSON, 0, len(author.Items))}
+				for i, it := range author.Items {
+					out.Items = append(out.Items, authorItemJSON{
+						searchItemJSON: newSearchItemJSON(&author.Items[i]),
+						Installed:      installed[it.PublishedFileID],
+					})
+				}
+				return emitJSON(cmd, out)
+			}
+
+			who := author.SteamID
+			if author.Name != "" {
+				who = author.Name + " (" + author.SteamID + ")"
+			}
+			cmd.Printf("%s\n", styleMuted.Render(humanize.Comma(int64(author.Total))+" items by "+who))
+			for _, it := range author.Items {
+				mark := "  "
+				if installed[it.PublishedFileID] {
+					mark = styleOK.Render("✓ ")
+				}
+				cmd.Printf("%s%s  %s  %s\n", mark,
+					styleInfo.Render(it.PublishedFileID),
+					it.Title,
+					styleMuted.Render(humanize.Bytes(uint64(it.FileSize))))
+			}
+			return nil
+		},
+	}
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// installedItems is the target config's WorkshopItems as a set, or empty when
+// no target resolves (the listing works without one).
+func installedItems(cmd *cobra.Command, st *store.Store) map[string]bool {
+	set := map[string]bool{}
+	t, err := resolveTarget(cmd, st)
+	if err != nil {
+		return set
+	}
+	cfg, err := t.config()
+	if err != nil {
+		return set
+	}
+	for _, id := range cfg.WorkshopItems() {
+		set[id] = true
+	}
+	return set
+}
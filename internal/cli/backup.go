@@ -1,7 +1,17 @@
 package cli
 
 import (
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
 	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/internal/pathutil"
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+	"github.com/kldzj/pzmod/pkg/service"
 	"github.com/kldzj/pzmod/pkg/store"
 	"github.com/spf13/cobra"
 )
@@ -11,7 +21,17 @@ func newBackupCmd(st *store.Store) *cobra.Command {
 		Use:   "backup",
 		Short: "Manage config backups",
 	}
-	cmd.AddCommand(newBackupListCmd(st), newBackupSnapshotCmd(st), newBackupRestoreCmd(st))
+	cmd.AddCommand(
+		newBackupListCmd(st),
+		newBackupSnapshotCmd(st),
+		newBackupDiffCmd(st),
+		newBackupRestoreCmd(st),
+		newBackupPinCmd(st, true),
+		newBackupPinCmd(st, false),
+		newBackupRetentionCmd(st),
+		newBackupExportCmd(st),
+		newBackupImportCmd(st),
+	)
 	return cmd
 }
 
@@ -44,7 +64,14 @@ func newBackupListCmd(st *store.Store) *cobra.Command {
 				if note != "" {
 					note = "  " + styleMuted.Render(note)
 				}
-				cmd.Printf("%s  %s  %s%s\n", e.ID, styleMuted.Render("["+e.Kind+"]"), humanize.Bytes(uint64(e.Size)), note)
+				pin := ""
+				if e.Pinned {
+					pin = "  " + styleWarn.Render("pinned")
+				}
+				if e.Content != "" {
+					pin += "  " + styleInfo.Render(fmt.Sprintf("+content (%d)", e.ContentItems))
+				}
+				cmd.Printf("%s  %s  %s%s%s\n", e.ID, styleMuted.Render("["+e.Kind+"]"), humanize.Bytes(uint64(e.Size)), pin, note)
 			}
 			return nil
 		},
@@ -64,7 +91,14 @@ func newBackupSnapshotCmd(st *store.Store) *cobra.Command {
 				return err
 			}
 			note, _ := cmd.Flags().GetString("note")
-			entry, err := t.services(st).SnapshotProfile(t.profile, note, "manual")
+			p := t.profile
+			if withContent, _ := cmd.Flags().GetBool("with-content"); withContent {
+				if p.WorkshopContentPath == "" {
+					return service.ErrNoContentPath
+				}
+				p.ArchiveContent = true
+			}
+			entry, err := t.services(st).SnapshotProfile(p, note, "manual")
 			if err != nil {
 				return err
 			}
@@ -72,34 +106,514 @@ func newBackupSnapshotCmd(st *store.Store) *cobra.Command {
 				return emitJSON(cmd, entry)
 			}
 			cmd.Println(styleOK.Render("snapshot created"), entry.ID)
+			if entry.Content != "" {
+				cmd.Println(styleMuted.Render(fmt.Sprintf("archived content for %d item(s), %s", entry.ContentItems, humanize.Bytes(uint64(entry.ContentSize)))))
+			}
 			return nil
 		},
 	}
 	cmd.Flags().String("note", "", "optional note")
+	cmd.Flags().Bool("with-content", false, "also archive the configured items' Workshop content")
 	addTargetFlags(cmd)
 	return cmd
 }
 
+func newBackupDiffCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "diff <backup-id> [<backup-id>]",
+		Short: "Show what changed between a backup and the config (or another backup)",
+		Long: "Show a semantic diff: mods, workshop items, and maps added, removed, or\n" +
+			"reordered, and every other key whose value changed. With one ID the backup\n" +
+			"is compared with the current config; with two, the first with the second.\n" +
+			"Password values are masked.",
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			svc := t.services(st)
+			old, err := svc.LoadBackup(t.profileID(), args[0])
+			if err != nil {
+				return err
+			}
+			to := "current"
+			var nw *serverconfig.Config
+			if len(args) == 2 {
+				to = args[1]
+				nw, err = svc.LoadBackup(t.profileID(), args[1])
+			} else {
+				nw, err = t.config()
+			}
+			if err != nil {
+				return err
+			}
+			d := serverconfig.Compare(old, nw)
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, newBackupDiffJSON(args[0], to, d))
+			}
+			if d.Empty() {
+				cmd.Println(styleOK.Render("identical"), styleMuted.Render(args[0]+" → "+to))
+				return nil
+			}
+			cmd.Println(styleMuted.Render(args[0] + " → " + to))
+			printListChange(cmd, "Mods", d.Mods)
+			printListChange(cmd, "Workshop items", d.WorkshopItems)
+			printListChange(cmd, "Maps", d.Maps)
+			for _, k := range d.Keys {
+				oldV, newV := maskSecret(k.Key, k.Old), maskSecret(k.Key, k.New)
+				switch {
+				case k.Added:
+					cmd.Printf("%s %s = %s\n", styleOK.Render("+"), k.Key, newV)
+				case k.Removed:
+					cmd.Printf("%s %s %s\n", styleError.Render("-"), k.Key, styleMuted.Render("(was "+oldV+")"))
+				default:
+					cmd.Printf("%s %s: %s → %s\n", styleWarn.Render("~"), k.Key, oldV, newV)
+				}
+			}
+			return nil
+		},
+	}
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// printListChange prints one list's additions, removals, and reorder flag.
+func printListChange(cmd *cobra.Command, label string, c domain.ListChange) {
+	if c.Empty() {
+		return
+	}
+	cmd.Println(styleInfo.Render(label + ":"))
+	for _, v := range c.Added {
+		cmd.Println("  " + styleOK.Render("+ "+v))
+	}
+	for _, v := range c.Removed {
+		cmd.Println("  " + styleError.Render("- "+v))
+	}
+	if c.Reordered {
+		cmd.Println("  " + styleWarn.Render("~ reordered"))
+	}
+}
+
+// maskSecret hides password values in diff output.
+func maskSecret(key, value string) string {
+	if value != "" && serverconfig.IsSecretKey(key) {
+		return "********"
+	}
+	return value
+}
+
 func newBackupRestoreCmd(st *store.Store) *cobra.Command {
 	cmd := &cobra.Command{
 		Use:   "restore <backup-id>",
 		Short: "Restore a backup (a safety snapshot is taken first)",
+		Long: "Restore a backup (a safety snapshot is taken first).\n\n" +
+			"--only restores just part of it and leaves the rest of the live config\n" +
+			"untouched: \"mods\", \"items\", \"maps\", or \"keys=K1,K2\" (aliases such as\n" +
+			"name or slots work). Repeat the flag or separate parts with commas; keys\n" +
+			"after keys= run until the next part name, or separate them with ';'\n" +
+			"(--only mods,keys=name;PVP,maps).\n\n" +
+			"--with-content also puts back the Workshop mod folders archived with the\n" +
+			"backup (see `profile edit --archive-content`), replacing what is installed.",
+		Example: "  pzmod backup restore 20240304-090000.000000000\n" +
+			"  pzmod backup restore 20240304-090000.000000000 --with-content\n" +
+			"  pzmod backup restore 20240304-090000.000000000 --only mods,items\n" +
+			"  pzmod backup restore 20240304-090000.000000000 --only keys=name,PVP",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			only, _ := cmd.Flags().GetStringArray("only")
+			if withContent, _ := cmd.Flags().GetBool("with-content"); withContent {
+				if len(only) > 0 {
+					return errors.New("--with-content restores the whole backup; it can't be combined with --only")
+				}
+				_, items, err := t.services(st).RestoreBackupContent(t.profile, args[0])
+				if err != nil {
+					return err
+				}
+				if jsonEnabled(cmd) {
+					return emitJSON(cmd, backupRestoreJSON{Restored: args[0], Changed: true, Missing: []string{}, Content: orEmpty(items)})
+				}
+				cmd.Println(styleOK.Render("restored"), args[0], styleMuted.Render(fmt.Sprintf("(with content for %d item(s))", len(items))))
+				return nil
+			}
+			if len(only) == 0 {
+				if _, err := t.services(st).RestoreBackup(t.profile, args[0]); err != nil {
+					return err
+				}
+				if jsonEnabled(cmd) {
+					return emitJSON(cmd, map[string]string{"restored": args[0]})
+				}
+				cmd.Println(styleOK.Render("restored"), args[0])
+				return nil
+			}
+
+			sel, err := parseRestoreSelection(only)
+			if err != nil {
+				return err
+			}
+			res, err := t.services(st).RestorePartial(t.profile, args[0], sel)
+			if err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				missing := res.Missing
+				if missing == nil {
+					missing = []string{}
+				}
+				return emitJSON(cmd, backupRestoreJSON{Restored: args[0], Partial: true, Changed: !res.Summary.Empty(), Missing: missing})
+			}
+			for _, k := range res.Missing {
+				cmd.Println(styleWarn.Render("not in backup:"), k)
+			}
+			if res.Summary.Empty() {
+				cmd.Println(styleMuted.Render("nothing to restore - the selected parts already match"), args[0])
+				return nil
+			}
+			cmd.Println(styleOK.Render("restored"), args[0], styleMuted.Render("("+strings.Join(only, ", ")+")"))
+			return nil
+		},
+	}
+	cmd.Flags().StringArray("only", nil, `restore only these parts: mods, items, maps, keys=K1,K2 (or keys=K1;K2)`)
+	cmd.Flags().Bool("with-content", false, "also restore the Workshop content archived with the backup")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// parseRestoreSelection turns --only values into a serverconfig.Selection.
+// Each value is split on commas. "keys=" starts a key list (itself split on
+// semicolons) that the following comma-separated names continue until one is
+// a part name, so "keys=name,PVP", "mods,keys=name", and "keys=name;PVP,mods"
+// all read as expected.
+func parseRestoreSelection(only []string) (serverconfig.Selection, error) {
+	var sel serverconfig.Selection
+	addKeys := func(list string) {
+		for _, k := range strings.Split(list, ";") {
+			if k = strings.TrimSpace(k); k != "" {
+				key, _ := resolveKey(k)
+				sel.Keys = append(sel.Keys, key)
+			}
+		}
+	}
+	for _, v := range only {
+		inKeys := false
+		for _, part := range strings.Split(v, ",") {
+			part = strings.TrimSpace(part)
+			if rest, ok := strings.CutPrefix(part, "keys="); ok {
+				addKeys(rest)
+				inKeys = true
+				continue
+			}
+			switch part {
+			case "mods":
+				sel.Mods = true
+			case "items", "workshop":
+				sel.WorkshopItems = true
+			case "maps":
+				sel.Maps = true
+			case "":
+				continue
+			default:
+				if !inKeys {
+					return sel, fmt.Errorf("unknown --only part %q (want mods, items, maps, or keys=K1,K2)", part)
+				}
+				addKeys(part)
+				continue
+			}
+			inKeys = false
+		}
+	}
+	if sel.Empty() {
+		return sel, errors.New("--only selects nothing")
+	}
+	return sel, nil
+}
+
+func newBackupPinCmd(st *store.Store, pin bool) *cobra.Command {
+	use, short, verb := "pin <backup-id>", "Pin a backup so retention never prunes it", "pinned"
+	if !pin {
+		use, short, verb = "unpin <backup-id>", "Unpin a backup so retention may prune it again", "unpinned"
+	}
+	cmd := &cobra.Command{
+		Use:   use,
+		Short: short,
 		Args:  cobra.ExactArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			t, err := resolveTarget(cmd, st)
 			if err != nil {
 				return err
 			}
-			if err := st.Restore(t.profileID(), args[0], t.iniPath()); err != nil {
+			if err := st.PinBackup(t.profileID(), args[0], pin); err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, map[string]any{"id": args[0], "pinned": pin})
+			}
+			cmd.Println(styleOK.Render(verb), args[0])
+			return nil
+		},
+	}
+	addTargetFlags(cmd)
+	return cmd
+}
+
+func newBackupRetentionCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "retention",
+		Short: "Show or change the profile's backup retention policy",
+		Long: "Show or change the profile's backup retention policy.\n\n" +
+			"A snapshot is kept when any rule selects it: the newest --keep-last, the\n" +
+			"newest per hour/day/ISO week for the last --hourly/--daily/--weekly buckets,\n" +
+			"and the newest N of a kind via --keep-kind (N < 0 keeps all of that kind).\n" +
+			"Pinned snapshots are always kept. The policy is applied after every snapshot.",
+		Example: "  pzmod backup retention --keep-last 5 --daily 7 --weekly 4\n" +
+			"  pzmod backup retention --keep-kind manual=-1 --keep-kind pre-restore=3",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			policy := t.profile.RetentionPolicy()
+			changed := false
+			for flag, dst := range map[string]*int{
+				"keep-last": &policy.KeepLast,
+				"hourly":    &policy.Hourly,
+				"daily":     &policy.Daily,
+				"weekly":    &policy.Weekly,
+			} {
+				if cmd.Flags().Changed(flag) {
+					*dst, _ = cmd.Flags().GetInt(flag)
+					changed = true
+				}
+			}
+			if cmd.Flags().Changed("keep-kind") {
+				kinds, _ := cmd.Flags().GetStringToInt("keep-kind")
+				if policy.Kinds == nil {
+					policy.Kinds = map[string]int{}
+				}
+				for k, n := range kinds {
+					policy.Kinds[k] = n
+				}
+				changed = true
+			}
+
+			if changed {
+				if t.adHoc {
+					return errors.New("retention is stored per profile; use --profile (or the default profile), not --file")
+				}
+				if policy.IsZero() {
+					return errors.New("retention policy would keep nothing; set at least one rule")
+				}
+				t.profile.Retention = &policy
+				if err := st.UpdateProfile(t.profile); err != nil {
+					return err
+				}
+				if apply, _ := cmd.Flags().GetBool("apply"); apply {
+					if _, err := st.PruneWithPolicy(t.profileID(), policy); err != nil {
+						return err
+					}
+				}
+			}
+
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, policy)
+			}
+			cmd.Println(describeRetention(policy))
+			return nil
+		},
+	}
+	cmd.Flags().Int("keep-last", 0, "keep the newest N snapshots")
+	cmd.Flags().Int("hourly", 0, "keep the newest snapshot of each of the last N hours")
+	cmd.Flags().Int("daily", 0, "keep the newest snapshot of each of the last N days")
+	cmd.Flags().Int("weekly", 0, "keep the newest snapshot of each of the last N weeks")
+	cmd.Flags().StringToInt("keep-kind", nil, "keep the newest N snapshots of a kind, e.g. manual=-1 (repeatable)")
+	cmd.Flags().Bool("apply", false, "prune existing snapshots with the new policy right away")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// describeRetention renders a policy as one human-readable line.
+func describeRetention(p store.RetentionPolicy) string {
+	var parts []string
+	if p.KeepLast > 0 {
+		parts = append(parts, fmt.Sprintf("last %d", p.KeepLast))
+	}
+	if p.Hourly > 0 {
+		parts = append(parts, fmt.Sprintf("%d hourly", p.Hourly))
+	}
+	if p.Daily > 0 {
+		parts = append(parts, fmt.Sprintf("%d daily", p.Daily))
+	}
+	if p.Weekly > 0 {
+		parts = append(parts, fmt.Sprintf("%d weekly", p.Weekly))
+	}
+	kinds := make([]string, 0, len(p.Kinds))
+	for k := range p.Kinds {
+		kinds = append(kinds, k)
+	}
+	sort.Strings(kinds)
+	for _, k := range kinds {
+		if n := p.Kinds[k]; n < 0 {
+			parts = append(parts, "all "+k)
+		} else {
+			parts = append(parts, fmt.Sprintf("%d %s", n, k))
+		}
+	}
+	return "keep " + strings.Join(parts, ", ") + " " + styleMuted.Render("(+ pinned)")
+}
+
+// envBackupPassphrase supplies the archive passphrase without putting it on the
+// command line (and in shell history).
+const envBackupPassphrase = "PZMOD_BACKUP_PASSPHRASE"
+
+// archivePassphrase returns --passphrase, falling back to PZMOD_BACKUP_PASSPHRASE.
+func archivePassphrase(cmd *cobra.Command) string {
+	if p, _ := cmd.Flags().GetString("passphrase"); p != "" {
+		return p
+	}
+	return os.Getenv(envBackupPassphrase)
+}
+
+func newBackupExportCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "export",
+		Short: "Bundle a profile's backups into a portable archive",
+		Long: "Bundle a profile's snapshots, backup index, and profile metadata into a\n" +
+			"tar.gz for `pzmod backup import` on another machine. With a passphrase\n" +
+			"(--passphrase or " + envBackupPassphrase + ") the archive is encrypted, which\n" +
+			"protects the Password= values inside. The Steam API key is never exported.",
+		Example: "  pzmod backup export --profile my-server --out backups.tar.gz",
+		Args:    cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			out, _ := cmd.Flags().GetString("out")
+			if out == "" {
+				return errors.New("--out is required")
+			}
+			out = pathutil.Expand(out)
+			f, err := os.OpenFile(out, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0600)
+			if err != nil {
+				return err
+			}
+			passphrase := archivePassphrase(cmd)
+			n, err := st.ExportBackups(t.profile, f, passphrase)
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+			if err != nil {
+				_ = os.Remove(out)
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, backupExportJSON{Path: out, Backups: n, Encrypted: passphrase != ""})
+			}
+			note := ""
+			if passphrase != "" {
+				note = " " + styleMuted.Render("(encrypted)")
+			}
+			cmd.Printf("%s %d backup(s) to %s%s\n", styleOK.Render("exported"), n, pathutil.Abbreviate(out), note)
+			return nil
+		},
+	}
+	cmd.Flags().StringP("out", "o", "", "archive path to write (e.g. backups.tar.gz)")
+	cmd.Flags().String("passphrase", "", "encrypt the archive (or set "+envBackupPassphrase+")")
+	cmd.MarkFlagFilename("out", "gz", "tgz")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+func newBackupImportCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "import <archive>",
+		Short: "Merge backups from an exported archive",
+		Long: "Merge the snapshots from a `pzmod backup export` archive into the local\n" +
+			"store. Snapshots already present (same SHA256) are skipped, so importing\n" +
+			"twice is harmless.\n\n" +
+			"Without --profile or --file the archive's own profile is used; when it does\n" +
+			"not exist here it is created from the archived metadata. Paths from the\n" +
+			"other machine are not kept: give the config path with --ini, and set the\n" +
+			"Workshop and local mods paths with `pzmod profile edit`.",
+		Example: "  pzmod backup import backups.tar.gz --ini /srv/zomboid/servertest.ini",
+		Args:    cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			f, err := os.Open(pathutil.Expand(args[0]))
+			if err != nil {
+				return err
+			}
+			archive, err := store.ReadBackupArchive(f, archivePassphrase(cmd))
+			f.Close()
+			if err != nil {
+				return err
+			}
+
+			var profileID string
+			created := false
+			file, _ := cmd.Flags().GetString("file")
+			profileName, _ := cmd.Flags().GetString("profile")
+			if file != "" || profileName != "" {
+				t, err := resolveTarget(cmd, st)
+				if err != nil {
+					return err
+				}
+				profileID = t.profileID()
+			} else {
+				p := archive.Manifest.Profile
+				if p.ID == "" {
+					return errors.New("archive has no profile; pass --profile or --file")
+				}
+				if _, err := st.Profile(p.ID); errors.Is(err, store.ErrNoProfile) {
+					// The paths belong to the exporting machine.
+					p.IniPath, p.WorkshopContentPath, p.LocalModsPath = "", "", ""
+					p.ArchiveContent = false
+					if ini, _ := cmd.Flags().GetString("ini"); ini != "" {
+						p.IniPath = pathutil.Expand(ini)
+					}
+					if p, err = st.AddProfile(p); err != nil {
+						return err
+					}
+					created = true
+				} else if err != nil {
+					return err
+				}
+				profileID = p.ID
+			}
+
+			res, err := st.ImportBackups(profileID, archive)
+			if err != nil {
 				return err
 			}
 			if jsonEnabled(cmd) {
-				return emitJSON(cmd, map[string]string{"restored": args[0]})
+				return emitJSON(cmd, backupImportJSON{
+					Profile:        profileID,
+					ProfileCreated: created,
+					Imported:       len(res.Imported),
+					Skipped:        res.Skipped,
+				})
+			}
+			if created {
+				cmd.Println(styleOK.Render("added profile"), profileID)
+				if p, err := st.Profile(profileID); err == nil && p.IniPath == "" {
+					cmd.Println(styleMuted.Render(fmt.Sprintf("set its config with `pzmod profile edit %s --file <servertest.ini>`", profileID)))
+				}
+			}
+			cmd.Printf("%s %d backup(s) into %s", styleOK.Render("imported"), len(res.Imported), profileID)
+			if res.Skipped > 0 {
+				cmd.Print(styleMuted.Render(fmt.Sprintf(" (%d already present)", res.Skipped)))
 			}
-			cmd.Println(styleOK.Render("restored"), args[0])
+			cmd.Println()
 			return nil
 		},
 	}
+	cmd.Flags().String("passphrase", "", "passphrase for an encrypted archive (or set "+envBackupPassphrase+")")
+	cmd.Flags().String("ini", "", "config path for a profile created by the import")
+	cmd.MarkFlagFilename("ini", "ini")
 	addTargetFlags(cmd)
 	return cmd
 }
@@ -0,0 +1,109 @@
+package cli
+
+import (
+	"fmt"
+
+	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/internal/pathutil"
+	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+func newContentCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "content",
+		Short: "Inspect downloaded Workshop content",
+	}
+	cmd.AddCommand(newContentStatusCmd(st))
+	return cmd
+}
+
+func newContentStatusCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "status",
+		Short: "Compare downloaded Workshop content with the config",
+		Long: "Reads the profile's Workshop content path and Steam's appworkshop_108600.acf\n" +
+			"manifest, and lists configured items that aren't downloaded, downloaded\n" +
+			"items older than their latest Workshop revision, and downloaded items no\n" +
+			"longer in WorkshopItems, with their sizes.",
+		Example: "  pzmod content status --profile main\n" +
+			"  pzmod download --prune   # then fix what it found",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			cfg, err := t.config()
+			if err != nil {
+				return err
+			}
+			rep, err := t.services(st).ContentStatus(cmd.Context(), t.profile, cfg.ServerMods())
+			if err != nil {
+				return err
+			}
+
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, contentStatusJSON{
+					ContentPath:  rep.ContentPath,
+					Manifest:     rep.Manifest,
+					Missing:      contentItemsJSON(rep.Missing),
+					Outdated:     contentItemsJSON(rep.Outdated),
+					Unreferenced: contentItemsJSON(rep.Unreferenced),
+					Current:      rep.Current,
+					Unchecked:    orEmpty(rep.Unchecked),
+				})
+			}
+
+			cmd.Println(styleMuted.Render("content: " + pathutil.Abbreviate(rep.ContentPath)))
+			if rep.Manifest == "" {
+				cmd.Println(styleWarn.Render("no appworkshop manifest found; installed revisions are unknown"))
+			}
+			printContentItems(cmd, "Not downloaded", rep.Missing, false)
+			printContentItems(cmd, "Outdated", rep.Outdated, true)
+			printContentItems(cmd, "Not in WorkshopItems", rep.Unreferenced, false)
+			if len(rep.Unchecked) > 0 {
+				cmd.Println(styleWarn.Render(fmt.Sprintf("steam did not answer for %d item(s); their revisions weren't checked", len(rep.Unchecked))))
+			}
+			cmd.Println(styleOK.Render(fmt.Sprintf("%d item(s) downloaded and up to date", rep.Current)))
+			return nil
+		},
+	}
+	addTargetFlags(cmd)
+	return cmd
+}
+
+func printContentItems(cmd *cobra.Command, heading string, items []service.ContentItem, revisions bool) {
+	if len(items) == 0 {
+		return
+	}
+	var total int64
+	for _, it := range items {
+		total += it.Size
+	}
+	cmd.Printf("%s (%d, %s)\n", styleInfo.Render(heading), len(items), humanize.Bytes(uint64(total)))
+	for _, it := range items {
+		line := fmt.Sprintf("  %s  %s  %s", it.ID, it.Title, styleMuted.Render(humanize.Bytes(uint64(it.Size))))
+		if revisions {
+			line += styleMuted.Render(fmt.Sprintf("  installed %s, updated %s",
+				it.Installed.Format("2006-01-02"), it.Workshop.Format("2006-01-02")))
+		}
+		cmd.Println(line)
+	}
+}
+
+func contentItemsJSON(items []service.ContentItem) []contentItemJSON {
+	out := make([]contentItemJSON, 0, len(items))
+	for _, it := range items {
+		j := contentItemJSON{ID: it.ID, Title: it.Title, Size: it.Size}
+		if !it.Installed.IsZero() {
+			j.Installed = &it.Installed
+		}
+		if !it.Workshop.IsZero() {
+			j.Workshop = &it.Workshop
+		}
+		out = append(out, j)
+	}
+	return out
+}
@@ -28,7 +28,7 @@ func newDoctorCmd(st *store.Store) *cobra.Command {
 			if st.HasAPIKey(t.profileID()) {
 				checks = append(checks, doctorCheckJSON{Name: "api-key", Status: "ok", Detail: "Steam API key configured"})
 			} else {
-				checks = append(checks, doctorCheckJSON{Name: "api-key", Status: "warn", Detail: "no Steam API key; run `pzmod api-key <key>` (validation skipped)"})
+				checks = append(checks, doctorCheckJSON{Name: "api-key", Status: "warn", Detail: "no Steam API key; using keyless Workshop access (search unavailable); run `pzmod api-key <key>`"})
 			}
 
 			cfg, cerr := t.config()
@@ -54,10 +54,8 @@ func newDoctorCmd(st *store.Store) *cobra.Command {
 			switch {
 			case offline:
 				checks = append(checks, doctorCheckJSON{Name: "validation", Status: "skip", Detail: "--offline"})
-			case !st.HasAPIKey(t.profileID()):
-				checks = append(checks, doctorCheckJSON{Name: "validation", Status: "skip", Detail: "no API key"})
 			default:
-				report, verr := t.services(st).Validate(cmd.Context(), sm, b)
+				report, verr := t.services(st).ValidateProfile(cmd.Context(), t.profile, sm)
 				switch {
 				case verr != nil:
 					checks = append(checks, doctorCheckJSON{Name: "validation", Status: "error", Detail: verr.Error()})
@@ -67,6 +65,12 @@ func newDoctorCmd(st *store.Store) *cobra.Command {
 						Status: "error",
 						Detail: fmt.Sprintf("%d error(s), %d warning(s); run `pzmod validate` for detail", report.Count(domain.SeverityError), report.Count(domain.SeverityWarning)),
 					})
+				case report.Incomplete():
+					checks = append(checks, doctorCheckJSON{
+						Name:   "validation",
+						Status: "warn",
+						Detail: fmt.Sprintf("incomplete: %d item(s) could not be checked; %d warning(s)", len(report.Unchecked), report.Count(domain.SeverityWarning)),
+					})
 				default:
 					checks = append(checks, doctorCheckJSON{
 						Name:   "validation",
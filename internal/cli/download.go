@@ -0,0 +1,148 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/internal/pathutil"
+	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/steamcmd"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+func newDownloadCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "download",
+		Short: "Download or update Workshop content with steamcmd",
+		Long: "Runs steamcmd to download every item in the target's WorkshopItems into the\n" +
+			"profile's Workshop content path (which must be a\n" +
+			".../steamapps/workshop/content/108600 directory), then checks each one\n" +
+			"landed there. Run it before a restart so the server doesn't download mods\n" +
+			"itself. With --prune, content for items no longer listed is deleted.",
+		Example: "  pzmod download --profile main\n" +
+			"  pzmod download --prune\n" +
+			"  pzmod download --steamcmd ~/steamcmd/steamcmd.sh",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			prune, _ := cmd.Flags().GetBool("prune")
+			bin, _ := cmd.Flags().GetString("steamcmd")
+			if bin == "" {
+				var err error
+				if bin, err = st.SteamCMD(); err != nil {
+					return err
+				}
+			}
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			cfg, err := t.config()
+			if err != nil {
+				return err
+			}
+
+			quiet := jsonEnabled(cmd)
+			progress := func(e steamcmd.Event) {
+				if quiet {
+					return
+				}
+				switch e.Kind {
+				case steamcmd.EventStart:
+					cmd.Println(styleMuted.Render("  downloading " + e.ID + "…"))
+				case steamcmd.EventDone:
+					cmd.Printf("  %s %s %s\n", styleOK.Render("✓"), e.ID, styleMuted.Render(humanize.Bytes(uint64(e.Bytes))))
+				case steamcmd.EventFailed:
+					cmd.Printf("  %s %s %s\n", styleError.Render("✗"), e.ID, styleMuted.Render(e.Err))
+				}
+			}
+			svc := t.services(st)
+			res, err := svc.Download(cmd.Context(), t.profile, cfg.ServerMods(), service.DownloadOptions{
+				SteamCMD: binaryPath(bin),
+				Prune:    prune,
+				Progress: progress,
+			})
+			if err != nil {
+				return err
+			}
+
+			failedIDs := make([]string, 0, len(res.Failed))
+			for id := range res.Failed {
+				failedIDs = append(failedIDs, id)
+			}
+			sort.Strings(failedIDs)
+			if quiet {
+				if err := emitJSON(cmd, downloadJSON{
+					ContentPath: res.ContentPath,
+					Downloaded:  orEmpty(res.Downloaded),
+					Failed:      res.Failed,
+					Pruned:      orEmpty(res.Pruned),
+				}); err != nil {
+					return err
+				}
+			} else {
+				cmd.Println(styleOK.Render(fmt.Sprintf("%d item(s) in %s", len(res.Downloaded), pathutil.Abbreviate(res.ContentPath))))
+				for _, id := range failedIDs {
+					cmd.Printf("%s %s: %s\n", styleError.Render("failed"), id, res.Failed[id])
+				}
+				for _, id := range res.Pruned {
+					cmd.Printf("%s %s\n", styleWarn.Render("removed"), id)
+				}
+			}
+			if len(failedIDs) > 0 {
+				return fmt.Errorf("%d item(s) failed to download", len(failedIDs))
+			}
+			return nil
+		},
+	}
+	addTargetFlags(cmd)
+	cmd.Flags().Bool("prune", false, "delete content for items no longer in WorkshopItems")
+	cmd.Flags().String("steamcmd", "", "steamcmd binary for this run (default: the saved path, or steamcmd on PATH)")
+	cmd.MarkFlagFilename("steamcmd")
+	return cmd
+}
+
+func newSteamCMDCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "steamcmd [path]",
+		Short: "Show or set the steamcmd binary used by download",
+		Args:  cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			if clear, _ := cmd.Flags().GetBool("clear"); clear {
+				if err := st.SetSteamCMD(""); err != nil {
+					return err
+				}
+			} else if len(args) == 1 {
+				if err := st.SetSteamCMD(binaryPath(args[0])); err != nil {
+					return err
+				}
+			}
+			path, err := st.SteamCMD()
+			if err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, map[string]string{"steamcmd": path})
+			}
+			if path == "" {
+				cmd.Println(styleMuted.Render(steamcmd.DefaultBinary + " (from PATH)"))
+				return nil
+			}
+			cmd.Println(pathutil.Abbreviate(path))
+			return nil
+		},
+	}
+	cmd.Flags().BoolP("clear", "c", false, "forget the saved path and use steamcmd from PATH")
+	return cmd
+}
+
+// binaryPath expands a path to a binary, leaving a bare name ("steamcmd") to
+// be looked up on PATH.
+func binaryPath(p string) string {
+	if strings.ContainsAny(p, `/\`) || strings.HasPrefix(p, "~") {
+		return pathutil.Expand(p)
+	}
+	return p
+}
@@ -0,0 +1,154 @@
+package cli
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+)
+
+// journalOp describes the running command for the operation journal. note is
+// the pre-operation snapshot note; --no-backup drops it.
+func journalOp(cmd *cobra.Command, args []string, note string) service.Op {
+	op := service.Op{
+		Command: strings.TrimPrefix(cmd.CommandPath(), cmd.Root().Name()+" "),
+		Args:    append([]string{}, args...),
+		Note:    note,
+	}
+	cmd.Flags().Visit(func(f *pflag.Flag) {
+		switch f.Name {
+		case "json", "file", "profile", "no-backup":
+			return
+		}
+		op.Args = append(op.Args, "--"+f.Name+"="+f.Value.String())
+	})
+	if noBackup, _ := cmd.Flags().GetBool("no-backup"); noBackup {
+		op.Note = ""
+	}
+	return op
+}
+
+func newHistoryCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "history",
+		Short: "Show the operation journal for the target config",
+		Long: "Show who changed the target config, when, with which command, and what\n" +
+			"changed. Each entry links the backup taken just before it; `pzmod undo`\n" +
+			"restores it.",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			journal, err := st.Journal(t.profileID())
+			if err != nil {
+				return err
+			}
+			undone := service.UndoneIDs(journal)
+			limit, _ := cmd.Flags().GetInt("limit")
+
+			var shown []store.JournalEntry // newest first
+			for i := len(journal) - 1; i >= 0 && (limit <= 0 || len(shown) < limit); i-- {
+				shown = append(shown, journal[i])
+			}
+			if jsonEnabled(cmd) {
+				out := historyJSON{Entries: []historyEntryJSON{}}
+				for _, e := range shown {
+					out.Entries = append(out.Entries, historyEntryJSON{JournalEntry: e, Undone: undone[e.ID]})
+				}
+				return emitJSON(cmd, out)
+			}
+			if len(shown) == 0 {
+				cmd.Println(styleMuted.Render("no operations recorded yet"))
+				return nil
+			}
+			for _, e := range shown {
+				line := fmt.Sprintf("#%-3d %s  %s  %s", e.ID, styleMuted.Render(e.Timestamp), styleInfo.Render(e.Actor), strings.Join(append([]string{e.Command}, e.Args...), " "))
+				if undone[e.ID] {
+					line += "  " + styleWarn.Render("undone")
+				}
+				cmd.Println(line)
+				if s := describeJournalSummary(e.Summary); s != "" {
+					cmd.Println("     " + s)
+				}
+				if e.BackupID != "" {
+					cmd.Println("     " + styleMuted.Render("backup "+e.BackupID))
+				}
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Int("limit", 20, "show at most this many entries (0 for all)")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+func newUndoCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "undo [n]",
+		Short: "Undo the last n journaled operations (default 1)",
+		Long: "Undo the last n operations in the journal by restoring the backup taken\n" +
+			"before the oldest of them. pzmod refuses when the config was edited since\n" +
+			"the last journaled write (by hand or by the server); --force overrides.\n" +
+			"The undo is journaled and snapshotted, so it can be reverted with\n" +
+			"`pzmod backup restore`.",
+		Example: "  pzmod undo      # revert the last change\n" +
+			"  pzmod undo 3    # revert the last three changes",
+		Args: cobra.MaximumNArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			n := 1
+			if len(args) == 1 {
+				if n, err = strconv.Atoi(args[0]); err != nil || n < 1 {
+					return fmt.Errorf("invalid count %q: want a positive number", args[0])
+				}
+			}
+			force, _ := cmd.Flags().GetBool("force")
+			res, err := t.services(st).Undo(t.profile, n, force)
+			if err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, undoJSON{Entry: res.Entry, Reverted: res.Reverted})
+			}
+			for _, e := range res.Reverted {
+				cmd.Println(styleOK.Render("undid"), fmt.Sprintf("#%d", e.ID), strings.Join(append([]string{e.Command}, e.Args...), " "), styleMuted.Render("("+e.Actor+")"))
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("force", false, "undo even if the config changed since the last journaled write")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// describeJournalSummary renders a journal summary as "+2 mods, -1 items, ...".
+func describeJournalSummary(s store.JournalSummary) string {
+	var parts []string
+	for _, l := range []struct {
+		name string
+		d    store.JournalDelta
+	}{{"mods", s.Mods}, {"items", s.WorkshopItems}, {"maps", s.Maps}} {
+		if l.d.Added > 0 {
+			parts = append(parts, fmt.Sprintf("+%d %s", l.d.Added, l.name))
+		}
+		if l.d.Removed > 0 {
+			parts = append(parts, fmt.Sprintf("-%d %s", l.d.Removed, l.name))
+		}
+		if l.d.Reordered {
+			parts = append(parts, l.name+" reordered")
+		}
+	}
+	for _, f := range s.ChangedFields {
+		parts = append(parts, f+" changed")
+	}
+	return strings.Join(parts, ", ")
+}
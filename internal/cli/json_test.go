@@ -2,7 +2,16 @@ package cli
 
 import (
 	"encoding/json"
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
 	"testing"
+	"time"
+
+	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/steam/steamtest"
 )
 
 func TestModsListJSON(t *testing.T) {
@@ -81,6 +90,43 @@ func TestValidateJSONError(t *testing.T) {
 	}
 }
 
+func TestValidateJSONIncomplete(t *testing.T) {
+	st := testStore(t)
+	f := cannedFake()
+	f.Fail = map[string]error{"200": steam.ErrCircuitOpen}
+	useFakeSteam(t, f)
+	ini := writeINI(t, "WorkshopItems=100;200\nMods=CoreLib;Weapons\n")
+
+	out, err := run(t, st, "validate", "--file", ini, "--json")
+	if err == nil || !strings.Contains(err.Error(), "incomplete") {
+		t.Errorf("err = %v; want a non-zero 'incomplete' exit", err)
+	}
+	var got validateJSON
+	if uerr := json.Unmarshal([]byte(out), &got); uerr != nil {
+		t.Fatalf("unmarshal %q: %v", out, uerr)
+	}
+	if !got.Incomplete || got.OK || len(got.Unchecked) != 1 || got.Unchecked[0] != "200" {
+		t.Errorf("got incomplete=%v ok=%v unchecked=%v; want incomplete, not ok, [200]", got.Incomplete, got.OK, got.Unchecked)
+	}
+}
+
+func TestValidateJSONUnavailableHint(t *testing.T) {
+	st := testStore(t)
+	f := cannedFake()
+	f.Results = map[string]steam.Result{"404": steam.ResultAccessDenied}
+	useFakeSteam(t, f)
+	ini := writeINI(t, "WorkshopItems=404\nMods=\n")
+
+	out, _ := run(t, st, "validate", "--file", ini, "--json")
+	var got validateJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if len(got.Findings) != 1 || got.Findings[0].Code != "private" || !strings.Contains(got.Findings[0].Hint, "author") {
+		t.Errorf("findings = %+v; want one private finding with an ask-the-author hint", got.Findings)
+	}
+}
+
 func TestSearchJSON(t *testing.T) {
 	st := testStore(t)
 	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef")
@@ -202,6 +248,42 @@ func TestModsShowJSON(t *testing.T) {
 	}
 }
 
+func TestModsShowModInfoFolder(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, cannedFake())
+	content := t.TempDir()
+	dir := filepath.Join(content, "200", "mods", "Weapons", "42.3")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id=Weapons\nname=Weapons\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	ini := writeINI(t, "WorkshopItems=200\nMods=Weapons\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini, "--workshop-path", content); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, st, "mods", "show", "200")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(out, "mod.info: Weapons from 42.3/") {
+		t.Errorf("mods show = %q; want the mod.info folder", out)
+	}
+	out, err = run(t, st, "mods", "show", "200", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got modShowResultJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if len(got.Items) != 1 || got.Items[0].ModInfoDirs["Weapons"] != "42.3" {
+		t.Errorf("items = %+v; want modInfoDirs Weapons=42.3", got.Items)
+	}
+}
+
 func TestModsShowMissing(t *testing.T) {
 	st := testStore(t)
 	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef")
@@ -219,3 +301,345 @@ func TestModsShowMissing(t *testing.T) {
 		t.Errorf("missing = %v; want it to contain 999", got.Missing)
 	}
 }
+
+func TestSearchFiltersAndBuildTag(t *testing.T) {
+	st := testStore(t)
+	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef")
+	f := cannedFake()
+	useFakeSteam(t, f)
+	ini := writeINI(t, "PublicName=x\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini, "--build", "b41"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := run(t, st, "search", "--sort", "updated", "--exclude-tag", "Map", "--updated-since", "2024-01-31", "--page", "2", "--json"); err != nil {
+		t.Fatal(err)
+	}
+	q := f.LastQuery
+	if q.Sort != steam.SortUpdated || q.Page != 2 || len(q.ExcludedTags) != 1 {
+		t.Errorf("query = %+v", q)
+	}
+	if len(q.Tags) != 1 || q.Tags[0] != "Build 41" {
+		t.Errorf("tags = %v; want the profile's build tag", q.Tags)
+	}
+	if q.UpdatedAfter.Format("2006-01-02") != "2024-01-31" {
+		t.Errorf("updated after = %v", q.UpdatedAfter)
+	}
+
+	if _, err := run(t, st, "search", "x", "--created-since", "2023-01-01", "--created-before", "2023-06-30",
+		"--updated-before", "2024-02-29", "--json"); err != nil {
+		t.Fatal(err)
+	}
+	q = f.LastQuery
+	if q.CreatedAfter.Format("2006-01-02") != "2023-01-01" || q.CreatedBefore.Format("2006-01-02") != "2023-06-30" ||
+		q.UpdatedBefore.Format("2006-01-02") != "2024-02-29" || !q.UpdatedAfter.IsZero() {
+		t.Errorf("date window = %+v", q)
+	}
+	if _, err := run(t, st, "search", "x", "--updated-since", "2024-02-01", "--updated-before", "2024-01-01"); err == nil {
+		t.Error("an empty --updated-since/--updated-before window should fail")
+	}
+
+	if _, err := run(t, st, "search", "x", "--any-build", "--json"); err != nil {
+		t.Fatal(err)
+	}
+	if len(f.LastQuery.Tags) != 0 {
+		t.Errorf("--any-build tags = %v; want none", f.LastQuery.Tags)
+	}
+	if _, err := run(t, st, "search", "x", "--sort", "best"); err == nil {
+		t.Error("unknown --sort should fail")
+	}
+}
+
+func TestParseSince(t *testing.T) {
+	now := time.Date(2024, 3, 10, 12, 0, 0, 0, time.UTC)
+	for in, want := range map[string]time.Time{
+		"30d": now.AddDate(0, 0, -30),
+		"12h": now.Add(-12 * time.Hour),
+	} {
+		if got, err := parseSince(in, now); err != nil || !got.Equal(want) {
+			t.Errorf("parseSince(%q) = %v, %v; want %v", in, got, err, want)
+		}
+	}
+	if _, err := parseSince("soon", now); err == nil {
+		t.Error("parseSince(soon) should fail")
+	}
+}
+
+func TestAuthorJSON(t *testing.T) {
+	st := testStore(t)
+	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef")
+	f := cannedFake()
+	for _, id := range []string{"100", "200"} {
+		it := f.Items[id]
+		it.Creator = "76561198000000001"
+		f.Items[id] = it
+	}
+	f.Names = map[string]string{"76561198000000001": "Alice"}
+	useFakeSteam(t, f)
+	ini := writeINI(t, "WorkshopItems=100\nMods=CoreLib\n")
+
+	out, err := run(t, st, "author", "200", "--file", ini, "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got authorJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if got.SteamID != "76561198000000001" || got.Name != "Alice" || len(got.Items) != 2 {
+		t.Fatalf("author = %+v", got)
+	}
+	if got.Items[0].CreatorName != "Alice" {
+		t.Errorf("item creator name = %q; want Alice", got.Items[0].CreatorName)
+	}
+	if !got.Items[0].Installed || got.Items[1].Installed {
+		t.Errorf("installed marks = %+v; want only 100", got.Items)
+	}
+}
+
+func TestValidateAgainstWorkshopSnapshot(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, cannedFake())
+	ini := writeINI(t, "WorkshopItems=200\nMods=Weapons\n")
+	ws := filepath.Join(t.TempDir(), "ws.json")
+
+	out, err := run(t, st, "workshop", "snapshot", "--file", ini, "--out", ws, "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var snap workshopSnapshotJSON
+	if err := json.Unmarshal([]byte(out), &snap); err != nil || snap.Items != 2 {
+		t.Fatalf("snapshot = %q (%v); want 2 items, 200 and its dependency 100", out, err)
+	}
+
+	// Steam is now unreachable; the snapshot alone must reproduce the report.
+	useFakeSteam(t, &steamtest.Fake{DetailsErr: errors.New("steam is down")})
+	out, err = run(t, st, "validate", "--file", ini, "--json", "--workshop-snapshot", ws)
+	if err == nil {
+		t.Fatalf("want a non-zero exit for the missing dependency; got\n%s", out)
+	}
+	var got validateJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	found := false
+	for _, f := range got.Findings {
+		found = found || (f.Code == "missing-dependency" && f.Subject == "100")
+	}
+	if !found || got.Incomplete {
+		t.Errorf("findings = %+v; want missing-dependency 100 from the snapshot", got.Findings)
+	}
+}
+
+func TestContentStatusJSON(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, cannedFake())
+	content := filepath.Join(t.TempDir(), "steamapps", "workshop", "content", "108600")
+	if err := os.MkdirAll(filepath.Join(content, "300", "mods", "Old"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	ini := writeINI(t, "WorkshopItems=100\nMods=CoreLib\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini, "--workshop-path", content); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, st, "content", "status", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got contentStatusJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if len(got.Missing) != 1 || got.Missing[0].ID != "100" || got.Missing[0].Title != "Core Library" {
+		t.Errorf("missing = %+v; want 100 (Core Library)", got.Missing)
+	}
+	if len(got.Unreferenced) != 1 || got.Unreferenced[0].ID != "300" {
+		t.Errorf("unreferenced = %+v; want 300", got.Unreferenced)
+	}
+}
+
+func TestBackupRestoreWithContent(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, cannedFake())
+	content := filepath.Join(t.TempDir(), "steamapps", "workshop", "content", "108600")
+	modFile := filepath.Join(content, "100", "mods", "CoreLib", "media", "lua", "core.lua")
+	if err := os.MkdirAll(filepath.Dir(modFile), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(modFile, []byte("v1"), 0644)
+	ini := writeINI(t, "WorkshopItems=100\nMods=CoreLib\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini,
+		"--workshop-path", content, "--archive-content"); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := run(t, st, "backup", "snapshot"); err != nil {
+		t.Fatal(err)
+	}
+	entries, err := st.Backups("alpha")
+	if err != nil || len(entries) != 1 || entries[0].ContentItems != 1 {
+		t.Fatalf("backups = %+v, %v; want one with content", entries, err)
+	}
+
+	os.WriteFile(modFile, []byte("v2"), 0644)
+	out, err := run(t, st, "backup", "restore", entries[0].ID, "--with-content", "--json")
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	var got backupRestoreJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if len(got.Content) != 1 || got.Content[0] != "100" {
+		t.Errorf("content = %v; want [100]", got.Content)
+	}
+	if data, _ := os.ReadFile(modFile); string(data) != "v1" {
+		t.Errorf("core.lua = %q; want v1", data)
+	}
+
+	if _, err := run(t, st, "backup", "restore", entries[0].ID, "--with-content", "--only", "mods"); err == nil {
+		t.Error("--with-content with --only should fail")
+	}
+}
+
+func TestValidateFromDisk(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, steamtest.New()) // the Workshop knows nothing; disk must answer
+	content := t.TempDir()
+	dir := filepath.Join(content, "100", "mods", "CoreLib", "42")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id=CoreLib\nrequire=\\Base\n"), 0644)
+	ini := writeINI(t, "WorkshopItems=100\nMods=\\CoreLib\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini,
+		"--workshop-path", content, "--build", "b42"); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, st, "validate", "--source", "disk", "--json")
+	if err == nil {
+		t.Fatal("a missing require should fail validation")
+	}
+	var got validateJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if len(got.Findings) != 1 || got.Findings[0].Code != "missing-require" || got.Findings[0].Subject != "Base" {
+		t.Errorf("findings = %+v; want only Base missing", got.Findings)
+	}
+
+	if _, err := run(t, st, "validate", "--source", "nowhere"); err == nil {
+		t.Error("an unknown --source should fail")
+	}
+}
+
+func TestValidateCountsLocalMods(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, cannedFake())
+	local := t.TempDir()
+	if err := os.MkdirAll(filepath.Join(local, "ServerTweaks"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(local, "ServerTweaks", "mod.info"), []byte("id=ServerTweaks\n"), 0644)
+	ini := writeINI(t, "WorkshopItems=100\nMods=CoreLib;ServerTweaks\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini, "--local-mods", local); err != nil {
+		t.Fatal(err)
+	}
+	if p, _ := st.Profile("alpha"); p.LocalModsPath != local {
+		t.Fatalf("LocalModsPath = %q; want %q", p.LocalModsPath, local)
+	}
+
+	out, err := run(t, st, "validate", "--json")
+	if err != nil {
+		t.Fatalf("%v: %s", err, out)
+	}
+	var got validateJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	for _, f := range got.Findings {
+		if f.Code == "unknown-mod-id" {
+			t.Errorf("local mod reported: %+v", f)
+		}
+	}
+}
+
+func TestModsResolveRequires(t *testing.T) {
+	st := testStore(t)
+	useFakeSteam(t, steamtest.New(
+		steam.WorkshopItem{Result: 1, FileType: steam.FileTypeMod, PublishedFileID: "200", Title: "Weapons", Description: "Mod ID: Weapons\n"},
+		steam.WorkshopItem{Result: 1, FileType: steam.FileTypeMod, PublishedFileID: "900", Title: "Ammo Pack", Description: "Mod ID: Ammo\n"},
+	))
+	content := t.TempDir()
+	dir := filepath.Join(content, "200", "mods", "Weapons")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id=Weapons\nrequire=\\Ammo,\\Nowhere\n"), 0644)
+	ini := writeINI(t, "WorkshopItems=200\nMods=Weapons\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini, "--workshop-path", content); err != nil {
+		t.Fatal(err)
+	}
+
+	out, err := run(t, st, "mods", "resolve", "--dry-run", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got resolveJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if !got.DryRun || !reflect.DeepEqual(got.AddWorkshopItems, []string{"900"}) ||
+		!reflect.DeepEqual(got.UnknownRequires, []string{"Nowhere"}) {
+		t.Errorf("plan = %+v; want item 900 added and Nowhere unknown", got)
+	}
+	if data, _ := os.ReadFile(ini); strings.Contains(string(data), "900") {
+		t.Error("--dry-run wrote the config")
+	}
+
+	if _, err := run(t, st, "mods", "resolve"); err != nil {
+		t.Fatal(err)
+	}
+	data, _ := os.ReadFile(ini)
+	if !strings.Contains(string(data), "WorkshopItems=200;900") || !strings.Contains(string(data), "Mods=Weapons;Ammo") {
+		t.Errorf("config after resolve:\n%s", data)
+	}
+}
+
+func TestModsPin(t *testing.T) {
+	st := testStore(t)
+	mk := func(id, title, mods string) steam.WorkshopItem {
+		return steam.WorkshopItem{Result: 1, FileType: steam.FileTypeMod, PublishedFileID: id, Title: title, Description: "Mod ID: " + mods + "\n"}
+	}
+	useFakeSteam(t, steamtest.New(mk("100", "Core Library", "CoreLib"), mk("110", "Core Library (fixed)", "CoreLib")))
+	ini := writeINI(t, "WorkshopItems=100;110\nMods=\\CoreLib\n")
+	if _, err := run(t, st, "profile", "add", "--name", "Alpha", "--file", ini, "--build", "b42"); err != nil {
+		t.Fatal(err)
+	}
+
+	if _, err := run(t, st, "mods", "pin", "Other", "100"); err == nil {
+		t.Error("pinning a mod that isn't enabled should fail")
+	}
+
+	// Without a terminal, naming the item is required.
+	if _, err := run(t, st, "mods", "pin", "CoreLib"); err == nil || !strings.Contains(err.Error(), "100, 110") {
+		t.Errorf("err = %v; want the declaring items listed", err)
+	}
+
+	out, err := run(t, st, "mods", "pin", "CoreLib", "110", "--drop-others", "--json")
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got pinJSON
+	if err := json.Unmarshal([]byte(out), &got); err != nil {
+		t.Fatalf("unmarshal %q: %v", out, err)
+	}
+	if got.Token != `110\CoreLib` || !reflect.DeepEqual(got.Dropped, []string{"100"}) {
+		t.Errorf("pin = %+v; want token 110\\CoreLib and 100 dropped", got)
+	}
+	data, _ := os.ReadFile(ini)
+	if !strings.Contains(string(data), "WorkshopItems=110\n") || !strings.Contains(string(data), `Mods=110\CoreLib`) {
+		t.Errorf("config after pin:\n%s", data)
+	}
+}
@@ -1,6 +1,11 @@
 package cli
 
 import (
+	"time"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
 	"github.com/kldzj/pzmod/pkg/service"
 	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/store"
@@ -29,6 +34,7 @@ type findingJSON struct {
 	Code     string `json:"code"`
 	Subject  string `json:"subject,omitempty"`
 	Message  string `json:"message"`
+	Hint     string `json:"hint,omitempty"`
 }
 
 // validateJSON is the shape of `validate --json`.
@@ -39,15 +45,32 @@ type validateJSON struct {
 		Warnings int `json:"warnings"`
 		Info     int `json:"info"`
 	} `json:"summary"`
-	OK bool `json:"ok"`
+	// Incomplete is set when Steam failed to answer for the Unchecked items;
+	// the findings cover the rest.
+	Incomplete bool     `json:"incomplete"`
+	Unchecked  []string `json:"unchecked"`
+	OK         bool     `json:"ok"`
 }
 
 // searchItemJSON is one Workshop search hit. It uses our own field names rather
 // than steam.WorkshopItem's Steam-API json tags.
 type searchItemJSON struct {
-	ID       string `json:"id"`
-	Title    string `json:"title"`
-	FileSize int64  `json:"fileSize"`
+	ID          string `json:"id"`
+	Title       string `json:"title"`
+	FileSize    int64  `json:"fileSize"`
+	Creator     string `json:"creator,omitempty"`     // SteamID64
+	CreatorName string `json:"creatorName,omitempty"` // persona name, when resolved
+}
+
+// newSearchItemJSON builds a searchItemJSON from a Workshop item.
+func newSearchItemJSON(it *steam.WorkshopItem) searchItemJSON {
+	return searchItemJSON{
+		ID:          it.PublishedFileID,
+		Title:       it.Title,
+		FileSize:    int64(it.FileSize),
+		Creator:     it.Creator,
+		CreatorName: it.CreatorName,
+	}
 }
 
 // searchJSON is the shape of `search --json`.
@@ -56,6 +79,20 @@ type searchJSON struct {
 	Items []searchItemJSON `json:"items"`
 }
 
+// authorItemJSON is one item in `author --json`.
+type authorItemJSON struct {
+	searchItemJSON
+	Installed bool `json:"installed"`
+}
+
+// authorJSON is the shape of `author --json`.
+type authorJSON struct {
+	SteamID string           `json:"steamId"`
+	Name    string           `json:"name,omitempty"`
+	Total   int              `json:"total"`
+	Items   []authorItemJSON `json:"items"`
+}
+
 // profileJSON embeds store.Profile (already json-tagged) and marks the default.
 type profileJSON struct {
 	store.Profile
@@ -73,22 +110,163 @@ type backupListJSON struct {
 	Backups []store.BackupEntry `json:"backups"`
 }
 
+// listChangeJSON mirrors domain.ListChange for output.
+type listChangeJSON struct {
+	Added     []string `json:"added"`
+	Removed   []string `json:"removed"`
+	Reordered bool     `json:"reordered"`
+}
+
+// keyChangeJSON is one changed key in `backup diff --json`. Change is "added",
+// "removed", or "changed"; password values are masked.
+type keyChangeJSON struct {
+	Key    string `json:"key"`
+	Change string `json:"change"`
+	Old    string `json:"old,omitempty"`
+	New    string `json:"new,omitempty"`
+}
+
+// backupDiffJSON is the shape of `backup diff --json`.
+type backupDiffJSON struct {
+	From          string          `json:"from"`
+	To            string          `json:"to"`
+	Identical     bool            `json:"identical"`
+	Mods          listChangeJSON  `json:"mods"`
+	WorkshopItems listChangeJSON  `json:"workshopItems"`
+	Maps          listChangeJSON  `json:"maps"`
+	Keys          []keyChangeJSON `json:"keys"`
+}
+
+func newListChangeJSON(c domain.ListChange) listChangeJSON {
+	out := listChangeJSON{Added: c.Added, Removed: c.Removed, Reordered: c.Reordered}
+	if out.Added == nil {
+		out.Added = []string{}
+	}
+	if out.Removed == nil {
+		out.Removed = []string{}
+	}
+	return out
+}
+
+func newBackupDiffJSON(from, to string, d serverconfig.Diff) backupDiffJSON {
+	out := backupDiffJSON{
+		From:          from,
+		To:            to,
+		Identical:     d.Empty(),
+		Mods:          newListChangeJSON(d.Mods),
+		WorkshopItems: newListChangeJSON(d.WorkshopItems),
+		Maps:          newListChangeJSON(d.Maps),
+		Keys:          []keyChangeJSON{},
+	}
+	for _, k := range d.Keys {
+		change := "changed"
+		if k.Added {
+			change = "added"
+		} else if k.Removed {
+			change = "removed"
+		}
+		out.Keys = append(out.Keys, keyChangeJSON{Key: k.Key, Change: change, Old: maskSecret(k.Key, k.Old), New: maskSecret(k.Key, k.New)})
+	}
+	return out
+}
+
+// backupRestoreJSON is the shape of `backup restore --only --json`.
+type backupRestoreJSON struct {
+	Restored string   `json:"restored"`
+	Partial  bool     `json:"partial"`
+	Changed  bool     `json:"changed"`
+	Missing  []string `json:"missing"`
+	Content  []string `json:"content,omitempty"` // item folders restored by --with-content
+}
+
+// backupExportJSON is the shape of `backup export --json`.
+type backupExportJSON struct {
+	Path      string `json:"path"`
+	Backups   int    `json:"backups"`
+	Encrypted bool   `json:"encrypted"`
+}
+
+// backupImportJSON is the shape of `backup import --json`.
+type backupImportJSON struct {
+	Profile        string `json:"profile"`
+	ProfileCreated bool   `json:"profileCreated"`
+	Imported       int    `json:"imported"`
+	Skipped        int    `json:"skipped"`
+}
+
+// historyEntryJSON is one entry of `history --json`.
+type historyEntryJSON struct {
+	store.JournalEntry
+	Undone bool `json:"undone"`
+}
+
+// historyJSON is the shape of `history --json`, newest first.
+type historyJSON struct {
+	Entries []historyEntryJSON `json:"entries"`
+}
+
+// undoJSON is the shape of `undo --json`.
+type undoJSON struct {
+	Entry    store.JournalEntry   `json:"entry"`
+	Reverted []store.JournalEntry `json:"reverted"`
+}
+
+// workshopSnapshotJSON is the shape of `workshop snapshot --json`.
+type workshopSnapshotJSON struct {
+	Path    string   `json:"path"`
+	Items   int      `json:"items"`
+	Missing []string `json:"missing"`
+}
+
+// downloadJSON is the shape of `download --json`.
+type downloadJSON struct {
+	ContentPath string            `json:"contentPath"`
+	Downloaded  []string          `json:"downloaded"`
+	Failed      map[string]string `json:"failed"`
+	Pruned      []string          `json:"pruned"`
+}
+
+// contentItemJSON mirrors service.ContentItem for output.
+type contentItemJSON struct {
+	ID        string     `json:"id"`
+	Title     string     `json:"title,omitempty"`
+	Size      int64      `json:"size"`
+	Installed *time.Time `json:"installed,omitempty"`
+	Workshop  *time.Time `json:"workshopUpdated,omitempty"`
+}
+
+// contentStatusJSON is the shape of `content status --json`.
+type contentStatusJSON struct {
+	ContentPath  string            `json:"contentPath"`
+	Manifest     string            `json:"manifest,omitempty"`
+	Missing      []contentItemJSON `json:"missing"`
+	Outdated     []contentItemJSON `json:"outdated"`
+	Unreferenced []contentItemJSON `json:"unreferenced"`
+	Current      int               `json:"current"`
+	Unchecked    []string          `json:"unchecked"`
+}
+
 // multiModJSON mirrors domain.MultiModItem for output.
 type multiModJSON struct {
 	ItemID string   `json:"itemId"`
 	ModIDs []string `json:"modIds"`
 }
 
-// resolveJSON is the shape of `mods add --resolve-deps --json`.
+// resolveJSON is the shape of `mods add --resolve-deps --json` and
+// `mods resolve --json`.
 type resolveJSON struct {
-	Resolved         bool           `json:"resolved"`
-	DryRun           bool           `json:"dryRun"`
-	AddWorkshopItems []string       `json:"addWorkshopItems"`
-	AddMods          []string       `json:"addMods"`
-	AddMaps          []string       `json:"addMaps"`
-	Missing          []string       `json:"missing"`
-	MultiMod         []multiModJSON `json:"multiMod"`
-	Cycles           [][]string     `json:"cycles"`
+	Resolved         bool                `json:"resolved"`
+	DryRun           bool                `json:"dryRun"`
+	AddWorkshopItems []string            `json:"addWorkshopItems"`
+	AddMods          []string            `json:"addMods"`
+	AddMaps          []string            `json:"addMaps"`
+	Missing          []string            `json:"missing"`
+	Unresolved       []string            `json:"unresolved"`
+	MultiMod         []multiModJSON      `json:"multiMod"`
+	Cycles           [][]string          `json:"cycles"`
+	UnknownRequires  []string            `json:"unknownRequires"`
+	Clashes          map[string][]string `json:"clashes"`
+	SearchError      string              `json:"searchError,omitempty"` // why the Workshop couldn't be searched for unknownRequires
 }
 
 // newResolveJSON builds a resolveJSON from a resolution plan.
@@ -101,17 +279,35 @@ func newResolveJSON(plan service.ResolvePlan) resolveJSON {
 	if cycles == nil {
 		cycles = [][]string{}
 	}
+	clashes := plan.Clashes
+	if clashes == nil {
+		clashes = map[string][]string{}
+	}
 	return resolveJSON{
 		Resolved:         true,
 		AddWorkshopItems: orEmpty(plan.AddWorkshopItems),
 		AddMods:          orEmpty(plan.AddMods),
 		AddMaps:          orEmpty(plan.AddMaps),
 		Missing:          orEmpty(plan.Missing),
+		Unresolved:       orEmpty(plan.Unresolved),
 		MultiMod:         mm,
 		Cycles:           cycles,
+		UnknownRequires:  orEmpty(plan.UnknownRequires),
+		Clashes:          clashes,
+		SearchError:      searchHint(plan.SearchErr),
 	}
 }
 
+// pinJSON is the shape of `mods pin --json`.
+type pinJSON struct {
+	ModID    string   `json:"modId"`
+	Provider string   `json:"provider"`
+	Token    string   `json:"token"` // the resulting Mods= entry
+	Dropped  []string `json:"dropped"`
+	Kept     []string `json:"kept"` // losing items left installed: they provide other enabled mods or maps
+	DryRun   bool     `json:"dryRun"`
+}
+
 // shallowAddJSON is the shape of `mods add --json` without --resolve-deps.
 type shallowAddJSON struct {
 	Added   []string `json:"added"`
@@ -130,6 +326,13 @@ type modShowJSON struct {
 	MapFolders  []string `json:"mapFolders"`
 	ChildIDs    []string `json:"childIds"`
 	Description string   `json:"description"`
+	Creator     string   `json:"creator,omitempty"`
+	CreatorName string   `json:"creatorName,omitempty"`
+	Stale       bool     `json:"stale,omitempty"` // served from cache past its TTL
+
+	// ModInfoDirs maps each downloaded mod's ID to the Build 42 version folder
+	// its mod.info was read from ("" for the mod root).
+	ModInfoDirs map[string]string `json:"modInfoDirs,omitempty"`
 }
 
 // modShowResultJSON is the shape of `mods show --json`.
@@ -138,13 +341,21 @@ type modShowResultJSON struct {
 	Missing []string      `json:"missing"`
 }
 
-// newModShowJSON builds a modShowJSON from a fetched Workshop item.
-func newModShowJSON(it *steam.WorkshopItem) modShowJSON {
+// newModShowJSON builds a modShowJSON from a fetched Workshop item and the
+// mod.info files read from its folder.
+func newModShowJSON(it *steam.WorkshopItem, infos []modinfo.ModInfo) modShowJSON {
 	parsed := it.Parse()
 	typ := "mod"
 	if it.IsCollection() {
 		typ = "collection"
 	}
+	var dirs map[string]string
+	for _, mi := range infos {
+		if dirs == nil {
+			dirs = map[string]string{}
+		}
+		dirs[mi.ID] = mi.VersionDir
+	}
 	return modShowJSON{
 		ID:          it.PublishedFileID,
 		Title:       it.Title,
@@ -154,6 +365,10 @@ func newModShowJSON(it *steam.WorkshopItem) modShowJSON {
 		MapFolders:  orEmpty(parsed.Maps),
 		ChildIDs:    orEmpty(it.GetChildIDs()),
 		Description: it.Description,
+		Creator:     it.Creator,
+		CreatorName: it.CreatorName,
+		Stale:       it.Stale,
+		ModInfoDirs: dirs,
 	}
 }
 
@@ -2,15 +2,20 @@ package cli
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"os"
 	"strings"
 
+	"github.com/charmbracelet/huh"
 	"github.com/dustin/go-humanize"
 	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
 	"github.com/kldzj/pzmod/pkg/service"
 	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/mattn/go-isatty"
 	"github.com/spf13/cobra"
 )
 
@@ -19,7 +24,7 @@ func newModsCmd(st *store.Store) *cobra.Command {
 		Use:   "mods",
 		Short: "List, add, and remove mods",
 	}
-	cmd.AddCommand(newModsListCmd(st), newModsAddCmd(st), newModsRemoveCmd(st), newModsShowCmd(st))
+	cmd.AddCommand(newModsListCmd(st), newModsAddCmd(st), newModsRemoveCmd(st), newModsShowCmd(st), newModsResolveCmd(st), newModsPinCmd(st))
 	return cmd
 }
 
@@ -73,10 +78,7 @@ func newModsAddCmd(st *store.Store) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			svc, err := t.servicesWithSteam(st)
-			if err != nil {
-				return err
-			}
+			svc := t.services(st)
 			cfg, err := t.config()
 			if err != nil {
 				return err
@@ -134,12 +136,7 @@ func newModsAddCmd(st *store.Store) *cobra.Command {
 			}
 
 			cfg.ApplyServerMods(projected)
-			if noBackup, _ := cmd.Flags().GetBool("no-backup"); !noBackup {
-				if _, err := svc.SnapshotProfile(t.profile, "before mods add", "auto"); err != nil {
-					return err
-				}
-			}
-			if err := cfg.Save(); err != nil {
+			if _, err := svc.ApplyChange(t.profile, cfg, journalOp(cmd, args, "before mods add")); err != nil {
 				return err
 			}
 			if asJSON {
@@ -191,12 +188,7 @@ func newModsRemoveCmd(st *store.Store) *cobra.Command {
 			}
 
 			cfg.ApplyServerMods(after)
-			if noBackup, _ := cmd.Flags().GetBool("no-backup"); !noBackup {
-				if _, err := t.services(st).SnapshotProfile(t.profile, "before mods remove", "auto"); err != nil {
-					return err
-				}
-			}
-			if err := cfg.Save(); err != nil {
+			if _, err := t.services(st).ApplyChange(t.profile, cfg, journalOp(cmd, args, "before mods remove")); err != nil {
 				return err
 			}
 			if jsonEnabled(cmd) {
@@ -251,12 +243,232 @@ func shallowAdd(ctx context.Context, svc *service.Services, sm domain.ServerMods
 	return sm, missing, content, nil
 }
 
+func newModsResolveCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "resolve [mod-id...]",
+		Short: "Add what enabled mods' mod.info require= lists but nothing provides",
+		Long: `Reads the mod.info of each enabled mod and satisfies the required mod IDs
+nothing enabled provides, including those the Workshop page doesn't list as
+required items. A mod an installed item or local mod already provides is just
+enabled; otherwise the Workshop is searched for an item declaring it, and that
+item is added with its dependencies. Give mod IDs to resolve only those.`,
+		Example: `  pzmod mods resolve --dry-run
+  pzmod mods resolve tsarslib`,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			svc := t.services(st)
+			cfg, err := t.config()
+			if err != nil {
+				return err
+			}
+			sm := cfg.ServerMods()
+			plan, err := svc.ResolveRequires(cmd.Context(), t.profile, sm, args)
+			if err != nil {
+				return err
+			}
+
+			asJSON := jsonEnabled(cmd)
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			rj := newResolveJSON(plan)
+			rj.DryRun = dryRun
+			if !asJSON {
+				printPlan(cmd, plan)
+			}
+			if dryRun || plan.Empty() {
+				if asJSON {
+					return emitJSON(cmd, rj)
+				}
+				if dryRun {
+					cmd.Println(styleMuted.Render("dry run: nothing written"))
+				}
+				return nil
+			}
+
+			cfg.ApplyServerMods(plan.Apply(sm, t.build() == build.B42))
+			if _, err := svc.ApplyChange(t.profile, cfg, journalOp(cmd, args, "before mods resolve")); err != nil {
+				return err
+			}
+			if asJSON {
+				return emitJSON(cmd, rj)
+			}
+			return nil
+		},
+	}
+	cmd.Flags().Bool("no-backup", false, "do not snapshot before saving")
+	cmd.Flags().Bool("dry-run", false, "resolve and show the plan without writing")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// searchHint explains a failed Workshop search in a resolution plan.
+func searchHint(err error) string {
+	switch {
+	case err == nil:
+		return ""
+	case errors.Is(err, steam.ErrSearchNeedsKey):
+		return "searching the Workshop needs a Steam API key - run `pzmod api-key <key>`"
+	default:
+		return "Workshop search failed: " + err.Error()
+	}
+}
+
+// pickProvider asks which of a clash's items should provide its mod ID. It is
+// a package var so tests can answer without a terminal.
+var pickProvider = func(c service.Clash) (string, error) {
+	opts := make([]huh.Option[string], 0, len(c.Items))
+	for _, it := range c.Items {
+		label := fmt.Sprintf("%s (%s)", it.Title, it.PublishedFileID)
+		if it.PublishedFileID == c.Pinned {
+			label += " - pinned"
+		}
+		opts = append(opts, huh.NewOption(label, it.PublishedFileID))
+	}
+	var choice string
+	err := huh.NewSelect[string]().
+		Title("Which item should provide " + c.ModID + "?").
+		Options(opts...).
+		Value(&choice).
+		Run()
+	return choice, err
+}
+
+// isTerminal reports whether f is an interactive terminal.
+func isTerminal(f *os.File) bool {
+	return isatty.IsTerminal(f.Fd()) || isatty.IsCygwinTerminal(f.Fd())
+}
+
+func newModsPinCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "pin <mod-id> [workshop-id]",
+		Short: "Choose which Workshop item provides a mod ID several items declare",
+		Long: `Forks and re-uploads often declare the same mod ID as the original. pin
+makes one installed item its provider: on Build 42 the Mods= entry becomes
+the pinned workshop\modID form, and with --drop-others the other items
+declaring it are removed, unless they also provide another enabled mod or
+map. Without a workshop ID you are asked to pick one.`,
+		Example: `  pzmod mods pin Brita              # pick from the declaring items
+  pzmod mods pin Brita 2200148440 --drop-others`,
+		Args: cobra.RangeArgs(1, 2),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			svc := t.services(st)
+			cfg, err := t.config()
+			if err != nil {
+				return err
+			}
+			sm := cfg.ServerMods()
+			modID := args[0]
+			if !sm.HasMod(modID) {
+				return fmt.Errorf("mod ID %s is not enabled in Mods=; enable it before choosing its provider", modID)
+			}
+
+			item := ""
+			if len(args) == 2 {
+				item = args[1]
+			} else {
+				clashes, err := svc.Clashes(cmd.Context(), sm)
+				if err != nil {
+					return err
+				}
+				var clash *service.Clash
+				for i := range clashes {
+					if clashes[i].ModID == modID {
+						clash = &clashes[i]
+					}
+				}
+				if clash == nil {
+					return fmt.Errorf("mod ID %s is not declared by more than one installed item", modID)
+				}
+				if jsonEnabled(cmd) || !isTerminal(os.Stdin) {
+					ids := make([]string, len(clash.Items))
+					for i, it := range clash.Items {
+						ids[i] = it.PublishedFileID
+					}
+					return fmt.Errorf("mod ID %s is declared by %s; name the one to keep: pzmod mods pin %s <workshop-id>",
+						modID, strings.Join(ids, ", "), modID)
+				}
+				if item, err = pickProvider(*clash); err != nil {
+					return err
+				}
+			}
+
+			drop, _ := cmd.Flags().GetBool("drop-others")
+			plan, err := svc.PlanProvider(cmd.Context(), sm, modID, item, drop)
+			if err != nil {
+				return err
+			}
+			explicit := t.build() == build.B42
+			after := plan.Apply(sm, explicit)
+			dryRun, _ := cmd.Flags().GetBool("dry-run")
+			out := pinJSON{
+				ModID:    modID,
+				Provider: item,
+				Token:    domain.FormatModRef(item, modID, explicit),
+				Dropped:  orEmpty(plan.Drop),
+				Kept:     orEmpty(plan.Kept),
+				DryRun:   dryRun,
+			}
+			if dryRun {
+				if jsonEnabled(cmd) {
+					return emitJSON(cmd, out)
+				}
+				printPin(cmd, plan, explicit, "would pin")
+				cmd.Println(styleMuted.Render("dry run: nothing written"))
+				return nil
+			}
+
+			cfg.ApplyServerMods(after)
+			if _, err := svc.ApplyChange(t.profile, cfg, journalOp(cmd, args, "before mods pin")); err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, out)
+			}
+			printPin(cmd, plan, explicit, "pinned")
+			return nil
+		},
+	}
+	cmd.Flags().Bool("drop-others", false, "remove the other items declaring the mod ID")
+	cmd.Flags().Bool("no-backup", false, "do not snapshot before saving")
+	cmd.Flags().Bool("dry-run", false, "show the change without writing")
+	addTargetFlags(cmd)
+	return cmd
+}
+
+// printPin describes a provider choice; verb is "pinned" or "would pin".
+func printPin(cmd *cobra.Command, plan domain.ProviderPlan, explicit bool, verb string) {
+	cmd.Printf("%s %s to item %s\n", verb, plan.ModID, plan.Provider)
+	if !explicit {
+		cmd.Println(styleMuted.Render("Build 41 has no pinned Mods= form; drop the other items to settle the clash"))
+	}
+	if len(plan.Drop) > 0 {
+		label := "dropped:"
+		if verb != "pinned" {
+			label = "would drop:"
+		}
+		cmd.Println(styleInfo.Render(label), strings.Join(plan.Drop, ", "))
+	}
+	if len(plan.Kept) > 0 {
+		cmd.Println(styleWarn.Render("kept (they provide other enabled mods or maps):"), strings.Join(plan.Kept, ", "))
+	}
+}
+
 func printPlan(cmd *cobra.Command, plan service.ResolvePlan) {
 	cmd.Printf("resolved: +%d items, +%d mods, +%d maps\n",
 		len(plan.AddWorkshopItems), len(plan.AddMods), len(plan.AddMaps))
 	if len(plan.Missing) > 0 {
 		cmd.Println(styleWarn.Render("missing:"), strings.Join(plan.Missing, ", "))
 	}
+	if len(plan.Unresolved) > 0 {
+		cmd.Println(styleWarn.Render("steam did not answer for:"), strings.Join(plan.Unresolved, ", "),
+			styleMuted.Render("(not added; their dependencies are unknown)"))
+	}
 	for _, mm := range plan.MultiMod {
 		cmd.Printf("%s item %s declares multiple mods: %s\n",
 			styleWarn.Render("note:"), mm.ItemID, strings.Join(mm.ModIDs, ", "))
@@ -264,6 +476,18 @@ func printPlan(cmd *cobra.Command, plan service.ResolvePlan) {
 	if len(plan.Cycles) > 0 {
 		cmd.Println(styleWarn.Render(fmt.Sprintf("%d dependency cycle(s) detected", len(plan.Cycles))))
 	}
+	if len(plan.UnknownRequires) > 0 {
+		cmd.Println(styleWarn.Render("no Workshop item found for required mods:"), strings.Join(plan.UnknownRequires, ", "))
+		if hint := searchHint(plan.SearchErr); hint != "" {
+			cmd.Println(styleMuted.Render(hint))
+		}
+	}
+	for _, mod := range plan.AddMods {
+		if items := plan.Clashes[mod]; len(items) > 0 {
+			cmd.Printf("%s mod %s is declared by %s; using %s (choose with `pzmod mods pin %s`)\n",
+				styleWarn.Render("note:"), mod, strings.Join(items, ", "), plan.AddModSources[mod], mod)
+		}
+	}
 }
 
 func newModsShowCmd(st *store.Store) *cobra.Command {
@@ -273,28 +497,33 @@ func newModsShowCmd(st *store.Store) *cobra.Command {
 		Args:  cobra.MinimumNArgs(1),
 		RunE: func(cmd *cobra.Command, args []string) error {
 			profile, _ := cmd.Flags().GetString("profile")
-			if !st.HasAPIKey(profile) {
-				return errNoKey
-			}
-			key, _ := st.APIKey(profile)
-			svc := service.New(steamFactory(key), st)
+			svc := service.New(newSteam(cmd, st, profile), st)
 
 			items, missing, err := svc.Details(cmd.Context(), args)
 			if err != nil {
 				return err
 			}
+			svc.NameCreators(cmd.Context(), items)
+			// Downloaded items also show which mod.info was read, from the
+			// profile's Workshop content.
+			infos := make([][]modinfo.ModInfo, len(items))
+			if p, err := showProfile(st, profile); err == nil {
+				for i := range items {
+					infos[i] = svc.ItemModInfos(p, items[i])
+				}
+			}
 			if jsonEnabled(cmd) {
 				out := modShowResultJSON{
 					Items:   make([]modShowJSON, 0, len(items)),
 					Missing: orEmpty(missing),
 				}
 				for i := range items {
-					out.Items = append(out.Items, newModShowJSON(&items[i]))
+					out.Items = append(out.Items, newModShowJSON(&items[i], infos[i]))
 				}
 				return emitJSON(cmd, out)
 			}
 			for i := range items {
-				printModDetails(cmd, &items[i])
+				printModDetails(cmd, &items[i], infos[i])
 			}
 			if len(missing) > 0 {
 				cmd.Println(styleWarn.Render("could not fetch:"), strings.Join(missing, ", "))
@@ -303,21 +532,48 @@ func newModsShowCmd(st *store.Store) *cobra.Command {
 		},
 	}
 	cmd.ValidArgsFunction = completeInstalledIDs(st)
-	cmd.Flags().StringP("profile", "p", "", "use a profile's API key")
+	cmd.Flags().StringP("profile", "p", "", "use a profile's API key and Workshop content")
 	return cmd
 }
 
-func printModDetails(cmd *cobra.Command, it *steam.WorkshopItem) {
+// showProfile is the named profile, or the default one.
+func showProfile(st *store.Store, id string) (store.Profile, error) {
+	if id != "" {
+		return st.Profile(id)
+	}
+	return st.DefaultProfile()
+}
+
+// modInfoSources lists where each mod's mod.info was read from.
+func modInfoSources(infos []modinfo.ModInfo) string {
+	parts := make([]string, len(infos))
+	for i, mi := range infos {
+		parts[i] = mi.ID + " from " + mi.Origin()
+	}
+	return strings.Join(parts, ", ")
+}
+
+func printModDetails(cmd *cobra.Command, it *steam.WorkshopItem, infos []modinfo.ModInfo) {
 	parsed := it.Parse()
 	kind := "mod"
 	if it.IsCollection() {
 		kind = "collection"
 	}
-	cmd.Printf("%s  %s\n", styleInfo.Render(it.PublishedFileID), it.Title)
+	stale := ""
+	if it.Stale {
+		stale = styleMuted.Render("  (cached, may be stale)")
+	}
+	cmd.Printf("%s  %s%s\n", styleInfo.Render(it.PublishedFileID), it.Title, stale)
 	cmd.Printf("  type: %s   size: %s\n", kind, humanize.Bytes(uint64(it.FileSize)))
+	if it.CreatorName != "" {
+		cmd.Printf("  by: %s %s\n", it.CreatorName, styleMuted.Render("("+it.Creator+")"))
+	}
 	if len(parsed.Mods) > 0 {
 		cmd.Printf("  mod ids: %s\n", strings.Join(parsed.Mods, ", "))
 	}
+	if len(infos) > 0 {
+		cmd.Printf("  mod.info: %s\n", modInfoSources(infos))
+	}
 	if len(parsed.Maps) > 0 {
 		cmd.Printf("  maps: %s\n", strings.Join(parsed.Maps, ", "))
 	}
@@ -0,0 +1,153 @@
+package cli
+
+import (
+	"strings"
+
+	"github.com/kldzj/pzmod/internal/pathutil"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+func newNetworkCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "network",
+		Short: "Show or set the proxy, CA certificates, timeout, and Steam API URL",
+		Long: "Shows or sets how pzmod reaches the Steam Web API. Without flags it prints the\n" +
+			"settings in effect. With --profile, changes apply to that profile only and\n" +
+			"override the global ones field by field. Pass an empty value (--proxy \"\")\n" +
+			"to unset a field. With no proxy set, HTTPS_PROXY/HTTP_PROXY are honoured.",
+		Example: "  pzmod network --proxy http://proxy.corp:3128 --ca-cert ~/corp-root.pem\n" +
+			"  pzmod network --profile main --timeout 90s\n" +
+			"  pzmod network --clear",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			profile, _ := cmd.Flags().GetString("profile")
+			clear, _ := cmd.Flags().GetBool("clear")
+
+			if clear || networkFlagsChanged(cmd) {
+				var (
+					n   store.Network
+					err error
+				)
+				if profile != "" {
+					var p store.Profile
+					if p, err = st.Profile(profile); err != nil {
+						return err
+					}
+					if p.Network != nil {
+						n = *p.Network
+					}
+				} else if n, err = st.GlobalNetwork(); err != nil {
+					return err
+				}
+				if clear {
+					n = store.Network{}
+				}
+				applyNetworkFlags(cmd, &n)
+				if err := saveNetwork(st, profile, n); err != nil {
+					return err
+				}
+				if !jsonEnabled(cmd) {
+					cmd.Println(styleOK.Render("network settings saved"))
+				}
+			}
+
+			eff, err := st.Network(profile)
+			if err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, eff.Redacted())
+			}
+			printNetwork(cmd, eff.Redacted())
+			return nil
+		},
+	}
+	addNetworkFlags(cmd)
+	cmd.Flags().BoolP("clear", "c", false, "unset every field (before applying any other flags)")
+	cmd.Flags().StringP("profile", "p", "", "change a profile's override instead of the global settings")
+	return cmd
+}
+
+// saveNetwork stores n globally, or as profile's override.
+func saveNetwork(st *store.Store, profile string, n store.Network) error {
+	if profile == "" {
+		return st.SetGlobalNetwork(n)
+	}
+	if err := n.Validate(); err != nil {
+		return err
+	}
+	p, err := st.Profile(profile)
+	if err != nil {
+		return err
+	}
+	p.Network = nil
+	if !n.IsZero() {
+		p.Network = &n
+	}
+	return st.UpdateProfile(p)
+}
+
+// addNetworkFlags adds the Steam connection flags shared by `network` and
+// `profile add/edit`.
+func addNetworkFlags(cmd *cobra.Command) {
+	cmd.Flags().String("proxy", "", "HTTP(S) or SOCKS5 proxy URL for Steam requests")
+	cmd.Flags().StringArray("ca-cert", nil, "extra PEM CA certificate file to trust (repeatable)")
+	cmd.Flags().String("timeout", "", "per-request timeout, e.g. 45s")
+	cmd.Flags().String("base-url", "", "Steam Web API base URL (a mirror or gateway)")
+	cmd.MarkFlagFilename("ca-cert", "pem", "crt")
+}
+
+func networkFlagsChanged(cmd *cobra.Command) bool {
+	for _, f := range []string{"proxy", "ca-cert", "timeout", "base-url"} {
+		if cmd.Flags().Changed(f) {
+			return true
+		}
+	}
+	return false
+}
+
+// applyNetworkFlags copies the network flags that were given onto n. CA paths
+// are expanded and made absolute, since pzmod may run from anywhere.
+func applyNetworkFlags(cmd *cobra.Command, n *store.Network) {
+	if cmd.Flags().Changed("proxy") {
+		n.Proxy, _ = cmd.Flags().GetString("proxy")
+	}
+	if cmd.Flags().Changed("ca-cert") {
+		files, _ := cmd.Flags().GetStringArray("ca-cert")
+		n.CAFiles = nil
+		for _, f := range files {
+			if f = strings.TrimSpace(f); f != "" {
+				n.CAFiles = append(n.CAFiles, pathutil.Expand(f))
+			}
+		}
+	}
+	if cmd.Flags().Changed("timeout") {
+		n.Timeout, _ = cmd.Flags().GetString("timeout")
+	}
+	if cmd.Flags().Changed("base-url") {
+		n.BaseURL, _ = cmd.Flags().GetString("base-url")
+	}
+}
+
+func printNetwork(cmd *cobra.Command, n store.Network) {
+	unset := styleMuted.Render("(default)")
+	or := func(v string) string {
+		if v == "" {
+			return unset
+		}
+		return v
+	}
+	cas := unset
+	if len(n.CAFiles) > 0 {
+		abbr := make([]string, len(n.CAFiles))
+		for i, f := range n.CAFiles {
+			abbr[i] = pathutil.Abbreviate(f)
+		}
+		cas = strings.Join(abbr, ", ")
+	}
+	cmd.Printf("Proxy:    %s\n", or(n.Proxy))
+	cmd.Printf("CA certs: %s\n", cas)
+	cmd.Printf("Timeout:  %s\n", or(n.Timeout))
+	cmd.Printf("Base URL: %s\n", or(n.BaseURL))
+}
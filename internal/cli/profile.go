@@ -16,6 +16,7 @@ func newProfileCmd(st *store.Store) *cobra.Command {
 	cmd.AddCommand(
 		newProfileListCmd(st),
 		newProfileAddCmd(st),
+		newProfileEditCmd(st),
 		newProfileRemoveCmd(st),
 		newProfileUseCmd(st),
 		newProfileShowCmd(st),
@@ -37,7 +38,7 @@ func newProfileListCmd(st *store.Store) *cobra.Command {
 			if jsonEnabled(cmd) {
 				out := profileListJSON{Profiles: make([]profileJSON, 0, len(profiles)), DefaultID: def.ID}
 				for _, p := range profiles {
-					out.Profiles = append(out.Profiles, profileJSON{Profile: p, Default: p.ID == def.ID})
+					out.Profiles = append(out.Profiles, profileJSON{Profile: p.Redacted(), Default: p.ID == def.ID})
 				}
 				return emitJSON(cmd, out)
 			}
@@ -77,17 +78,36 @@ func newProfileAddCmd(st *store.Store) *cobra.Command {
 			if workshop != "" {
 				workshop = pathutil.Expand(workshop)
 			}
-			p, err := st.AddProfile(store.Profile{
+			local, _ := cmd.Flags().GetString("local-mods")
+			if local != "" {
+				local = pathutil.Expand(local)
+			}
+			var n store.Network
+			applyNetworkFlags(cmd, &n)
+			if err := n.Validate(); err != nil {
+				return err
+			}
+			archive, _ := cmd.Flags().GetBool("archive-content")
+			if archive && workshop == "" {
+				return fmt.Errorf("--archive-content needs --workshop-path")
+			}
+			p := store.Profile{
 				Name:                name,
 				IniPath:             file,
 				Build:               buildStr,
 				WorkshopContentPath: workshop,
-			})
+				LocalModsPath:       local,
+				ArchiveContent:      archive,
+			}
+			if !n.IsZero() {
+				p.Network = &n
+			}
+			p, err := st.AddProfile(p)
 			if err != nil {
 				return err
 			}
 			if jsonEnabled(cmd) {
-				return emitJSON(cmd, p)
+				return emitJSON(cmd, p.Redacted())
 			}
 			cmd.Println(styleOK.Render("added profile"), p.ID)
 			return nil
@@ -97,6 +117,92 @@ func newProfileAddCmd(st *store.Store) *cobra.Command {
 	cmd.Flags().StringP("file", "f", "", "path to servertest.ini")
 	cmd.Flags().String("build", "", "game build: b41 or b42")
 	cmd.Flags().String("workshop-path", "", "optional Workshop content dir for mod.info enrichment")
+	cmd.Flags().String("local-mods", "", "optional local mods dir (Zomboid/mods) for mods installed without the Workshop")
+	cmd.Flags().Bool("archive-content", false, "archive the Workshop content with every backup, for full rollback")
+	addNetworkFlags(cmd)
+	return cmd
+}
+
+func newProfileEditCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "edit <id>",
+		Short: "Change a profile's settings",
+		Long: "Changes only the settings whose flags are given. Pass an empty value\n" +
+			"(--proxy \"\") to unset a network setting and fall back to the global one.",
+		Example: "  pzmod profile edit main --build b42\n" +
+			"  pzmod profile edit main --proxy http://proxy.corp:3128 --ca-cert corp-root.pem",
+		Args: cobra.ExactArgs(1),
+		RunE: func(cmd *cobra.Command, args []string) error {
+			p, err := st.Profile(args[0])
+			if err != nil {
+				return err
+			}
+			if cmd.Flags().Changed("name") {
+				p.Name, _ = cmd.Flags().GetString("name")
+			}
+			if cmd.Flags().Changed("file") {
+				file, _ := cmd.Flags().GetString("file")
+				file = pathutil.Expand(file)
+				if !pathutil.FileExists(file) {
+					return fmt.Errorf("config file not found: %s", file)
+				}
+				p.IniPath = file
+			}
+			if cmd.Flags().Changed("build") {
+				p.Build, _ = cmd.Flags().GetString("build")
+			}
+			if cmd.Flags().Changed("workshop-path") {
+				workshop, _ := cmd.Flags().GetString("workshop-path")
+				if workshop != "" {
+					workshop = pathutil.Expand(workshop)
+				}
+				p.WorkshopContentPath = workshop
+			}
+			if cmd.Flags().Changed("local-mods") {
+				local, _ := cmd.Flags().GetString("local-mods")
+				if local != "" {
+					local = pathutil.Expand(local)
+				}
+				p.LocalModsPath = local
+			}
+			if cmd.Flags().Changed("archive-content") {
+				p.ArchiveContent, _ = cmd.Flags().GetBool("archive-content")
+			}
+			if p.ArchiveContent && p.WorkshopContentPath == "" {
+				return fmt.Errorf("--archive-content needs a Workshop content path (--workshop-path)")
+			}
+			if networkFlagsChanged(cmd) {
+				var n store.Network
+				if p.Network != nil {
+					n = *p.Network
+				}
+				applyNetworkFlags(cmd, &n)
+				if err := n.Validate(); err != nil {
+					return err
+				}
+				p.Network = nil
+				if !n.IsZero() {
+					p.Network = &n
+				}
+			}
+			if err := st.UpdateProfile(p); err != nil {
+				return err
+			}
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, p.Redacted())
+			}
+			cmd.Println(styleOK.Render("updated profile"), p.ID)
+			return nil
+		},
+	}
+	cmd.Flags().String("name", "", "display name")
+	cmd.Flags().StringP("file", "f", "", "path to servertest.ini")
+	cmd.Flags().String("build", "", "game build: b41 or b42")
+	cmd.Flags().String("workshop-path", "", "Workshop content dir for mod.info enrichment")
+	cmd.Flags().String("local-mods", "", "local mods dir (Zomboid/mods) for mods installed without the Workshop")
+	cmd.Flags().Bool("archive-content", false, "archive the Workshop content with every backup, for full rollback")
+	addNetworkFlags(cmd)
+	cmd.ValidArgsFunction = completeProfiles(st)
 	return cmd
 }
 
@@ -158,7 +264,7 @@ func newProfileShowCmd(st *store.Store) *cobra.Command {
 			}
 			if jsonEnabled(cmd) {
 				def, _ := st.DefaultProfile()
-				return emitJSON(cmd, profileJSON{Profile: p, Default: p.ID == def.ID})
+				return emitJSON(cmd, profileJSON{Profile: p.Redacted(), Default: p.ID == def.ID})
 			}
 			cmd.Printf("ID:            %s\n", p.ID)
 			cmd.Printf("Name:          %s\n", p.Name)
@@ -167,6 +273,28 @@ func newProfileShowCmd(st *store.Store) *cobra.Command {
 			if p.WorkshopContentPath != "" {
 				cmd.Printf("Workshop path: %s\n", pathutil.Abbreviate(p.WorkshopContentPath))
 			}
+			if p.LocalModsPath != "" {
+				cmd.Printf("Local mods:    %s\n", pathutil.Abbreviate(p.LocalModsPath))
+			}
+			backups := describeRetention(p.RetentionPolicy())
+			if p.ArchiveContent {
+				backups += ", with Workshop content"
+			}
+			cmd.Printf("Backups:       %s\n", backups)
+			if n := p.Redacted().Network; n != nil {
+				if n.Proxy != "" {
+					cmd.Printf("Proxy:         %s\n", n.Proxy)
+				}
+				if len(n.CAFiles) > 0 {
+					cmd.Printf("CA certs:      %d file(s)\n", len(n.CAFiles))
+				}
+				if n.Timeout != "" {
+					cmd.Printf("Timeout:       %s\n", n.Timeout)
+				}
+				if n.BaseURL != "" {
+					cmd.Printf("Steam API:     %s\n", n.BaseURL)
+				}
+			}
 			return nil
 		},
 	}
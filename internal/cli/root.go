@@ -27,6 +27,15 @@ func NewRootCommand(st *store.Store, ver string) *cobra.Command {
 			"  pzmod search hydrocraft     # search the Workshop",
 		SilenceUsage:  true,
 		SilenceErrors: true, // main.go is the sole error reporter
+		PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+			if isOffline(cmd) && !jsonEnabled(cmd) && cmd != cmd.Root() {
+				cmd.PrintErrln(styleWarn.Render("offline: Workshop data comes from the local cache and may be stale"))
+			}
+			if err := openSnapshot(cmd); err != nil {
+				return err
+			}
+			return openTrace(cmd)
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
 			return launchTUI(st, cmd)
 		},
@@ -34,18 +43,31 @@ func NewRootCommand(st *store.Store, ver string) *cobra.Command {
 
 	addTargetFlags(root)
 	root.PersistentFlags().Bool("json", false, "output machine-readable JSON instead of styled text")
+	root.PersistentFlags().Bool("offline", false, "answer Workshop lookups from the local cache only; never contact Steam")
+	root.PersistentFlags().String("workshop-snapshot", "", "answer Workshop lookups from a `file` saved by `pzmod workshop snapshot`")
+	root.MarkPersistentFlagFilename("workshop-snapshot", "json")
+	root.PersistentFlags().String("trace", "", "record every Steam request to `file` (HAR if it ends in .har, else NDJSON)")
+	root.MarkPersistentFlagFilename("trace", "har", "ndjson", "jsonl")
 	root.Flags().Bool("mouse", false, "enable mouse support in the terminal app (wheel scroll; may affect text selection)")
 	root.AddCommand(
 		newGetCmd(st),
 		newSetCmd(st),
 		newCopyCmd(st),
 		newAPIKeyCmd(st),
+		newNetworkCmd(st),
+		newWorkshopCmd(st),
+		newDownloadCmd(st),
+		newContentCmd(st),
+		newSteamCMDCmd(st),
 		newUpdateCmd(),
 		newProfileCmd(st),
 		newValidateCmd(st),
 		newDoctorCmd(st),
 		newSearchCmd(st),
+		newAuthorCmd(st),
 		newBackupCmd(st),
+		newHistoryCmd(st),
+		newUndoCmd(st),
 		newModsCmd(st),
 	)
 	registerFlagCompletions(root, st)
@@ -1,63 +1,200 @@
 package cli
 
 import (
+	"fmt"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/pkg/service"
 	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/store"
 	"github.com/spf13/cobra"
 )
 
+// searchAllMax caps `search --all`, which otherwise follows cursors until Steam
+// runs out of results.
+const searchAllMax = 1000
+
 func newSearchCmd(st *store.Store) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:   "search <text...>",
+		Use:   "search [text...]",
 		Short: "Search the Steam Workshop for Project Zomboid mods",
-		Args:  cobra.MinimumNArgs(1),
+		Long: "Search the Steam Workshop for Project Zomboid mods. With no text, browse by the\n" +
+			"chosen --sort. Results are limited to the profile's build tag (Build 41/42)\n" +
+			"unless --tag or --any-build is given.",
+		Example: "  pzmod search hydrocraft\n" +
+			"  pzmod search --sort subscribed --updated-since 30d\n" +
+			"  pzmod search vehicles --exclude-tag Map --all --json",
 		RunE: func(cmd *cobra.Command, args []string) error {
 			profile, _ := cmd.Flags().GetString("profile")
-			if !st.HasAPIKey(profile) {
-				return errNoKey
-			}
-			key, _ := st.APIKey(profile)
-			svc := service.New(steamFactory(key), st)
-
-			limit, _ := cmd.Flags().GetInt("limit")
-			tags, _ := cmd.Flags().GetStringArray("tag")
-			page, err := svc.Search(cmd.Context(), steam.Query{
-				SearchText: strings.Join(args, " "),
-				Tags:       tags,
-				PerPage:    limit,
-			})
+			if err := requireKey(cmd, st, profile); err != nil {
+				return err
+			}
+			q, err := searchQuery(cmd, st, strings.Join(args, " "))
+			if err != nil {
+				return err
+			}
+			svc := service.New(newSteam(cmd, st, profile), st)
+
+			var page steam.Page
+			if all, _ := cmd.Flags().GetBool("all"); all {
+				if !cmd.Flags().Changed("limit") {
+					q.PerPage = 100
+				}
+				page, err = svc.SearchAll(cmd.Context(), q, searchAllMax)
+			} else {
+				page, err = svc.Search(cmd.Context(), q)
+			}
 			if err != nil {
 				return err
 			}
+			svc.NameCreators(cmd.Context(), page.Items)
 
 			if jsonEnabled(cmd) {
 				out := searchJSON{Total: page.Total, Items: make([]searchItemJSON, 0, len(page.Items))}
-				for _, it := range page.Items {
-					out.Items = append(out.Items, searchItemJSON{
-						ID:       it.PublishedFileID,
-						Title:    it.Title,
-						FileSize: int64(it.FileSize),
-					})
+				for i := range page.Items {
+					out.Items = append(out.Items, newSearchItemJSON(&page.Items[i]))
 				}
 				return emitJSON(cmd, out)
 			}
 
-			cmd.Printf("%s\n", styleMuted.Render(humanize.Comma(int64(page.Total))+" results"))
+			summary := humanize.Comma(int64(page.Total)) + " results"
+			if q.Page > 0 && q.PerPage > 0 {
+				pages := (page.Total + q.PerPage - 1) / q.PerPage
+				summary += fmt.Sprintf(" · page %d of %d", q.Page, pages)
+			}
+			if len(q.Tags) > 0 {
+				summary += " · tagged " + strings.Join(q.Tags, ", ")
+			}
+			cmd.Printf("%s\n", styleMuted.Render(summary))
 			for _, it := range page.Items {
 				cmd.Printf("%s  %s  %s\n",
 					styleInfo.Render(it.PublishedFileID),
 					it.Title,
-					styleMuted.Render(humanize.Bytes(uint64(it.FileSize))))
+					styleMuted.Render(itemMeta(&it)))
 			}
 			return nil
 		},
 	}
-	cmd.Flags().IntP("limit", "l", 20, "max results")
+	cmd.Flags().IntP("limit", "l", 20, "results per page (max 100)")
+	cmd.Flags().Int("page", 1, "page of results to show")
+	cmd.Flags().Bool("all", false, fmt.Sprintf("fetch every page (up to %d results)", searchAllMax))
+	cmd.Flags().String("sort", "", "ranking: relevance, trend, subscribed, recent, or updated")
 	cmd.Flags().StringArray("tag", nil, "required Workshop tag (repeatable)")
-	cmd.Flags().StringP("profile", "p", "", "use a profile's API key")
+	cmd.Flags().Bool("any-tag", false, "match items with any --tag rather than all of them")
+	cmd.Flags().StringArray("exclude-tag", nil, "skip items with this Workshop tag (repeatable)")
+	cmd.Flags().Bool("any-build", false, "don't filter by the profile's build tag")
+	cmd.Flags().String("created-since", "", "only items created since a date (2024-01-31) or age (30d, 12h)")
+	cmd.Flags().String("created-before", "", "only items created before a date or age")
+	cmd.Flags().String("updated-since", "", "only items updated since a date (2024-01-31) or age (30d, 12h)")
+	cmd.Flags().String("updated-before", "", "only items updated before a date or age")
+	cmd.Flags().StringP("profile", "p", "", "use a profile's API key and build")
+	_ = cmd.RegisterFlagCompletionFunc("sort", func(*cobra.Command, []string, string) ([]string, cobra.ShellCompDirective) {
+		names := make([]string, len(steam.Sorts))
+		for i, s := range steam.Sorts {
+			names[i] = string(s)
+		}
+		return names, cobra.ShellCompDirectiveNoFileComp
+	})
 	return cmd
 }
+
+// searchQuery builds the Workshop query from the search flags.
+func searchQuery(cmd *cobra.Command, st *store.Store, text string) (steam.Query, error) {
+	q := steam.Query{SearchText: text}
+	q.PerPage, _ = cmd.Flags().GetInt("limit")
+	q.Tags, _ = cmd.Flags().GetStringArray("tag")
+	q.MatchAnyTag, _ = cmd.Flags().GetBool("any-tag")
+	q.ExcludedTags, _ = cmd.Flags().GetStringArray("exclude-tag")
+
+	sortName, _ := cmd.Flags().GetString("sort")
+	sort, err := steam.ParseSort(sortName)
+	if err != nil {
+		return steam.Query{}, err
+	}
+	q.Sort = sort
+
+	if page, _ := cmd.Flags().GetInt("page"); cmd.Flags().Changed("page") {
+		if page < 1 {
+			return steam.Query{}, fmt.Errorf("--page must be 1 or more")
+		}
+		q.Page = page
+	}
+
+	now := time.Now()
+	for flag, dst := range map[string]*time.Time{
+		"created-since": &q.CreatedAfter, "created-before": &q.CreatedBefore,
+		"updated-since": &q.UpdatedAfter, "updated-before": &q.UpdatedBefore,
+	} {
+		v, _ := cmd.Flags().GetString(flag)
+		if v == "" {
+			continue
+		}
+		t, err := parseSince(v, now)
+		if err != nil {
+			return steam.Query{}, fmt.Errorf("--%s: %w", flag, err)
+		}
+		*dst = t
+	}
+	if !q.CreatedBefore.IsZero() && q.CreatedBefore.Before(q.CreatedAfter) {
+		return steam.Query{}, fmt.Errorf("--created-before is earlier than --created-since")
+	}
+	if !q.UpdatedBefore.IsZero() && q.UpdatedBefore.Before(q.UpdatedAfter) {
+		return steam.Query{}, fmt.Errorf("--updated-before is earlier than --updated-since")
+	}
+
+	anyBuild, _ := cmd.Flags().GetBool("any-build")
+	if len(q.Tags) == 0 && !anyBuild {
+		if tag := searchBuild(cmd, st).WorkshopTag(); tag != "" {
+			q.Tags = []string{tag}
+		}
+	}
+	return q, nil
+}
+
+// searchBuild is the build of --profile, or of the default profile. Search
+// works without a profile, so a missing one just means no build filter.
+func searchBuild(cmd *cobra.Command, st *store.Store) build.Build {
+	var (
+		p   store.Profile
+		err error
+	)
+	if name, _ := cmd.Flags().GetString("profile"); name != "" {
+		p, err = st.Profile(name)
+	} else {
+		p, err = st.DefaultProfile()
+	}
+	if err != nil {
+		return build.Unknown
+	}
+	return build.Parse(p.Build)
+}
+
+// parseSince reads a point in time given as a date (2006-01-02) or as an age
+// before now: a Go duration (12h) or a number of days (30d).
+func parseSince(s string, now time.Time) (time.Time, error) {
+	if t, err := time.ParseInLocation("2006-01-02", s, time.Local); err == nil {
+		return t, nil
+	}
+	if days, ok := strings.CutSuffix(s, "d"); ok {
+		if n, err := strconv.Atoi(days); err == nil && n >= 0 {
+			return now.AddDate(0, 0, -n), nil
+		}
+	}
+	if d, err := time.ParseDuration(s); err == nil && d >= 0 {
+		return now.Add(-d), nil
+	}
+	return time.Time{}, fmt.Errorf("%q is not a date (2024-01-31) or age (30d, 12h)", s)
+}
+
+// itemMeta is the muted size (and author, when known) shown after a title.
+func itemMeta(it *steam.WorkshopItem) string {
+	meta := humanize.Bytes(uint64(it.FileSize))
+	if it.CreatorName != "" {
+		meta += " · by " + it.CreatorName
+	}
+	return meta
+}
@@ -4,6 +4,7 @@ import (
 	"fmt"
 	"strings"
 
+	"github.com/kldzj/pzmod/pkg/serverconfig"
 	"github.com/kldzj/pzmod/pkg/store"
 	"github.com/spf13/cobra"
 )
@@ -63,7 +64,11 @@ func newSetCmd(st *store.Store) *cobra.Command {
 				cmd.Print(cfg.String())
 				return nil
 			}
-			if err := cfg.Save(); err != nil {
+			jargs := []string{args[0], value}
+			if serverconfig.IsSecretKey(key) {
+				jargs[1] = "********"
+			}
+			if _, err := t.services(st).ApplyChange(t.profile, cfg, journalOp(cmd, jargs, "before set "+args[0])); err != nil {
 				return err
 			}
 			if jsonEnabled(cmd) {
@@ -74,6 +79,7 @@ func newSetCmd(st *store.Store) *cobra.Command {
 	}
 	cmd.Flags().BoolP("no-save", "n", false, "print the result instead of writing the file")
 	cmd.Flags().Bool("dry-run", false, "show the change without writing")
+	cmd.Flags().Bool("no-backup", false, "do not snapshot before saving")
 	cmd.ValidArgsFunction = completeConfigKeys
 	addTargetFlags(cmd)
 	return cmd
@@ -1,8 +1,11 @@
 package cli
 
 import (
+	"context"
 	"errors"
 	"path/filepath"
+	"sync"
+	"time"
 
 	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/internal/pathutil"
@@ -13,18 +16,121 @@ import (
 	"github.com/spf13/cobra"
 )
 
-// errNoKey is returned by commands that need the Steam API but have no key.
+// errNoKey is returned by search, the one command the keyless Workshop
+// endpoints cannot serve.
 var errNoKey = errors.New("no Steam API key set - run `pzmod api-key <key>`")
 
-// steamFactory builds the Steam API client from a key. It is a package var so
-// tests can substitute a fake.
-var steamFactory = func(key string) steam.API { return steam.New(key) }
+// steamFactory builds the Steam API client from a key (keyless when empty). It
+// is a package var so tests can substitute a fake.
+var steamFactory = func(key string, opts ...steam.Option) steam.API { return steam.NewAPI(key, opts...) }
+
+// steamClient builds the Steam client for a key and the profile whose network
+// settings apply ("" for the global ones). If those settings can't be loaded
+// (say a CA file was deleted), the client fails every call with why, so
+// commands that never touch Steam still work.
+//
+// With --workshop-snapshot, the snapshot stands in for Steam altogether.
+func steamClient(st *store.Store, key, profileID string, offline bool) steam.API {
+	if activeSnapshot != nil {
+		return activeSnapshot
+	}
+	opts, err := steamOptions(st, profileID, offline)
+	if err != nil {
+		return brokenSteam{err}
+	}
+	return buildSteam(key, opts...)
+}
+
+// revalidateGrace bounds how long a finished command waits for background
+// refreshes of stale cache entries before the process exits.
+const revalidateGrace = 5 * time.Second
+
+// liveSteam holds the clients built this run, for waitRevalidation.
+var (
+	liveSteamMu sync.Mutex
+	liveSteam   []interface{ WaitRevalidation() }
+)
+
+// buildSteam calls steamFactory and remembers clients that refresh stale
+// cache entries in the background.
+func buildSteam(key string, opts ...steam.Option) steam.API {
+	api := steamFactory(key, opts...)
+	if r, ok := api.(interface{ WaitRevalidation() }); ok {
+		liveSteamMu.Lock()
+		liveSteam = append(liveSteam, r)
+		liveSteamMu.Unlock()
+	}
+	return api
+}
+
+// waitRevalidation gives the background refreshes started this run up to
+// revalidateGrace to finish. Without it they die with the process, and stale
+// entries would be served until they expire.
+func waitRevalidation() {
+	liveSteamMu.Lock()
+	clients := liveSteam
+	liveSteam = nil
+	liveSteamMu.Unlock()
+	if len(clients) == 0 {
+		return
+	}
+	done := make(chan struct{})
+	go func() {
+		for _, c := range clients {
+			c.WaitRevalidation()
+		}
+		close(done)
+	}()
+	select {
+	case <-done:
+	case <-time.After(revalidateGrace):
+	}
+}
+
+// brokenSteam is a steam.API that always fails with err.
+type brokenSteam struct{ err error }
+
+func (b brokenSteam) GetDetails(context.Context, []string) ([]steam.WorkshopItem, []string, error) {
+	return nil, nil, b.err
+}
+func (b brokenSteam) QueryFiles(context.Context, steam.Query) (steam.Page, error) {
+	return steam.Page{}, b.err
+}
+func (b brokenSteam) UserFiles(context.Context, string, int, int) (steam.Page, error) {
+	return steam.Page{}, b.err
+}
+func (b brokenSteam) PlayerNames(context.Context, []string) (map[string]string, error) {
+	return nil, b.err
+}
+
+// isOffline reports whether the global --offline flag is set.
+func isOffline(cmd *cobra.Command) bool {
+	b, _ := cmd.Flags().GetBool("offline")
+	return b
+}
+
+// newSteam builds the Steam client for a command that takes a --profile but no
+// target: the profile's key and network settings, or the global ones.
+func newSteam(cmd *cobra.Command, st *store.Store, profile string) steam.API {
+	key, _ := st.APIKey(profile)
+	return steamClient(st, key, profile, isOffline(cmd))
+}
+
+// requireKey errors when no API key is configured for profile, for search.
+// Offline runs never reach the API, so they don't need one.
+func requireKey(cmd *cobra.Command, st *store.Store, profile string) error {
+	if !isOffline(cmd) && !st.HasAPIKey(profile) {
+		return errNoKey
+	}
+	return nil
+}
 
 // target identifies which server config a command operates on, resolved from
 // --file, --profile, or the default profile.
 type target struct {
 	profile store.Profile // for ad-hoc --file this is a synthetic profile
 	adHoc   bool          // true when resolved from --file rather than a stored profile
+	offline bool          // --offline: Steam data comes from the Workshop cache only
 }
 
 func (t target) iniPath() string    { return t.profile.IniPath }
@@ -40,6 +146,12 @@ func addTargetFlags(cmd *cobra.Command) {
 
 // resolveTarget picks the target from flags, falling back to the default profile.
 func resolveTarget(cmd *cobra.Command, st *store.Store) (target, error) {
+	t, err := resolveProfile(cmd, st)
+	t.offline = isOffline(cmd)
+	return t, err
+}
+
+func resolveProfile(cmd *cobra.Command, st *store.Store) (target, error) {
 	file, _ := cmd.Flags().GetString("file")
 	profileName, _ := cmd.Flags().GetString("profile")
 
@@ -71,17 +183,8 @@ func (t target) config() (*serverconfig.Config, error) {
 }
 
 // services builds a Services aggregate for the target. The steam client is
-// constructed with the resolved key (which may be empty for offline commands).
+// constructed with the resolved key; without one it uses the keyless endpoints.
 func (t target) services(st *store.Store) *service.Services {
 	key, _ := st.APIKey(t.profileID())
-	return service.New(steamFactory(key), st)
-}
-
-// servicesWithSteam is like services but errors when no API key is configured,
-// for commands that must reach the Steam API.
-func (t target) servicesWithSteam(st *store.Store) (*service.Services, error) {
-	if !st.HasAPIKey(t.profileID()) {
-		return nil, errNoKey
-	}
-	return t.services(st), nil
+	return service.New(steamClient(st, key, t.profileID(), t.offline), st)
 }
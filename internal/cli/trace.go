@@ -0,0 +1,63 @@
+package cli
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/kldzj/pzmod/internal/pathutil"
+	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// activeTrace is the --trace file for this run, if any; traceOut is where its
+// summary goes when the command finishes.
+var (
+	activeTrace *steam.TraceFile
+	traceOut    io.Writer
+)
+
+// Finalizers run after every command, failed ones included. Background cache
+// refreshes get to finish first, so they are persisted and traced.
+func init() { cobra.OnFinalize(waitRevalidation, closeTrace) }
+
+// openTrace starts recording Steam requests when --trace is given.
+func openTrace(cmd *cobra.Command) error {
+	path, _ := cmd.Flags().GetString("trace")
+	if path == "" {
+		return nil
+	}
+	tf, err := steam.OpenTraceFile(pathutil.Expand(path))
+	if err != nil {
+		return fmt.Errorf("--trace: %w", err)
+	}
+	activeTrace, traceOut = tf, cmd.ErrOrStderr()
+	return nil
+}
+
+// closeTrace writes out the trace file and prints its summary.
+func closeTrace() {
+	tf := activeTrace
+	if tf == nil {
+		return
+	}
+	activeTrace = nil
+	if err := tf.Close(); err != nil {
+		fmt.Fprintln(traceOut, styleWarn.Render("trace: "+err.Error()))
+		return
+	}
+	fmt.Fprintln(traceOut, styleMuted.Render(fmt.Sprintf("trace: %s → %s", tf.Summary(), pathutil.Abbreviate(tf.Path()))))
+}
+
+// steamOptions is service.SteamOptions plus the --trace recorder.
+func steamOptions(st *store.Store, profileID string, offline bool) ([]steam.Option, error) {
+	opts, err := service.SteamOptions(st, profileID, offline)
+	if err != nil {
+		return nil, err
+	}
+	if activeTrace != nil {
+		opts = append(opts, steam.WithTracer(activeTrace))
+	}
+	return opts, nil
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 
 	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/store"
 	"github.com/kldzj/pzmod/internal/tui"
 	"github.com/spf13/cobra"
@@ -20,6 +21,17 @@ func launchTUI(st *store.Store, cmd *cobra.Command) error {
 	// so it does not use the command's interrupt-cancelled context; in-flight
 	// Steam work uses a plain background context and is bounded by quitting.
 	tuiCtx := context.Background()
+	offline := isOffline(cmd)
+	newSteam := func(key, profileID string) (steam.API, error) {
+		if activeSnapshot != nil {
+			return activeSnapshot, nil
+		}
+		opts, err := steamOptions(st, profileID, offline)
+		if err != nil {
+			return nil, err
+		}
+		return buildSteam(key, opts...), nil
+	}
 
 	if file != "" || profile != "" {
 		t, err := resolveTarget(cmd, st)
@@ -27,10 +39,10 @@ func launchTUI(st *store.Store, cmd *cobra.Command) error {
 			return err
 		}
 		svc := t.services(st)
-		return tui.RunOpen(svc, st, tuiCtx, t.profile, mouse)
+		return tui.RunOpen(svc, st, tuiCtx, newSteam, t.profile, mouse)
 	}
 
 	key, _ := st.APIKey("")
-	svc := service.New(steamFactory(key), st)
-	return tui.Run(svc, st, tuiCtx, tui.NewLauncher(), mouse)
+	svc := service.New(steamClient(st, key, "", offline), st)
+	return tui.Run(svc, st, tuiCtx, newSteam, tui.NewLauncher(), mouse)
 }
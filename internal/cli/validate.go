@@ -4,14 +4,21 @@ import (
 	"fmt"
 
 	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/service"
 	"github.com/kldzj/pzmod/pkg/store"
 	"github.com/spf13/cobra"
 )
 
 func newValidateCmd(st *store.Store) *cobra.Command {
 	cmd := &cobra.Command{
-		Use:           "validate",
-		Short:         "Validate mods and dependencies (exits non-zero on errors)",
+		Use:   "validate",
+		Short: "Validate mods and dependencies (exits non-zero on errors)",
+		Long: "Checks the config's mods, maps, and Workshop items against the Workshop.\n" +
+			"With --source disk nothing is fetched: which mods and maps each item provides,\n" +
+			"and what they require, are read from the mod.info files under the profile's\n" +
+			"Workshop content path, for servers firewalled from the Steam Web API.",
+		Example: "  pzmod validate --profile main\n" +
+			"  pzmod validate --source disk   # offline, from downloaded content",
 		Args:          cobra.NoArgs,
 		SilenceErrors: true,
 		SilenceUsage:  true,
@@ -20,16 +27,20 @@ func newValidateCmd(st *store.Store) *cobra.Command {
 			if err != nil {
 				return err
 			}
-			svc, err := t.servicesWithSteam(st)
-			if err != nil {
-				return err
-			}
 			cfg, err := t.config()
 			if err != nil {
 				return err
 			}
 
-			report, err := svc.Validate(cmd.Context(), cfg.ServerMods(), t.build())
+			var report domain.Report
+			switch source, _ := cmd.Flags().GetString("source"); source {
+			case "steam":
+				report, err = t.services(st).ValidateProfile(cmd.Context(), t.profile, cfg.ServerMods())
+			case "disk":
+				report, err = service.New(nil, st).ValidateDisk(t.profile, cfg.ServerMods())
+			default:
+				return fmt.Errorf("unknown --source %q (want steam or disk)", source)
+			}
 			if err != nil {
 				return err
 			}
@@ -37,13 +48,19 @@ func newValidateCmd(st *store.Store) *cobra.Command {
 			findings := report.Sorted()
 
 			if jsonEnabled(cmd) {
-				out := validateJSON{Findings: make([]findingJSON, 0, len(findings)), OK: !report.HasErrors()}
+				out := validateJSON{
+					Findings:   make([]findingJSON, 0, len(findings)),
+					Incomplete: report.Incomplete(),
+					Unchecked:  orEmpty(report.Unchecked),
+					OK:         !report.HasErrors() && !report.Incomplete(),
+				}
 				for _, f := range findings {
 					out.Findings = append(out.Findings, findingJSON{
 						Severity: f.Severity.String(),
 						Code:     f.Code,
 						Subject:  f.Subject,
 						Message:  f.Message,
+						Hint:     f.Hint,
 					})
 				}
 				out.Summary.Errors = report.Count(domain.SeverityError)
@@ -52,10 +69,7 @@ func newValidateCmd(st *store.Store) *cobra.Command {
 				if err := emitJSON(cmd, out); err != nil {
 					return err
 				}
-				if report.HasErrors() {
-					return fmt.Errorf("validation failed with %d error(s)", report.Count(domain.SeverityError))
-				}
-				return nil
+				return validateErr(report)
 			}
 
 			if len(findings) == 0 {
@@ -64,18 +78,33 @@ func newValidateCmd(st *store.Store) *cobra.Command {
 			}
 			for _, f := range findings {
 				cmd.Printf("%s %s\n", severityTag(f.Severity), f.Message)
+				if f.Hint != "" {
+					cmd.Printf("      %s\n", styleMuted.Render("→ "+f.Hint))
+				}
 			}
 			cmd.Printf("\n%d error(s), %d warning(s), %d info\n",
 				report.Count(domain.SeverityError),
 				report.Count(domain.SeverityWarning),
 				report.Count(domain.SeverityInfo))
-
-			if report.HasErrors() {
-				return fmt.Errorf("validation failed with %d error(s)", report.Count(domain.SeverityError))
+			if report.Incomplete() {
+				cmd.Println(styleWarn.Render(fmt.Sprintf("incomplete: %d item(s) could not be checked", len(report.Unchecked))))
 			}
-			return nil
+			return validateErr(report)
 		},
 	}
+	cmd.Flags().String("source", "steam", "where item contents come from: steam (the Workshop API) or disk (the profile's Workshop content path, no network)")
 	addTargetFlags(cmd)
 	return cmd
 }
+
+// validateErr is the error validate exits with: errors fail it, and so does an
+// incomplete report, since an unchecked item may hide one.
+func validateErr(report domain.Report) error {
+	switch {
+	case report.HasErrors():
+		return fmt.Errorf("validation failed with %d error(s)", report.Count(domain.SeverityError))
+	case report.Incomplete():
+		return fmt.Errorf("validation incomplete: %d item(s) could not be checked", len(report.Unchecked))
+	}
+	return nil
+}
@@ -0,0 +1,101 @@
+package cli
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/kldzj/pzmod/internal/pathutil"
+	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/store"
+	"github.com/spf13/cobra"
+)
+
+// activeSnapshot is the --workshop-snapshot file for this run, if any. When
+// set, every Steam client is replaced by it.
+var activeSnapshot *steam.SnapshotAPI
+
+func init() { cobra.OnFinalize(func() { activeSnapshot = nil }) }
+
+// openSnapshot loads the --workshop-snapshot file when given.
+func openSnapshot(cmd *cobra.Command) error {
+	activeSnapshot = nil
+	path, _ := cmd.Flags().GetString("workshop-snapshot")
+	if path == "" {
+		return nil
+	}
+	snap, err := steam.LoadSnapshot(pathutil.Expand(path))
+	if err != nil {
+		return fmt.Errorf("--workshop-snapshot: %w", err)
+	}
+	activeSnapshot = steam.NewSnapshotAPI(snap)
+	if !jsonEnabled(cmd) && cmd != cmd.Root() {
+		cmd.PrintErrln(styleMuted.Render(fmt.Sprintf("workshop data comes from %s (taken %s)",
+			pathutil.Abbreviate(path), snap.Created.Local().Format("2006-01-02 15:04"))))
+	}
+	return nil
+}
+
+func newWorkshopCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "workshop",
+		Short: "Capture Workshop data for reproducible validation",
+	}
+	cmd.AddCommand(newWorkshopSnapshotCmd(st))
+	return cmd
+}
+
+func newWorkshopSnapshotCmd(st *store.Store) *cobra.Command {
+	cmd := &cobra.Command{
+		Use:   "snapshot",
+		Short: "Save the Workshop details a config depends on to a file",
+		Long: "Fetches every item in the target's WorkshopItems, plus their dependencies\n" +
+			"and collection members, and writes them to a JSON file. Pass that file to\n" +
+			"--workshop-snapshot to validate or resolve against exactly that data,\n" +
+			"without Steam: for reproducible CI runs, or to attach to a bug report.",
+		Example: "  pzmod workshop snapshot --out ws.json\n" +
+			"  pzmod validate --workshop-snapshot ws.json",
+		Args: cobra.NoArgs,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			out, _ := cmd.Flags().GetString("out")
+			t, err := resolveTarget(cmd, st)
+			if err != nil {
+				return err
+			}
+			cfg, err := t.config()
+			if err != nil {
+				return err
+			}
+			snap, err := t.services(st).WorkshopSnapshot(cmd.Context(), cfg.ServerMods())
+			if err != nil {
+				return err
+			}
+			path := pathutil.Expand(out)
+			if err := snap.Save(path); err != nil {
+				return err
+			}
+
+			if jsonEnabled(cmd) {
+				return emitJSON(cmd, workshopSnapshotJSON{Path: path, Items: len(snap.Items), Missing: orEmpty(snapshotMissing(snap))})
+			}
+			cmd.Println(styleOK.Render(fmt.Sprintf("saved %d item(s) to %s", len(snap.Items), pathutil.Abbreviate(path))))
+			if n := len(snap.Missing); n > 0 {
+				cmd.Println(styleWarn.Render(fmt.Sprintf("%d item(s) unavailable on the Workshop (recorded as such)", n)))
+			}
+			return nil
+		},
+	}
+	addTargetFlags(cmd)
+	cmd.Flags().StringP("out", "o", "", "file to write the snapshot to")
+	cmd.MarkFlagRequired("out")
+	cmd.MarkFlagFilename("out", "json")
+	return cmd
+}
+
+func snapshotMissing(s *steam.Snapshot) []string {
+	ids := make([]string, 0, len(s.Missing))
+	for id := range s.Missing {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	return ids
+}
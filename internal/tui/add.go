@@ -46,7 +46,7 @@ func quickAdd(s *Session, id string, replaceTop bool) tea.Cmd {
 		for _, m := range parsed.Mods {
 			sm = sm.AddMod(domain.FormatModRef(it.PublishedFileID, m, explicit))
 		}
-		s.Cfg.ApplyServerMods(sm)
+		s.Edit("add "+itemTitle(&it), func() { s.Cfg.ApplyServerMods(sm) })
 		return modsChangedMsg{toast: "added " + itemTitle(&it) + " (unsaved)"}
 	})
 }
@@ -27,6 +27,8 @@ func NewAddByID() Screen {
 
 func (a *addByID) Title() string { return "Add by ID" }
 
+func (a *addByID) claimsKey(string) bool { return true }
+
 func (a *addByID) Init(s *Session) tea.Cmd { return textinput.Blink }
 
 func (a *addByID) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
@@ -84,7 +84,7 @@ func (a *addSheet) apply(s *Session) tea.Cmd {
 			sm = sm.AddMod(domain.FormatModRef(a.id, r.value, explicit))
 		}
 	}
-	s.Cfg.ApplyServerMods(sm)
+	s.Edit("add "+a.title, func() { s.Cfg.ApplyServerMods(sm) })
 	return tea.Batch(Pop(), func() tea.Msg { return modsChangedMsg{toast: "added " + a.title + " (unsaved)"} })
 }
 
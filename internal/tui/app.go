@@ -16,6 +16,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/charmbracelet/lipgloss"
 	"github.com/kldzj/pzmod/pkg/service"
+	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/store"
 )
 
@@ -58,14 +59,19 @@ func New(svc *service.Services, st *store.Store, ctx context.Context, initial Sc
 
 // Run starts the program on the alt screen. The session uses ctx for in-flight
 // Steam work; interrupts are handled by the model's guard, not ctx cancellation.
-func Run(svc *service.Services, st *store.Store, ctx context.Context, initial Screen, mouse bool) error {
-	return runProgram(New(svc, st, ctx, initial), mouse)
+// newSteam builds clients when a profile is opened or the key changes (nil
+// uses the default, which honours the store's network settings).
+func Run(svc *service.Services, st *store.Store, ctx context.Context, newSteam func(key, profileID string) (steam.API, error), initial Screen, mouse bool) error {
+	m := New(svc, st, ctx, initial)
+	m.s.NewSteam = newSteam
+	return runProgram(m, mouse)
 }
 
 // RunOpen starts the app with a profile already opened, going straight to the
 // dashboard (used for `pzmod --file` / `--profile`).
-func RunOpen(svc *service.Services, st *store.Store, ctx context.Context, profile store.Profile, mouse bool) error {
+func RunOpen(svc *service.Services, st *store.Store, ctx context.Context, newSteam func(key, profileID string) (steam.API, error), profile store.Profile, mouse bool) error {
 	m := New(svc, st, ctx, NewDashboard())
+	m.s.NewSteam = newSteam
 	if err := m.s.OpenProfile(profile); err != nil {
 		return err
 	}
@@ -161,6 +167,21 @@ func (m *model) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
 				return m, Toast("nothing to save")
 			}
 			return m, Push(NewSaveConfirm())
+		case key.Matches(msg, m.s.Keys.Undo, m.s.Keys.Redo):
+			if m.s.Cfg == nil || claimed(m.active(), msg.String()) {
+				break
+			}
+			step, verb, none := m.s.Redo, "redid", "nothing to redo"
+			if key.Matches(msg, m.s.Keys.Undo) {
+				step, verb, none = m.s.Undo, "undid", "nothing to undo"
+			}
+			label, ok := step()
+			if !ok {
+				return m, Toast(none)
+			}
+			m.toast, m.toastErr = verb+" "+label, false
+			m.toastToken++
+			return m, tea.Batch(m.scheduleToastClear(), m.delegate(editSteppedMsg{}))
 		}
 		return m, m.delegate(msg)
 
@@ -329,6 +350,12 @@ func (m *model) renderFooter() string {
 		}
 	}
 	hint := "? help · ctrl+s save · esc back · ctrl+c quit"
+	if label := m.s.UndoLabel(); label != "" {
+		hint = "u undo " + label + " · " + hint
+	}
+	if label := m.s.RedoLabel(); label != "" {
+		hint = "ctrl+r redo " + label + " · " + hint
+	}
 	bar := th.BottomBar.Width(m.s.Width).Render(" " + hint)
 	return lipgloss.JoinVertical(lipgloss.Left, " "+toast, bar)
 }
@@ -338,6 +365,10 @@ func (m *model) renderFooter() string {
 // view can refresh itself.
 type modsChangedMsg struct{ toast string }
 
+// editSteppedMsg is delivered to the active screen after an undo or redo
+// replaced the config's content, so list views can re-read it.
+type editSteppedMsg struct{}
+
 // resumedMsg is delivered to the now-active screen after a Pop, so a screen can
 // refresh state that may have changed while a child screen was on top.
 type resumedMsg struct{}
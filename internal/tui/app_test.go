@@ -34,7 +34,7 @@ func testModel(t *testing.T, fake *steamtest.Fake) (*teatest.TestModel, *model,
 
 	svc := service.New(fake, st)
 	m := New(svc, st, context.Background(), NewLauncher())
-	m.s.NewSteam = func(string) steam.API { return fake }
+	m.s.NewSteam = func(string, string) (steam.API, error) { return fake, nil }
 
 	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(100, 30))
 	tm.Send(tea.WindowSizeMsg{Width: 100, Height: 30})
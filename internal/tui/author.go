@@ -0,0 +1,117 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+// author lists the other Workshop items published by a mod's creator, opened
+// from the detail screen.
+type author struct {
+	creator string
+	exclude string // the item the screen was opened from
+	items   []steam.WorkshopItem
+	total   int
+	cursor  int
+	load    loader
+	loading bool
+}
+
+// NewAuthor returns the screen listing creator's items, leaving out exclude.
+func NewAuthor(creator, exclude string) Screen {
+	return &author{creator: creator, exclude: exclude, loading: true, load: newLoader()}
+}
+
+func (a *author) Title() string { return "More by this author" }
+
+type authorLoadedMsg struct {
+	items []steam.WorkshopItem
+	total int
+	err   error
+}
+
+func (a *author) Init(s *Session) tea.Cmd {
+	creator := a.creator
+	return tea.Batch(a.load.tick(), s.Do(func(ctx context.Context) tea.Msg {
+		res, err := s.Svc.AuthorItems(ctx, creator)
+		return authorLoadedMsg{items: res.Items, total: res.Total, err: err}
+	}))
+}
+
+func (a *author) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
+	if cmd, ok := a.load.update(msg); ok {
+		if a.loading {
+			return a, cmd
+		}
+		return a, nil
+	}
+	switch msg := msg.(type) {
+	case authorLoadedMsg:
+		a.loading = false
+		if msg.err != nil {
+			return a, tea.Batch(Fail(msg.err), Pop())
+		}
+		a.total = msg.total
+		for _, it := range msg.items {
+			if it.PublishedFileID != a.exclude {
+				a.items = append(a.items, it)
+			}
+		}
+		return a, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return a, Pop()
+		case "up", "k":
+			if a.cursor > 0 {
+				a.cursor--
+			}
+		case "down", "j":
+			if a.cursor < len(a.items)-1 {
+				a.cursor++
+			}
+		case "enter":
+			if a.cursor < len(a.items) {
+				return a, Push(NewDetail(a.items[a.cursor].PublishedFileID))
+			}
+		}
+	}
+	return a, nil
+}
+
+func (a *author) View(s *Session) string {
+	th := s.Theme
+	if a.loading {
+		return pad(a.load.view(th, "loading the author's items…"))
+	}
+	var b strings.Builder
+	b.WriteString(th.Muted.Render(fmt.Sprintf("%d other item(s) by this author", len(a.items))) + "\n\n")
+
+	installed := installedSet(s)
+	h := max(3, s.BodyHeight()-4)
+	start, end := listWindow(a.cursor, len(a.items), h)
+	if start > 0 {
+		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n")
+	}
+	for i := start; i < end; i++ {
+		it := a.items[i]
+		badge := "  "
+		if installed[it.PublishedFileID] {
+			badge = th.OK.Render("✓ ")
+		}
+		b.WriteString(renderRow(th, s.ContentWidth(), badge, itemTitle(&it), humanize.Bytes(uint64(it.FileSize)), i == a.cursor) + "\n")
+	}
+	if end < len(a.items) {
+		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↓ %d more", len(a.items)-end)) + "\n")
+	}
+	if len(a.items) == 0 {
+		b.WriteString(th.Muted.Render("no other items") + "\n")
+	}
+	b.WriteString("\n" + th.Muted.Render("enter: details   ↑/↓: browse   esc: back"))
+	return pad(b.String())
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"testing"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+func TestAuthorExcludesOrigin(t *testing.T) {
+	a := NewAuthor("76561198000000001", "1").(*author)
+	a.Update(&Session{}, authorLoadedMsg{total: 3, items: []steam.WorkshopItem{
+		{PublishedFileID: "1", Title: "Origin"},
+		{PublishedFileID: "2", Title: "Companion"},
+		{PublishedFileID: "3", Title: "Addon"},
+	}})
+	if len(a.items) != 2 || a.items[0].PublishedFileID != "2" {
+		t.Fatalf("items = %+v; want the origin left out", a.items)
+	}
+
+	a.Update(&Session{}, tea.KeyMsg{Type: tea.KeyDown})
+	_, cmd := a.Update(&Session{}, tea.KeyMsg{Type: tea.KeyEnter})
+	push, ok := cmd().(PushMsg)
+	if !ok {
+		t.Fatalf("enter = %T; want PushMsg", cmd())
+	}
+	if d, ok := push.Screen.(*detail); !ok || d.id != "3" {
+		t.Errorf("pushed %+v; want detail for 3", push.Screen)
+	}
+}
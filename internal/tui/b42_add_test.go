@@ -29,7 +29,7 @@ func openB42Model(t *testing.T, fake *steamtest.Fake, iniBody string) (*teatest.
 	p, _ := st.AddProfile(store.Profile{Name: "B42", IniPath: ini, Build: "b42"})
 	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef")
 	m := New(nil, st, context.Background(), NewInstalled())
-	m.s.NewSteam = func(string) steam.API { return fake }
+	m.s.NewSteam = func(string, string) (steam.API, error) { return fake, nil }
 	if err := m.s.OpenProfile(p); err != nil {
 		t.Fatal(err)
 	}
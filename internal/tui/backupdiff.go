@@ -2,17 +2,23 @@ package tui
 
 import (
 	"context"
+	"fmt"
 	"os"
+	"strings"
 
 	"github.com/aymanbagabas/go-udiff"
 	"github.com/charmbracelet/bubbles/viewport"
 	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
 )
 
-// backupDiff shows a unified diff between a backup and the current config file.
+// backupDiff shows a semantic summary and a unified diff between a backup and
+// the current config file.
 type backupDiff struct {
 	profileID, backupID, path string
 	diff                      string
+	semantic                  serverconfig.Diff
 	vp                        viewport.Model
 	loading                   bool
 	ready                     bool
@@ -26,8 +32,9 @@ func NewBackupDiff(profileID, backupID, path string) Screen {
 func (bd *backupDiff) Title() string { return "Backup diff" }
 
 type diffLoadedMsg struct {
-	diff string
-	err  error
+	diff     string
+	semantic serverconfig.Diff
+	err      error
 }
 
 func (bd *backupDiff) Init(s *Session) tea.Cmd {
@@ -41,7 +48,8 @@ func (bd *backupDiff) Init(s *Session) tea.Cmd {
 			return diffLoadedMsg{err: err}
 		}
 		d := udiff.Unified("backup", "current", string(backupBytes), string(current))
-		return diffLoadedMsg{diff: d}
+		sem := serverconfig.Compare(serverconfig.FromBytes("", backupBytes), serverconfig.FromBytes(bd.path, current))
+		return diffLoadedMsg{diff: d, semantic: sem}
 	})
 }
 
@@ -56,6 +64,7 @@ func (bd *backupDiff) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 		}
 		bd.loading = false
 		bd.diff = msg.diff
+		bd.semantic = msg.semantic
 		bd.resize(s)
 		return bd, nil
 	case tea.KeyMsg:
@@ -82,7 +91,7 @@ func (bd *backupDiff) renderDiff(s *Session) string {
 	if bd.diff == "" {
 		return th.OK.Render("identical - this backup matches the current config")
 	}
-	var out []byte
+	out := []byte(bd.renderSummary(s))
 	for _, line := range splitLinesKeep(bd.diff) {
 		switch {
 		case len(line) > 0 && line[0] == '+':
@@ -99,6 +108,54 @@ func (bd *backupDiff) renderDiff(s *Session) string {
 	return string(out)
 }
 
+// renderSummary lists what the backup → current change means, above the raw
+// diff. Password values are never shown.
+func (bd *backupDiff) renderSummary(s *Session) string {
+	th := s.Theme
+	d := bd.semantic
+	if d.Empty() {
+		return ""
+	}
+	var sb strings.Builder
+	for _, l := range []struct {
+		label string
+		c     domain.ListChange
+	}{{"Mods", d.Mods}, {"Workshop items", d.WorkshopItems}, {"Maps", d.Maps}} {
+		if !l.c.Empty() {
+			sb.WriteString(th.Subtitle.Render(l.label+":") + " " + listChangeText(l.c) + "\n")
+		}
+	}
+	for _, k := range d.Keys {
+		switch {
+		case k.Added:
+			sb.WriteString(th.OK.Render("+ "+k.Key) + "\n")
+		case k.Removed:
+			sb.WriteString(th.Error.Render("- "+k.Key) + "\n")
+		case serverconfig.IsSecretKey(k.Key):
+			sb.WriteString(th.Warn.Render("~ "+k.Key) + th.Muted.Render(" (changed)") + "\n")
+		default:
+			sb.WriteString(th.Warn.Render("~ "+k.Key) + " " + th.Muted.Render(k.Old+" → "+k.New) + "\n")
+		}
+	}
+	sb.WriteString("\n")
+	return sb.String()
+}
+
+// listChangeText is a one-line summary such as "+2 -1 reordered".
+func listChangeText(c domain.ListChange) string {
+	var parts []string
+	if n := len(c.Added); n > 0 {
+		parts = append(parts, fmt.Sprintf("+%d", n))
+	}
+	if n := len(c.Removed); n > 0 {
+		parts = append(parts, fmt.Sprintf("-%d", n))
+	}
+	if c.Reordered {
+		parts = append(parts, "reordered")
+	}
+	return strings.Join(parts, " ")
+}
+
 func (bd *backupDiff) View(s *Session) string {
 	th := s.Theme
 	if bd.loading {
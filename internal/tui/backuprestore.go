@@ -0,0 +1,172 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+)
+
+type restoreRow struct {
+	label  string
+	detail string
+	part   string // "mods", "items", "maps", or "" for a key
+	key    string
+	on     bool
+}
+
+// partialRestore lets the user pick which parts of a backup to copy into the
+// in-memory config. Nothing is written until the config is saved.
+type partialRestore struct {
+	backupID string
+	src      *serverconfig.Config
+	rows     []restoreRow
+	cursor   int
+	loading  bool
+}
+
+// NewPartialRestore returns the picker for restoring parts of a backup.
+func NewPartialRestore(backupID string) Screen {
+	return &partialRestore{backupID: backupID, loading: true}
+}
+
+func (pr *partialRestore) Title() string { return "Restore parts" }
+
+type restoreSourceMsg struct {
+	src *serverconfig.Config
+	err error
+}
+
+func (pr *partialRestore) Init(s *Session) tea.Cmd {
+	pid, id := s.Profile.ID, pr.backupID
+	return s.Do(func(ctx context.Context) tea.Msg {
+		src, err := s.Svc.LoadBackup(pid, id)
+		return restoreSourceMsg{src: src, err: err}
+	})
+}
+
+// restoreRows lists every part of the backup that differs from the current
+// config, all unticked. d compares the current config (old) with the backup.
+func restoreRows(d serverconfig.Diff) []restoreRow {
+	var rows []restoreRow
+	for _, l := range []struct {
+		label, part string
+		c           domain.ListChange
+	}{
+		{"Mods", "mods", d.Mods},
+		{"Workshop items", "items", d.WorkshopItems},
+		{"Maps", "maps", d.Maps},
+	} {
+		if !l.c.Empty() {
+			rows = append(rows, restoreRow{label: l.label, detail: listChangeText(l.c), part: l.part})
+		}
+	}
+	for _, k := range d.Keys {
+		var detail string
+		switch {
+		case k.Removed:
+			continue // only in the current config: nothing to restore
+		case k.Added:
+			detail = "not in current config"
+		case serverconfig.IsSecretKey(k.Key):
+			detail = "changed"
+		default:
+			detail = k.Old + " → " + k.New
+		}
+		rows = append(rows, restoreRow{label: k.Key, detail: detail, key: k.Key})
+	}
+	return rows
+}
+
+func (pr *partialRestore) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
+	switch msg := msg.(type) {
+	case restoreSourceMsg:
+		if msg.err != nil {
+			return pr, tea.Batch(Fail(msg.err), Pop())
+		}
+		pr.loading = false
+		pr.src = msg.src
+		pr.rows = restoreRows(serverconfig.Compare(s.Cfg, msg.src))
+		return pr, nil
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return pr, Pop()
+		case "up", "k":
+			if pr.cursor > 0 {
+				pr.cursor--
+			}
+		case "down", "j":
+			if pr.cursor < len(pr.rows)-1 {
+				pr.cursor++
+			}
+		case " ":
+			if pr.cursor < len(pr.rows) {
+				pr.rows[pr.cursor].on = !pr.rows[pr.cursor].on
+			}
+		case "enter":
+			return pr, pr.apply(s)
+		}
+	}
+	return pr, nil
+}
+
+func (pr *partialRestore) selection() serverconfig.Selection {
+	var sel serverconfig.Selection
+	for _, r := range pr.rows {
+		if !r.on {
+			continue
+		}
+		switch r.part {
+		case "mods":
+			sel.Mods = true
+		case "items":
+			sel.WorkshopItems = true
+		case "maps":
+			sel.Maps = true
+		default:
+			sel.Keys = append(sel.Keys, r.key)
+		}
+	}
+	return sel
+}
+
+func (pr *partialRestore) apply(s *Session) tea.Cmd {
+	sel := pr.selection()
+	if pr.src == nil || sel.Empty() {
+		return Toast("nothing selected")
+	}
+	s.Edit("restore parts of "+pr.backupID, func() { s.Cfg.Splice(pr.src, sel) })
+	n := 0
+	for _, r := range pr.rows {
+		if r.on {
+			n++
+		}
+	}
+	toast := fmt.Sprintf("restored %d part(s) of %s (unsaved)", n, pr.backupID)
+	return tea.Batch(Pop(), func() tea.Msg { return modsChangedMsg{toast: toast} })
+}
+
+func (pr *partialRestore) View(s *Session) string {
+	th := s.Theme
+	if pr.loading {
+		return pad(th.Muted.Render("loading backup…"))
+	}
+	var b strings.Builder
+	b.WriteString(th.Subtitle.Render("Restore parts of "+pr.backupID) + "\n\n")
+	if len(pr.rows) == 0 {
+		b.WriteString(th.OK.Render("identical - this backup matches the current config") + "\n")
+	}
+	for i, r := range pr.rows {
+		box := "[ ] "
+		if r.on {
+			box = th.OK.Render("[x] ")
+		}
+		b.WriteString(renderRow(th, s.ContentWidth(), cursorPrefix(th, i == pr.cursor)+box, r.label, r.detail, i == pr.cursor) + "\n")
+	}
+	b.WriteString("\n" + th.Muted.Render("space: toggle   enter: restore into config (unsaved)   esc: cancel"))
+	return pad(b.String())
+}
@@ -0,0 +1,30 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+)
+
+func TestPartialRestoreRows(t *testing.T) {
+	current := serverconfig.FromBytes("c.ini", []byte("PublicName=New\nPassword=b\nMods=c\nWorkshopItems=1\nExtra=1\n"))
+	backup := serverconfig.FromBytes("", []byte("PublicName=Old\nPassword=a\nMods=a;b\nWorkshopItems=1\nPVP=false\n"))
+
+	pr := &partialRestore{backupID: "x", src: backup, rows: restoreRows(serverconfig.Compare(current, backup))}
+	var labels []string
+	for _, r := range pr.rows {
+		labels = append(labels, r.label+": "+r.detail)
+	}
+	want := []string{"Mods: +2 -1", "PublicName: New → Old", "Password: changed", "PVP: not in current config"}
+	if !reflect.DeepEqual(labels, want) {
+		t.Fatalf("rows = %q\nwant %q", labels, want)
+	}
+
+	pr.rows[0].on = true
+	pr.rows[3].on = true
+	sel := pr.selection()
+	if !sel.Mods || sel.WorkshopItems || !reflect.DeepEqual(sel.Keys, []string{"PVP"}) {
+		t.Errorf("selection = %+v; want mods + PVP", sel)
+	}
+}
@@ -25,6 +25,8 @@ func NewBackups() Screen { return &backups{loading: true} }
 
 func (b *backups) Title() string { return "Backups" }
 
+func (b *backups) claimsKey(string) bool { return b.filter.active }
+
 type backupsLoadedMsg struct {
 	entries []store.BackupEntry
 	err     error
@@ -96,6 +98,10 @@ func (b *backups) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 			}
 		case "s":
 			return b, b.snapshot(s)
+		case "p":
+			if e, ok := b.current(); ok {
+				return b, b.pinCmd(s, e.ID, !e.Pinned)
+			}
 		case "d":
 			if e, ok := b.current(); ok {
 				return b, Confirm("Delete backup "+e.ID+"?", b.deleteCmd(s, e.ID))
@@ -104,6 +110,10 @@ func (b *backups) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 			if e, ok := b.current(); ok {
 				return b, Push(NewBackupDiff(s.Profile.ID, e.ID, s.Profile.IniPath))
 			}
+		case "R":
+			if e, ok := b.current(); ok {
+				return b, Push(NewPartialRestore(e.ID))
+			}
 		case "r":
 			if e, ok := b.current(); ok {
 				return b, Confirm("Restore "+e.ID+"? (current config is backed up first)", b.restoreCmd(s, e.ID))
@@ -161,11 +171,21 @@ func (b *backups) deleteCmd(s *Session, id string) tea.Cmd {
 	}
 }
 
-func (b *backups) restoreCmd(s *Session, id string) tea.Cmd {
+func (b *backups) pinCmd(s *Session, id string, pinned bool) tea.Cmd {
 	pid := s.Profile.ID
-	path := s.Profile.IniPath
 	return func() tea.Msg {
-		if err := s.Store.Restore(pid, id, path); err != nil {
+		if err := s.Store.PinBackup(pid, id, pinned); err != nil {
+			return ErrMsg{Err: err}
+		}
+		return reloadBackupsMsg{}
+	}
+}
+
+func (b *backups) restoreCmd(s *Session, id string) tea.Cmd {
+	profile := *s.Profile
+	path := profile.IniPath
+	return func() tea.Msg {
+		if _, err := s.Svc.RestoreBackup(profile, id); err != nil {
 			return ErrMsg{Err: err}
 		}
 		cfg, err := serverconfig.Load(path)
@@ -173,6 +193,7 @@ func (b *backups) restoreCmd(s *Session, id string) tea.Cmd {
 			return ErrMsg{Err: err}
 		}
 		s.Cfg = cfg
+		s.ClearHistory()
 		return restoredMsg{}
 	}
 }
@@ -211,13 +232,17 @@ func (b *backups) View(s *Session) string {
 		if e.Note != "" {
 			left += " - " + e.Note
 		}
-		right := metaLine("["+e.Kind+"]", humanize.Bytes(uint64(e.Size)))
+		pin := ""
+		if e.Pinned {
+			pin = "pinned"
+		}
+		right := metaLine(pin, "["+e.Kind+"]", humanize.Bytes(uint64(e.Size)))
 		sb.WriteString(renderRow(th, s.ContentWidth(), cursorPrefix(th, sel), left, right, sel) + "\n")
 	}
 	if bEnd < len(sh) {
 		sb.WriteString(th.Muted.Render(fmt.Sprintf("  ↓ %d more", len(sh)-bEnd)) + "\n")
 	}
 
-	sb.WriteString("\n" + th.Muted.Render("enter/v: diff   r: restore   d: delete   s: snapshot   /: filter   esc: back"))
+	sb.WriteString("\n" + th.Muted.Render("enter/v: diff   r: restore   R: restore parts   d: delete   s: snapshot   p: pin   /: filter   esc: back"))
 	return pad(sb.String())
 }
@@ -8,6 +8,7 @@ import (
 	tea "github.com/charmbracelet/bubbletea"
 	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/service"
 )
 
 // dashboard is the main menu for the open profile.
@@ -66,9 +67,9 @@ func (d *dashboard) Init(s *Session) tea.Cmd {
 	}
 	s.Validated = true
 	sm := s.Cfg.ServerMods()
-	b := s.Build()
+	profile := s.Profile
 	return s.Do(func(ctx context.Context) tea.Msg {
-		report, err := s.Svc.Validate(ctx, sm, b)
+		report, err := s.validate(ctx, profile, sm)
 		if err != nil {
 			return nil
 		}
@@ -133,6 +134,9 @@ func validationSummary(s *Session) string {
 	}
 	e := r.Count(domain.SeverityError)
 	w := r.Count(domain.SeverityWarning)
+	if r.Incomplete() {
+		return th.Warn.Render(fmt.Sprintf("%d error(s), %d warning(s); %d item(s) unchecked (last check)", e, w, len(r.Unchecked)))
+	}
 	switch {
 	case e > 0:
 		return th.Error.Render(fmt.Sprintf("⚠ %d error(s), %d warning(s) (last check)", e, w))
@@ -143,7 +147,8 @@ func validationSummary(s *Session) string {
 	}
 }
 
-// saveCmd snapshots the config then writes it.
+// saveCmd snapshots the config then writes it, recording the save in the
+// profile's operation journal.
 func saveCmd(s *Session) tea.Cmd {
 	return func() tea.Msg {
 		if s.Cfg == nil {
@@ -153,9 +158,11 @@ func saveCmd(s *Session) tea.Cmd {
 			return ToastMsg{Text: "nothing to save"}
 		}
 		if s.Profile != nil {
-			if _, err := s.Svc.SnapshotProfile(*s.Profile, "before save", "auto"); err != nil {
+			op := service.Op{Command: "tui save", Note: "before save"}
+			if _, err := s.Svc.ApplyChange(*s.Profile, s.Cfg, op); err != nil {
 				return ErrMsg{Err: err}
 			}
+			return ToastMsg{Text: "saved"}
 		}
 		if err := s.Cfg.Save(); err != nil {
 			return ErrMsg{Err: err}
@@ -40,6 +40,9 @@ func NewDeps(seeds []string) Screen { return &deps{seeds: seeds, loading: true,
 
 func (d *deps) Title() string { return "Resolve dependencies" }
 
+// "u" lists the unavailable items here.
+func (d *deps) claimsKey(key string) bool { return key == "u" }
+
 type depsResolvedMsg struct {
 	plan service.ResolvePlan
 	err  error
@@ -176,7 +179,7 @@ func (d *deps) apply(s *Session) tea.Cmd {
 		}
 		n++
 	}
-	s.Cfg.ApplyServerMods(sm)
+	s.Edit(fmt.Sprintf("add %d dependencies", n), func() { s.Cfg.ApplyServerMods(sm) })
 	return tea.Batch(Toast(fmt.Sprintf("added %d item(s) (unsaved)", n)), Pop())
 }
 
@@ -230,6 +233,9 @@ func (d *deps) View(s *Session) string {
 	if len(d.plan.Missing) > 0 {
 		b.WriteString(th.Warn.Render(fmt.Sprintf("  ⚠ %d unavailable (delisted/private) - not added", len(d.plan.Missing))) + "\n")
 	}
+	if len(d.plan.Unresolved) > 0 {
+		b.WriteString(th.Warn.Render(fmt.Sprintf("  ⚠ Steam did not answer for %d item(s) - not added, dependencies unknown", len(d.plan.Unresolved))) + "\n")
+	}
 
 	b.WriteString("\n")
 	b.WriteString(th.Muted.Render(fmt.Sprintf("selected total: %s", humanize.Bytes(total))) + "\n")
@@ -31,7 +31,7 @@ func openedModelAt(t *testing.T, fake *steamtest.Fake, iniContent string, initia
 	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef") // avoid the first-run key prompt
 
 	m := New(nil, st, context.Background(), initial)
-	m.s.NewSteam = func(string) steam.API { return fake }
+	m.s.NewSteam = func(string, string) (steam.API, error) { return fake, nil }
 	if err := m.s.OpenProfile(p); err != nil {
 		t.Fatal(err)
 	}
@@ -11,6 +11,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/kldzj/pzmod/internal/bbcode"
 	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
 	"github.com/kldzj/pzmod/internal/openurl"
 	"github.com/kldzj/pzmod/pkg/steam"
 )
@@ -21,6 +22,7 @@ type detail struct {
 	id      string
 	item    *steam.WorkshopItem
 	parsed  *steam.ParsedItem
+	infos   []modinfo.ModInfo // mod.info files read from the downloaded item
 	vp      viewport.Model
 	load    loader
 	loading bool
@@ -33,8 +35,9 @@ func NewDetail(id string) Screen { return &detail{id: id, loading: true, load: n
 func (d *detail) Title() string { return "Mod details" }
 
 type detailLoadedMsg struct {
-	item *steam.WorkshopItem
-	err  error
+	item  *steam.WorkshopItem
+	infos []modinfo.ModInfo
+	err   error
 }
 
 type detailRemovePlanMsg struct {
@@ -46,6 +49,7 @@ type detailRemovedMsg struct{ title string }
 
 func (d *detail) Init(s *Session) tea.Cmd {
 	id := d.id
+	profile := s.Profile
 	return tea.Batch(d.load.tick(), s.Do(func(ctx context.Context) tea.Msg {
 		items, _, err := s.Svc.Details(ctx, []string{id})
 		if err != nil {
@@ -54,7 +58,12 @@ func (d *detail) Init(s *Session) tea.Cmd {
 		if len(items) == 0 {
 			return detailLoadedMsg{err: fmt.Errorf("item %s not found", id)}
 		}
-		return detailLoadedMsg{item: &items[0]}
+		s.Svc.NameCreators(ctx, items)
+		var infos []modinfo.ModInfo
+		if profile != nil {
+			infos = s.Svc.ItemModInfos(*profile, items[0])
+		}
+		return detailLoadedMsg{item: &items[0], infos: infos}
 	}))
 }
 
@@ -75,6 +84,7 @@ func (d *detail) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 		}
 		d.item = msg.item
 		d.parsed = msg.item.Parse()
+		d.infos = msg.infos
 		d.loading = false
 		d.resize(s)
 		return d, nil
@@ -89,7 +99,7 @@ func (d *detail) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 		plan := msg.plan
 		title := msg.title
 		return d, Confirm("Remove "+title+"? ("+desc+")", func() tea.Msg {
-			s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods()))
+			s.Edit("remove "+title, func() { s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods())) })
 			return detailRemovedMsg{title: title}
 		})
 	case detailRemovedMsg:
@@ -121,6 +131,10 @@ func (d *detail) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 			if d.item != nil {
 				return d, Push(NewDeps([]string{d.item.PublishedFileID}))
 			}
+		case "m":
+			if d.item != nil && d.item.Creator != "" {
+				return d, Push(NewAuthor(d.item.Creator, d.item.PublishedFileID))
+			}
 		}
 	}
 	if d.ready {
@@ -214,6 +228,9 @@ func (d *detail) View(s *Session) string {
 	b.WriteString("\n")
 
 	meta := []string{humanize.Bytes(uint64(d.item.FileSize))}
+	if d.item.CreatorName != "" {
+		meta = append(meta, "by "+d.item.CreatorName)
+	}
 	if d.item.TimeUpdated > 0 {
 		meta = append(meta, "updated "+relTime(d.item.TimeUpdated))
 	}
@@ -230,6 +247,13 @@ func (d *detail) View(s *Session) string {
 	if len(d.parsed.Mods) > 0 {
 		b.WriteString(th.Muted.Render("mods: ") + strings.Join(d.parsed.Mods, ", ") + "\n")
 	}
+	if len(d.infos) > 0 {
+		origins := make([]string, len(d.infos))
+		for i, mi := range d.infos {
+			origins[i] = mi.ID + " from " + mi.Origin()
+		}
+		b.WriteString(th.Muted.Render("mod.info: ") + strings.Join(origins, ", ") + "\n")
+	}
 	if len(d.parsed.Maps) > 0 {
 		b.WriteString(th.Muted.Render("maps: ") + strings.Join(d.parsed.Maps, ", ") + "\n")
 	}
@@ -239,11 +263,14 @@ func (d *detail) View(s *Session) string {
 	b.WriteString(th.Muted.Render(hyperlink(d.item.WorkshopURL(), "open in Steam ↗")) + "\n\n")
 
 	b.WriteString(d.vp.View() + "\n")
+	hint := "a: add   d: deps   "
 	if installedSet(s)[d.item.PublishedFileID] {
-		b.WriteString(th.Muted.Render("a: add   d: deps   x: remove   o: open   esc: back"))
-	} else {
-		b.WriteString(th.Muted.Render("a: add   d: deps   o: open   esc: back"))
+		hint += "x: remove   "
+	}
+	if d.item.Creator != "" {
+		hint += "m: more by author   "
 	}
+	b.WriteString(th.Muted.Render(hint + "o: open   esc: back"))
 	return pad(b.String())
 }
 
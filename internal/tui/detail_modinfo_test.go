@@ -0,0 +1,24 @@
+package tui
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/kldzj/pzmod/pkg/modinfo"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+// TestDetailShowsModInfoFolder: a downloaded Build 42 mod's details name the
+// version folder its mod.info came from.
+func TestDetailShowsModInfoFolder(t *testing.T) {
+	s := &Session{Cfg: serverconfig.FromBytes("server.ini", []byte("WorkshopItems=100\nMods=FooMod\n")),
+		Theme: DefaultTheme(), Width: 100, Height: 30}
+	it := steam.WorkshopItem{Result: 1, PublishedFileID: "100", Title: "Foo", Description: "Mod ID: FooMod\n"}
+	d := NewDetail("100").(*detail)
+	d.Update(s, detailLoadedMsg{item: &it, infos: []modinfo.ModInfo{{ID: "FooMod", VersionDir: "42.3"}}})
+
+	if view := d.View(s); !strings.Contains(view, "FooMod from 42.3/") {
+		t.Errorf("detail view lacks the mod.info folder:\n%s", view)
+	}
+}
@@ -36,7 +36,7 @@ func TestSettingsSaveReturnsToLauncher(t *testing.T) {
 	}
 
 	m := New(service.New(steamtest.New(), st), st, context.Background(), NewLauncher())
-	m.s.NewSteam = func(string) steam.API { return steamtest.New() }
+	m.s.NewSteam = func(string, string) (steam.API, error) { return steamtest.New(), nil }
 	tm := teatest.NewTestModel(t, m, teatest.WithInitialTermSize(100, 30))
 	tm.Send(tea.WindowSizeMsg{Width: 100, Height: 30})
 
@@ -9,6 +9,7 @@ import (
 	"github.com/dustin/go-humanize"
 	"github.com/kldzj/pzmod/pkg/domain"
 	"github.com/kldzj/pzmod/internal/openurl"
+	"github.com/kldzj/pzmod/pkg/modinfo"
 	"github.com/kldzj/pzmod/pkg/steam"
 )
 
@@ -18,7 +19,9 @@ type installedRow struct {
 	size  uint64
 	mods  int
 	maps  int
-	ok    bool // details fetched
+	by    string // creator's persona name, when resolved
+	ok    bool   // details fetched
+	local bool   // a mod from the local mods directory; id is its mod ID
 }
 
 // installed is the item-centric view of what the profile has added.
@@ -36,8 +39,11 @@ func NewInstalled() Screen { return &installed{loading: true, load: newLoader()}
 
 func (in *installed) Title() string { return "Installed Mods" }
 
+func (in *installed) claimsKey(string) bool { return in.filter.active }
+
 type installedLoadedMsg struct {
 	items []steam.WorkshopItem
+	local []modinfo.ModInfo
 	err   error
 }
 
@@ -47,9 +53,15 @@ func (in *installed) Init(s *Session) tea.Cmd {
 
 func (in *installed) reload(s *Session) tea.Cmd {
 	ids := append([]string(nil), s.Cfg.WorkshopItems()...)
+	profile := s.Profile
 	return s.Do(func(ctx context.Context) tea.Msg {
 		items, _, err := s.Svc.Details(ctx, ids)
-		return installedLoadedMsg{items: items, err: err}
+		s.Svc.NameCreators(ctx, items)
+		var local []modinfo.ModInfo
+		if profile != nil && err == nil {
+			local, err = s.Svc.LocalMods(*profile)
+		}
+		return installedLoadedMsg{items: items, local: local, err: err}
 	})
 }
 
@@ -66,13 +78,15 @@ func (in *installed) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 		if msg.err != nil {
 			return in, Fail(msg.err)
 		}
-		in.build(s, msg.items)
+		in.build(s, msg.items, msg.local)
 		return in, nil
 	// Both modsChangedMsg and resumedMsg can fire on a single add; the reload is idempotent.
 	case modsChangedMsg:
 		return in, in.reload(s)
 	case resumedMsg:
 		return in, in.reload(s)
+	case editSteppedMsg:
+		return in, in.reload(s)
 	case tea.KeyMsg:
 		if in.filter.active {
 			if in.filter.handleKey(msg) {
@@ -111,6 +125,9 @@ func (in *installed) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 			in.cursor = max(0, len(in.shown())-1)
 		case "enter":
 			if r, ok := in.current(); ok {
+				if r.local {
+					return in, Toast("local mod - not on the Workshop")
+				}
 				return in, Push(NewDetail(r.id))
 			}
 		case "a":
@@ -121,6 +138,9 @@ func (in *installed) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 			}
 		case "o":
 			if r, ok := in.current(); ok {
+				if r.local {
+					return in, Toast("local mod - not on the Workshop")
+				}
 				tmp := steam.WorkshopItem{PublishedFileID: r.id}
 				_ = openurl.Open(tmp.WorkshopURL())
 				return in, Toast("opening in browser…")
@@ -130,7 +150,7 @@ func (in *installed) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 	return in, nil
 }
 
-func (in *installed) build(s *Session, items []steam.WorkshopItem) {
+func (in *installed) build(s *Session, items []steam.WorkshopItem, local []modinfo.ModInfo) {
 	byID := map[string]steam.WorkshopItem{}
 	for _, it := range items {
 		byID[it.PublishedFileID] = it
@@ -147,21 +167,34 @@ func (in *installed) build(s *Session, items []steam.WorkshopItem) {
 		in.decl[id] = domain.ModDecl{Mods: p.Mods, Maps: p.Maps}
 		in.rows = append(in.rows, installedRow{
 			id: id, title: itemTitle(&it), size: uint64(it.FileSize),
-			mods: len(p.Mods), maps: len(p.Maps), ok: true,
+			mods: len(p.Mods), maps: len(p.Maps), by: it.CreatorName, ok: true,
 		})
 	}
+	// Enabled mods from the local mods directory follow the Workshop items.
+	sm := s.Cfg.ServerMods()
+	for _, mi := range local {
+		if !sm.HasMod(mi.ID) {
+			continue
+		}
+		title := mi.Name
+		if title == "" {
+			title = mi.ID
+		}
+		in.decl[mi.ID] = domain.ModDecl{Mods: []string{mi.ID}, Maps: mi.Maps}
+		in.rows = append(in.rows, installedRow{id: mi.ID, title: title, mods: 1, maps: len(mi.Maps), ok: true, local: true})
+	}
 	in.clampCursor()
 }
 
-// shown returns the rows matching the current filter (title, workshop ID, and
-// declared mod IDs / map names).
+// shown returns the rows matching the current filter (title, workshop ID,
+// author, and declared mod IDs / map names).
 func (in *installed) shown() []installedRow {
 	if !in.filter.has() {
 		return in.rows
 	}
 	var out []installedRow
 	for _, r := range in.rows {
-		fields := []string{r.title, r.id}
+		fields := []string{r.title, r.id, r.by}
 		if d, ok := in.decl[r.id]; ok {
 			fields = append(fields, d.Mods...)
 			fields = append(fields, d.Maps...)
@@ -191,6 +224,12 @@ func (in *installed) current() (installedRow, bool) {
 }
 
 func (in *installed) confirmRemove(s *Session, r installedRow) tea.Cmd {
+	if r.local {
+		return Confirm("Disable local mod "+r.title+"? (mod "+r.id+")", func() tea.Msg {
+			s.Edit("remove "+r.title, func() { s.Cfg.ApplyServerMods(s.Cfg.ServerMods().RemoveMod(r.id)) })
+			return modsChangedMsg{toast: "disabled " + r.title}
+		})
+	}
 	plan := domain.PlanRemoval(r.id, in.decl, s.Cfg.ServerMods())
 	desc := "item " + r.id
 	if len(plan.Mods) > 0 {
@@ -200,7 +239,7 @@ func (in *installed) confirmRemove(s *Session, r installedRow) tea.Cmd {
 		desc += " + map " + strings.Join(plan.Maps, ", ")
 	}
 	return Confirm("Remove "+r.title+"? ("+desc+")", func() tea.Msg {
-		s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods()))
+		s.Edit("remove "+r.title, func() { s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods())) })
 		return modsChangedMsg{toast: "removed " + r.title}
 	})
 }
@@ -230,8 +269,16 @@ func (in *installed) View(s *Session) string {
 		r := rows[i]
 		sel := i == in.cursor
 		right := ""
-		if r.ok {
+		if r.local {
+			right = metaLine(th.Chip.Render("local"), "mod "+r.id)
+			if r.maps > 0 {
+				right = metaLine(right, fmt.Sprintf("%d maps", r.maps))
+			}
+		} else if r.ok {
 			right = metaLine(humanize.Bytes(r.size), modsMapsLabel(r.mods, r.maps))
+			if r.by != "" {
+				right = metaLine(right, "by "+r.by)
+			}
 		} else {
 			right = th.Warn.Render("unavailable")
 		}
@@ -13,6 +13,8 @@ type KeyMap struct {
 	Quit  key.Binding
 	Help  key.Binding
 	Save  key.Binding
+	Undo  key.Binding
+	Redo  key.Binding
 }
 
 // DefaultKeyMap returns the standard bindings.
@@ -27,6 +29,8 @@ func DefaultKeyMap() KeyMap {
 		Quit:  key.NewBinding(key.WithKeys("ctrl+c"), key.WithHelp("ctrl+c", "quit")),
 		Help:  key.NewBinding(key.WithKeys("?"), key.WithHelp("?", "help")),
 		Save:  key.NewBinding(key.WithKeys("ctrl+s"), key.WithHelp("ctrl+s", "save")),
+		Undo:  key.NewBinding(key.WithKeys("u"), key.WithHelp("u", "undo edit")),
+		Redo:  key.NewBinding(key.WithKeys("ctrl+r"), key.WithHelp("ctrl+r", "redo edit")),
 	}
 }
 
@@ -40,6 +44,7 @@ func (k KeyMap) FullHelp() [][]key.Binding {
 	return [][]key.Binding{
 		{k.Up, k.Down, k.Left, k.Right},
 		{k.Enter, k.Back, k.Save},
+		{k.Undo, k.Redo},
 		{k.Help, k.Quit},
 	}
 }
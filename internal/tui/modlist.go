@@ -36,6 +36,8 @@ func NewLoadOrder() Screen { return &modlist{} }
 
 func (m *modlist) Title() string { return "Load order" }
 
+func (m *modlist) claimsKey(string) bool { return m.filter.active }
+
 type loadOrderMsg struct {
 	plan domain.OrderPlan
 	err  error
@@ -59,14 +61,24 @@ func (m *modlist) list() []string {
 	return m.mods
 }
 
-func (m *modlist) setList(s *Session, v []string) {
-	if m.tab == tabMaps {
-		m.maps = v
-		s.Cfg.SetMaps(v)
-	} else {
-		m.mods = v
-		s.Cfg.SetMods(v)
-	}
+// setList replaces the active list, recording it as an undoable edit.
+func (m *modlist) setList(s *Session, label string, v []string) {
+	s.Edit(label, func() {
+		if m.tab == tabMaps {
+			m.maps = v
+			s.Cfg.SetMaps(v)
+		} else {
+			m.mods = v
+			s.Cfg.SetMods(v)
+		}
+	})
+}
+
+// reload re-reads both lists from the config (after an undo or redo).
+func (m *modlist) reload(s *Session) {
+	m.mods = append([]string(nil), s.Cfg.Mods()...)
+	m.maps = append([]string(nil), s.Cfg.Maps()...)
+	m.clampCursor()
 }
 
 func (m *modlist) suggestion() *domain.OrderPlan {
@@ -128,6 +140,9 @@ func (m *modlist) exitFilter() {
 
 func (m *modlist) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 	switch msg := msg.(type) {
+	case editSteppedMsg:
+		m.reload(s)
+		return m, nil
 	case loadOrderMsg:
 		if msg.err == nil {
 			p := msg.plan
@@ -227,7 +242,7 @@ func (m *modlist) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 func (m *modlist) updatePreview(s *Session, msg tea.KeyMsg) (Screen, tea.Cmd) {
 	switch msg.String() {
 	case "y", "enter":
-		m.setList(s, append([]string(nil), m.suggestion().Ordered...))
+		m.setList(s, "apply suggested order", append([]string(nil), m.suggestion().Ordered...))
 		m.previewing = false
 		return m, Toast("order applied (unsaved)")
 	case "n", "esc":
@@ -253,9 +268,10 @@ func (m *modlist) shift(s *Session, d int) {
 	if j < 0 || j >= len(l) {
 		return
 	}
+	item := l[m.cursor]
 	l[m.cursor], l[j] = l[j], l[m.cursor]
 	m.cursor = j
-	m.setList(s, l)
+	m.setList(s, "move "+item, l)
 }
 
 func (m *modlist) moveTo(s *Session, to int) {
@@ -270,7 +286,7 @@ func (m *modlist) moveTo(s *Session, to int) {
 	out = append(out, item)
 	out = append(out, l[to:]...)
 	m.cursor = to
-	m.setList(s, out)
+	m.setList(s, "move "+item, out)
 }
 
 func (m *modlist) View(s *Session) string {
@@ -26,6 +26,8 @@ func NewProfileForm() Screen { return &profileform{} }
 
 func (pf *profileform) Title() string { return "New profile" }
 
+func (pf *profileform) claimsKey(string) bool { return pf.formActive() }
+
 func (pf *profileform) Init(s *Session) tea.Cmd {
 	start, _ := os.UserHomeDir()
 	if start == "" {
@@ -0,0 +1,168 @@
+package tui
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/dustin/go-humanize"
+	"github.com/kldzj/pzmod/internal/openurl"
+	"github.com/kldzj/pzmod/pkg/build"
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+// providers picks which of several installed items provides a clashing mod
+// ID, opened from the mod-id-clash finding on the Validate screen.
+type providers struct {
+	modID   string
+	items   []steam.WorkshopItem
+	pinned  string
+	cursor  int
+	load    loader
+	loading bool
+}
+
+// NewProviders returns the provider picker for modID.
+func NewProviders(modID string) Screen {
+	return &providers{modID: modID, loading: true, load: newLoader()}
+}
+
+func (p *providers) Title() string { return "Provider for " + p.modID }
+
+type providersLoadedMsg struct {
+	items  []steam.WorkshopItem
+	pinned string
+	err    error
+}
+
+type providerPlannedMsg struct {
+	plan domain.ProviderPlan
+	err  error
+}
+
+func (p *providers) Init(s *Session) tea.Cmd {
+	modID, sm := p.modID, s.Cfg.ServerMods()
+	return tea.Batch(p.load.tick(), s.Do(func(ctx context.Context) tea.Msg {
+		clashes, err := s.Svc.Clashes(ctx, sm)
+		if err != nil {
+			return providersLoadedMsg{err: err}
+		}
+		for _, c := range clashes {
+			if c.ModID == modID {
+				return providersLoadedMsg{items: c.Items, pinned: c.Pinned}
+			}
+		}
+		return providersLoadedMsg{}
+	}))
+}
+
+func (p *providers) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
+	if cmd, ok := p.load.update(msg); ok {
+		if p.loading {
+			return p, cmd
+		}
+		return p, nil
+	}
+	switch msg := msg.(type) {
+	case providersLoadedMsg:
+		p.loading = false
+		if msg.err != nil {
+			return p, tea.Batch(Fail(msg.err), Pop())
+		}
+		p.items, p.pinned = msg.items, msg.pinned
+		if len(p.items) < 2 {
+			return p, tea.Batch(Toast("mod ID "+p.modID+" no longer clashes"), Pop())
+		}
+		for i, it := range p.items {
+			if it.PublishedFileID == p.pinned {
+				p.cursor = i
+			}
+		}
+		return p, nil
+	case providerPlannedMsg:
+		if msg.err != nil {
+			return p, Fail(msg.err)
+		}
+		plan := msg.plan
+		s.Edit("pin "+plan.ModID+" to "+plan.Provider, func() {
+			s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods(), s.Build() == build.B42))
+		})
+		text := fmt.Sprintf("%s now provided by %s (unsaved)", plan.ModID, plan.Provider)
+		if len(plan.Drop) > 0 {
+			text += fmt.Sprintf(", dropped %d item(s)", len(plan.Drop))
+		}
+		if len(plan.Kept) > 0 {
+			text += fmt.Sprintf(", kept %d that provide other mods", len(plan.Kept))
+		}
+		return p, tea.Batch(Toast(text), Pop())
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "esc":
+			return p, Pop()
+		case "up", "k":
+			if p.cursor > 0 {
+				p.cursor--
+			}
+		case "down", "j":
+			if p.cursor < len(p.items)-1 {
+				p.cursor++
+			}
+		case "enter":
+			if p.cursor < len(p.items) {
+				return p, p.pin(s, p.items[p.cursor].PublishedFileID, false)
+			}
+		case "d":
+			if p.cursor < len(p.items) {
+				id := p.items[p.cursor].PublishedFileID
+				return p, Confirm("Keep "+id+" and remove the other items declaring "+p.modID+"?", p.pin(s, id, true))
+			}
+		case "o":
+			if p.cursor < len(p.items) {
+				_ = openurl.Open(p.items[p.cursor].WorkshopURL())
+				return p, Toast("opening in browser…")
+			}
+		}
+	}
+	return p, nil
+}
+
+func (p *providers) pin(s *Session, item string, drop bool) tea.Cmd {
+	modID, sm := p.modID, s.Cfg.ServerMods()
+	return s.Do(func(ctx context.Context) tea.Msg {
+		plan, err := s.Svc.PlanProvider(ctx, sm, modID, item, drop)
+		return providerPlannedMsg{plan: plan, err: err}
+	})
+}
+
+func (p *providers) View(s *Session) string {
+	th := s.Theme
+	if p.loading {
+		return pad(p.load.view(th, "loading the items that declare "+p.modID+"…"))
+	}
+	var b strings.Builder
+	b.WriteString(th.Muted.Render(fmt.Sprintf("%d installed items declare mod ID %s; choose the one that provides it", len(p.items), p.modID)) + "\n\n")
+	h := max(3, s.BodyHeight()-5)
+	start, end := listWindow(p.cursor, len(p.items), h)
+	if start > 0 {
+		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n")
+	}
+	for i := start; i < end; i++ {
+		it := p.items[i]
+		badge := "  "
+		if it.PublishedFileID == p.pinned {
+			badge = th.OK.Render("● ")
+		}
+		right := metaLine(humanize.Bytes(uint64(it.FileSize)), it.PublishedFileID)
+		b.WriteString(renderRow(th, s.ContentWidth(), badge, itemTitle(&it), right, i == p.cursor) + "\n")
+	}
+	if end < len(p.items) {
+		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↓ %d more", len(p.items)-end)) + "\n")
+	}
+	if s.Build() != build.B42 {
+		b.WriteString("\n" + th.Warn.Render("Build 41 can't pin a provider in Mods=; press d to drop the others") + "\n")
+	}
+	b.WriteString("\n" + th.Muted.Render("enter: pin   d: pin and drop the others   o: open   esc: back"))
+	return pad(b.String())
+}
@@ -37,7 +37,7 @@ func openProfileModel(t *testing.T, dirty bool) (*teatest.TestModel, *model) {
 
 	fake := steamtest.New()
 	m := New(service.New(fake, st), st, context.Background(), NewDashboard())
-	m.s.NewSteam = func(string) steam.API { return fake }
+	m.s.NewSteam = func(string, string) (steam.API, error) { return fake, nil }
 	if err := m.s.OpenProfile(p); err != nil {
 		t.Fatal(err)
 	}
@@ -70,7 +70,7 @@ func openProfileModelWith(t *testing.T, fake *steamtest.Fake, iniBody string) (*
 	}
 	_ = st.SetGlobalKey("0123456789abcdef0123456789abcdef")
 	m := New(service.New(fake, st), st, context.Background(), NewDashboard())
-	m.s.NewSteam = func(string) steam.API { return fake }
+	m.s.NewSteam = func(string, string) (steam.API, error) { return fake, nil }
 	if err := m.s.OpenProfile(p); err != nil {
 		t.Fatal(err)
 	}
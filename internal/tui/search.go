@@ -41,6 +41,9 @@ func NewSearch() Screen {
 
 func (sc *search) Title() string { return "Search Workshop" }
 
+// Typing into the query box takes every key.
+func (sc *search) claimsKey(string) bool { return true }
+
 type debounceMsg struct{ gen int }
 
 type searchResultMsg struct {
@@ -56,9 +59,14 @@ func (sc *search) Init(s *Session) tea.Cmd {
 }
 
 func (sc *search) runSearch(s *Session, gen int, cursor string, appendMode bool) tea.Cmd {
-	q := sc.input.Value()
+	q := steam.Query{SearchText: sc.input.Value(), PerPage: 40, Cursor: cursor}
+	// Like `pzmod search`, default to mods tagged for the profile's build.
+	if tag := s.Build().WorkshopTag(); tag != "" {
+		q.Tags = []string{tag}
+	}
 	return s.Do(func(ctx context.Context) tea.Msg {
-		page, err := s.Svc.Search(ctx, steam.Query{SearchText: q, PerPage: 40, Cursor: cursor})
+		page, err := s.Svc.Search(ctx, q)
+		s.Svc.NameCreators(ctx, page.Items)
 		return searchResultMsg{gen: gen, page: page, err: err, appendMode: appendMode}
 	})
 }
@@ -192,7 +200,11 @@ func (sc *search) View(s *Session) string {
 		if installed[it.PublishedFileID] {
 			badge = th.OK.Render("✓ ")
 		}
-		b.WriteString(renderRow(th, s.ContentWidth(), badge, it.Title, humanize.Bytes(uint64(it.FileSize)), sel) + "\n")
+		right := humanize.Bytes(uint64(it.FileSize))
+		if it.CreatorName != "" {
+			right = metaLine(right, "by "+it.CreatorName)
+		}
+		b.WriteString(renderRow(th, s.ContentWidth(), badge, it.Title, right, sel) + "\n")
 	}
 	if end < len(sc.results) {
 		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↓ %d more", len(sc.results)-end)) + "\n")
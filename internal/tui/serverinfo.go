@@ -20,6 +20,9 @@ func NewServerInfo() Screen { return &serverinfo{} }
 
 func (si *serverinfo) Title() string { return "Server info" }
 
+// Fields are committed on exit, so there is nothing to undo while the form is open.
+func (si *serverinfo) claimsKey(string) bool { return si.form != nil }
+
 func (si *serverinfo) Init(s *Session) tea.Cmd {
 	cfg := s.Cfg
 	si.name = cfg.Name()
@@ -77,14 +80,16 @@ func (si *serverinfo) commit(s *Session) tea.Cmd {
 			changed = true
 		}
 	}
-	set(cfg.Name(), si.name, cfg.SetName)
-	set(cfg.Description(), encodeLINE(si.desc), cfg.SetDescription)
-	set(cfg.Password(), si.password, cfg.SetPassword)
-	set(cfg.MaxPlayers(), si.slots, cfg.SetMaxPlayers)
-	if si.public != cfg.Public() {
-		cfg.SetPublic(si.public)
-		changed = true
-	}
+	s.Edit("edit server info", func() {
+		set(cfg.Name(), si.name, cfg.SetName)
+		set(cfg.Description(), encodeLINE(si.desc), cfg.SetDescription)
+		set(cfg.Password(), si.password, cfg.SetPassword)
+		set(cfg.MaxPlayers(), si.slots, cfg.SetMaxPlayers)
+		if si.public != cfg.Public() {
+			cfg.SetPublic(si.public)
+			changed = true
+		}
+	})
 	if changed {
 		return tea.Batch(Toast("server info updated (unsaved)"), Pop())
 	}
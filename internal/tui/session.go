@@ -28,8 +28,10 @@ type Session struct {
 	Profile *store.Profile
 	Cfg     *serverconfig.Config
 
-	// NewSteam builds a Steam client for a resolved key. Overridable in tests.
-	NewSteam func(key string) steam.API
+	// NewSteam builds a Steam client for a resolved key and the profile whose
+	// network settings apply ("" for the global ones). Set by the CLI (to
+	// honour --offline) and overridable in tests.
+	NewSteam func(key, profileID string) (steam.API, error)
 
 	// LastValidation caches the most recent validation report this session, so
 	// the dashboard can show an at-a-glance status.
@@ -42,6 +44,9 @@ type Session struct {
 	// (empty otherwise, and always empty on dev builds). Set once by a background
 	// check at startup and shown as a hint in the top bar.
 	UpdateLatest string
+
+	// history is the in-memory undo/redo stack of config edits (see Edit).
+	history editHistory
 }
 
 // OpenProfile loads a profile's config and rebuilds the service layer with the
@@ -52,19 +57,28 @@ func (s *Session) OpenProfile(p store.Profile) error {
 		return err
 	}
 	key, _ := s.Store.APIKey(p.ID)
-	s.Svc = service.New(s.newSteam(key), s.Store)
+	api, err := s.newSteam(key, p.ID)
+	if err != nil {
+		return err
+	}
+	s.Svc = service.New(api, s.Store)
 	pp := p
 	s.Profile = &pp
 	s.Cfg = cfg
 	s.Validated = false
+	s.ClearHistory()
 	return nil
 }
 
-func (s *Session) newSteam(key string) steam.API {
+func (s *Session) newSteam(key, profileID string) (steam.API, error) {
 	if s.NewSteam != nil {
-		return s.NewSteam(key)
+		return s.NewSteam(key, profileID)
 	}
-	return steam.New(key)
+	opts, err := service.SteamOptions(s.Store, profileID, false)
+	if err != nil {
+		return nil, err
+	}
+	return steam.NewAPI(key, opts...), nil
 }
 
 // Build returns the active profile's build (Unknown when no profile is open).
@@ -75,6 +89,16 @@ func (s *Session) Build() build.Build {
 	return build.Parse(s.Profile.Build)
 }
 
+// validate runs validation for sm, with the open profile's mod.info checks
+// when there is one. profile is captured by the caller, as commands run off
+// the UI goroutine.
+func (s *Session) validate(ctx context.Context, profile *store.Profile, sm domain.ServerMods) (domain.Report, error) {
+	if profile == nil {
+		return s.Svc.Validate(ctx, sm, build.Unknown)
+	}
+	return s.Svc.ValidateProfile(ctx, *profile, sm)
+}
+
 // Dirty reports whether the open config has unsaved changes.
 func (s *Session) Dirty() bool {
 	return s.Cfg != nil && s.Cfg.HasUnsavedChanges()
@@ -31,6 +31,8 @@ func NewSettings() Screen {
 
 func (st *settings) Title() string { return "Settings" }
 
+func (st *settings) claimsKey(string) bool { return true }
+
 func (st *settings) Init(s *Session) tea.Cmd {
 	st.hasKey = s.Store.HasAPIKey("")
 	return textinput.Blink
@@ -92,7 +94,15 @@ func (st *settings) save(s *Session) tea.Cmd {
 		return Fail(err)
 	}
 	// Rebuild the service layer so subsequent Steam calls use the new key.
-	s.Svc = service.New(steam.New(key), s.Store)
+	profileID := ""
+	if s.Profile != nil {
+		profileID = s.Profile.ID
+	}
+	api, err := s.newSteam(key, profileID)
+	if err != nil {
+		return Fail(err)
+	}
+	s.Svc = service.New(api, s.Store)
 	// Return to the previous screen (the profile menu on first run, the dashboard
 	// from the settings jump) and confirm via a toast, since this screen goes away.
 	return tea.Batch(Toast("API key saved"), Pop())
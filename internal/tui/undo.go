@@ -0,0 +1,96 @@
+package tui
+
+import "bytes"
+
+// maxUndo bounds the in-session undo stack.
+const maxUndo = 100
+
+// editEntry is one labelled step of the in-session undo/redo history. It holds
+// the whole rendered config from the other side of the edit, so undo is
+// byte-exact for the mod lists and scalar fields alike.
+type editEntry struct {
+	label string
+	state []byte
+}
+
+// editHistory is the session's undo/redo stack of in-memory config edits.
+// Saving does not clear it; opening another profile does.
+type editHistory struct {
+	undo, redo []editEntry
+}
+
+// Edit runs fn, which mutates s.Cfg, and records it as one undoable step
+// labelled label. Edits that change nothing are not recorded. Consecutive edits
+// with the same label merge into one step, so moving a mod 40 slots (one
+// "move X" per keypress) is undone in one go.
+func (s *Session) Edit(label string, fn func()) {
+	if s.Cfg == nil {
+		fn()
+		return
+	}
+	before := s.Cfg.Bytes()
+	fn()
+	if bytes.Equal(before, s.Cfg.Bytes()) {
+		return
+	}
+	h := &s.history
+	h.redo = nil
+	if n := len(h.undo); n > 0 && h.undo[n-1].label == label {
+		return // the earlier step already holds the state before this run of edits
+	}
+	h.undo = append(h.undo, editEntry{label: label, state: before})
+	if len(h.undo) > maxUndo {
+		h.undo = h.undo[len(h.undo)-maxUndo:]
+	}
+}
+
+// Undo reverts the newest recorded edit and returns its label.
+func (s *Session) Undo() (string, bool) {
+	return s.history.step(s, &s.history.undo, &s.history.redo)
+}
+
+// Redo re-applies the newest undone edit and returns its label.
+func (s *Session) Redo() (string, bool) {
+	return s.history.step(s, &s.history.redo, &s.history.undo)
+}
+
+// step pops from one stack, pushes the current state onto the other, and
+// resets the config to the popped state.
+func (h *editHistory) step(s *Session, from, to *[]editEntry) (string, bool) {
+	if s.Cfg == nil || len(*from) == 0 {
+		return "", false
+	}
+	e := (*from)[len(*from)-1]
+	*from = (*from)[:len(*from)-1]
+	*to = append(*to, editEntry{label: e.label, state: s.Cfg.Bytes()})
+	s.Cfg.Reset(e.state)
+	return e.label, true
+}
+
+// UndoLabel names the edit Undo would revert ("" when there is none).
+func (s *Session) UndoLabel() string { return topLabel(s.history.undo) }
+
+// RedoLabel names the edit Redo would re-apply ("" when there is none).
+func (s *Session) RedoLabel() string { return topLabel(s.history.redo) }
+
+// ClearHistory drops the undo/redo history (the config was replaced).
+func (s *Session) ClearHistory() { s.history = editHistory{} }
+
+func topLabel(stack []editEntry) string {
+	if len(stack) == 0 {
+		return ""
+	}
+	return stack[len(stack)-1].label
+}
+
+// keyClaimer is implemented by screens that need a key the root model would
+// otherwise treat as global (e.g. "u" while typing into a text field).
+type keyClaimer interface {
+	claimsKey(key string) bool
+}
+
+// claimed reports whether the active screen wants key for itself.
+func claimed(sc Screen, key string) bool {
+	c, ok := sc.(keyClaimer)
+	return ok && c.claimsKey(key)
+}
@@ -0,0 +1,48 @@
+package tui
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+)
+
+func TestSessionUndoRedo(t *testing.T) {
+	orig := "PublicName=A # keep me\nMods=a;b;c\n"
+	s := &Session{Cfg: serverconfig.FromBytes("x.ini", []byte(orig))}
+
+	// Consecutive moves of the same mod merge into one step.
+	for _, mods := range [][]string{{"b", "a", "c"}, {"b", "c", "a"}} {
+		s.Edit("move a", func() { s.Cfg.SetMods(mods) })
+	}
+	s.Edit("edit server info", func() { s.Cfg.SetName("B") })
+	s.Edit("no-op", func() {})
+	if got := s.UndoLabel(); got != "edit server info" {
+		t.Fatalf("UndoLabel = %q; want edit server info", got)
+	}
+
+	if label, ok := s.Undo(); !ok || label != "edit server info" {
+		t.Fatalf("Undo = %q, %v", label, ok)
+	}
+	if label, _ := s.Undo(); label != "move a" {
+		t.Fatalf("second Undo = %q; want move a", label)
+	}
+	if s.Cfg.String() != orig || s.Dirty() {
+		t.Fatalf("after undoing everything = %q (dirty %v); want the original bytes", s.Cfg.String(), s.Dirty())
+	}
+	if _, ok := s.Undo(); ok {
+		t.Error("undo past the first edit should report false")
+	}
+
+	if label, _ := s.Redo(); label != "move a" {
+		t.Fatalf("Redo = %q; want move a", label)
+	}
+	if got := s.Cfg.Mods(); !reflect.DeepEqual(got, []string{"b", "c", "a"}) {
+		t.Errorf("Mods after redo = %v", got)
+	}
+	// A fresh edit drops the redo stack.
+	s.Edit("remove c", func() { s.Cfg.SetMods([]string{"b", "a"}) })
+	if s.RedoLabel() != "" {
+		t.Errorf("RedoLabel = %q; a new edit should clear redo", s.RedoLabel())
+	}
+}
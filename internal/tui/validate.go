@@ -2,6 +2,7 @@ package tui
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"strings"
 
@@ -32,6 +33,8 @@ func NewValidate() Screen { return &validate{loading: true, load: newLoader()} }
 
 func (v *validate) Title() string { return "Validate" }
 
+func (v *validate) claimsKey(string) bool { return v.filter.active }
+
 type validateMsg struct {
 	report domain.Report
 	extra  []domain.Finding
@@ -45,10 +48,9 @@ func (v *validate) Init(s *Session) tea.Cmd { return tea.Batch(v.load.tick(), v.
 func (v *validate) run(s *Session) tea.Cmd {
 	v.loading = true
 	sm := s.Cfg.ServerMods()
-	b := s.Build()
 	profile := s.Profile // captured pointer; may be nil if no profile open
 	return s.Do(func(ctx context.Context) tea.Msg {
-		report, err := s.Svc.Validate(ctx, sm, b)
+		report, err := s.validate(ctx, profile, sm)
 		if err != nil {
 			return validateMsg{err: err}
 		}
@@ -96,8 +98,9 @@ func (v *validate) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 		return v, tea.Batch(v.load.tick(), v.run(s))
 	case resumedMsg:
 		// Re-run when returning from a screen a fix navigated to (Load order via
-		// the mod-order advisory, or the deps resolver via 'r'), so the findings
-		// reflect changes made there instead of going stale.
+		// the mod-order advisory, the provider picker for a clash, or the deps
+		// resolver via 'r'), so the findings reflect changes made there instead
+		// of going stale.
 		return v, tea.Batch(v.load.tick(), v.run(s))
 	case tea.KeyMsg:
 		if v.filter.active {
@@ -126,10 +129,10 @@ func (v *validate) Update(s *Session, msg tea.Msg) (Screen, tea.Cmd) {
 				v.cursor++
 			}
 		case "pgup":
-			h := max(3, s.BodyHeight()-7)
+			h := max(3, s.BodyHeight()-8)
 			v.cursor = max(0, v.cursor-h)
 		case "pgdown":
-			h := max(3, s.BodyHeight()-7)
+			h := max(3, s.BodyHeight()-8)
 			if n := len(v.shownFindings()); n > 0 {
 				v.cursor = min(n-1, v.cursor+h)
 			}
@@ -201,31 +204,57 @@ func (v *validate) fix(s *Session, f domain.Finding) tea.Cmd {
 			if err != nil {
 				return ErrMsg{Err: err}
 			}
-			s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods(), s.Build() == build.B42))
+			s.Edit("add dependencies of "+id, func() { s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods(), s.Build() == build.B42)) })
+			return revalidateMsg{}
+		})
+	case domain.CodeMissingRequire:
+		if s.Profile == nil {
+			return Toast("no automatic fix - " + f.Hint)
+		}
+		mod, profile := f.Subject, *s.Profile
+		return s.Do(func(ctx context.Context) tea.Msg {
+			plan, err := s.Svc.ResolveRequires(ctx, profile, s.Cfg.ServerMods(), []string{mod})
+			if err != nil {
+				return ErrMsg{Err: err}
+			}
+			if len(plan.UnknownRequires) > 0 {
+				if errors.Is(plan.SearchErr, steam.ErrSearchNeedsKey) {
+					return ToastMsg{Text: "can't search the Workshop for " + mod + " without a Steam API key"}
+				}
+				return ToastMsg{Text: "no Workshop item found that provides " + mod}
+			}
+			s.Edit("add provider of "+mod, func() { s.Cfg.ApplyServerMods(plan.Apply(s.Cfg.ServerMods(), s.Build() == build.B42)) })
 			return revalidateMsg{}
 		})
 	case domain.CodeUnusedModID:
-		s.Cfg.ApplyServerMods(s.Cfg.ServerMods().AddMod(domain.FormatModRef("", f.Subject, s.Build() == build.B42)))
+		s.Edit("enable "+f.Subject, func() {
+			s.Cfg.ApplyServerMods(s.Cfg.ServerMods().AddMod(domain.FormatModRef("", f.Subject, s.Build() == build.B42)))
+		})
 		return tea.Batch(Toast("enabled "+f.Subject), func() tea.Msg { return revalidateMsg{} })
 	case domain.CodeUnusedMap:
-		s.Cfg.ApplyServerMods(s.Cfg.ServerMods().AddMap(f.Subject))
+		s.Edit("enable map "+f.Subject, func() { s.Cfg.ApplyServerMods(s.Cfg.ServerMods().AddMap(f.Subject)) })
 		return tea.Batch(Toast("enabled map "+f.Subject), func() tea.Msg { return revalidateMsg{} })
 	case domain.CodeUnknownModID:
-		s.Cfg.ApplyServerMods(s.Cfg.ServerMods().RemoveMod(f.Subject))
+		s.Edit("remove "+f.Subject, func() { s.Cfg.ApplyServerMods(s.Cfg.ServerMods().RemoveMod(f.Subject)) })
 		return tea.Batch(Toast("removed "+f.Subject), func() tea.Msg { return revalidateMsg{} })
-	case domain.CodeDelisted, domain.CodeBanned:
+	case domain.CodeDelisted, domain.CodeNotFound, domain.CodeBanned:
 		id := f.Subject
 		return Confirm("Remove workshop item "+id+"?", func() tea.Msg {
-			s.Cfg.ApplyServerMods(s.Cfg.ServerMods().RemoveItem(id))
+			s.Edit("remove item "+id, func() { s.Cfg.ApplyServerMods(s.Cfg.ServerMods().RemoveItem(id)) })
 			return revalidateMsg{}
 		})
 	case codeMapOrder:
 		plan := domain.SuggestMapOrder(s.Cfg.Maps())
-		s.Cfg.SetMaps(plan.Ordered)
+		s.Edit("reorder maps", func() { s.Cfg.SetMaps(plan.Ordered) })
 		return tea.Batch(Toast("map order updated"), func() tea.Msg { return revalidateMsg{} })
 	case codeModOrder:
 		return Push(NewLoadOrder())
+	case domain.CodeModIDClash:
+		return Push(NewProviders(f.Subject))
 	default:
+		if f.Hint != "" {
+			return Toast("no automatic fix - " + f.Hint)
+		}
 		if findingItemID(f) != "" {
 			return Toast("no automatic fix - press o to open the mod's page")
 		}
@@ -239,9 +268,9 @@ func findingItemID(f domain.Finding) string { return parseWorkshopID(f.Subject)
 
 func actionable(code string) bool {
 	switch code {
-	case domain.CodeMissingDependency, domain.CodeUnusedModID, domain.CodeUnusedMap,
-		domain.CodeUnknownModID, domain.CodeDelisted, domain.CodeBanned,
-		codeMapOrder, codeModOrder:
+	case domain.CodeMissingDependency, domain.CodeMissingRequire, domain.CodeUnusedModID, domain.CodeUnusedMap,
+		domain.CodeUnknownModID, domain.CodeDelisted, domain.CodeNotFound, domain.CodeBanned,
+		domain.CodeModIDClash, codeMapOrder, codeModOrder:
 		return true
 	}
 	return false
@@ -269,6 +298,9 @@ func (v *validate) View(s *Session) string {
 			th.Muted.Render("multiplayer still disables mods as of now") + "\n\n")
 	}
 
+	if v.report.Incomplete() {
+		b.WriteString(th.Warn.Render(fmt.Sprintf("⚠ incomplete: Steam did not answer for %d item(s)", len(v.report.Unchecked))) + "\n\n")
+	}
 	if len(v.findings) == 0 {
 		b.WriteString(th.OK.Render("✓ no problems found") + "\n\n")
 		b.WriteString(th.Muted.Render("esc: back"))
@@ -282,7 +314,7 @@ func (v *validate) View(s *Session) string {
 	if len(shown) == 0 {
 		b.WriteString(th.Muted.Render(fmt.Sprintf("no matches for %q", v.filter.query)) + "\n\n")
 	}
-	h := max(3, s.BodyHeight()-7-v.filter.chrome())
+	h := max(3, s.BodyHeight()-8-v.filter.chrome())
 	start, end := listWindow(v.cursor, len(shown), h)
 	if start > 0 {
 		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↑ %d more", start)) + "\n")
@@ -292,7 +324,7 @@ func (v *validate) View(s *Session) string {
 		sel := i == v.cursor
 		prefix := cursorPrefix(th, sel) + severityTagFor(th, f.Severity) + " "
 		right := "manual"
-		if f.Code == codeModOrder {
+		if f.Code == codeModOrder || f.Code == domain.CodeModIDClash {
 			right = "↵ open"
 		} else if actionable(f.Code) {
 			right = "↵ fix"
@@ -302,6 +334,9 @@ func (v *validate) View(s *Session) string {
 	if end < len(shown) {
 		b.WriteString(th.Muted.Render(fmt.Sprintf("  ↓ %d more", len(shown)-end)) + "\n")
 	}
+	if f, ok := v.current(); ok && f.Hint != "" {
+		b.WriteString(th.Muted.Render("  → "+f.Hint) + "\n")
+	}
 
 	b.WriteString("\n")
 	b.WriteString(fmt.Sprintf("%s  %s  %s\n",
@@ -79,6 +79,18 @@ func TestValidateShowsMissingDependency(t *testing.T) {
 	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
 }
 
+func TestValidateShowsPrivateHint(t *testing.T) {
+	fake := steamtest.New()
+	fake.Results = map[string]steam.Result{"404": steam.ResultAccessDenied}
+	tm, _ := openedModelAt(t, fake, "Mods=\nWorkshopItems=404\n", NewValidate())
+
+	// The only finding is selected, so its hint shows under the list.
+	waitForText(t, tm, "ask the author")
+
+	tm.Send(tea.KeyMsg{Type: tea.KeyCtrlC})
+	tm.WaitFinished(t, teatest.WithFinalTimeout(3*time.Second))
+}
+
 func TestValidateFixMissingDependency(t *testing.T) {
 	fake := steamtest.New(
 		steam.WorkshopItem{Result: 1, FileType: steam.FileTypeMod, PublishedFileID: "200",
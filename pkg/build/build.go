@@ -5,9 +5,12 @@
 package build
 
 import (
+	"sort"
+	"strconv"
 	"strings"
 
 	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
 	"github.com/kldzj/pzmod/pkg/steam"
 )
 
@@ -47,12 +50,38 @@ func (b Build) Label() string {
 	}
 }
 
+// Major returns the game's major version number for the build (41 or 42), or
+// 0 for Unknown.
+func (b Build) Major() int {
+	switch b {
+	case B41:
+		return 41
+	case B42:
+		return 42
+	default:
+		return 0
+	}
+}
+
 // Workshop tag names used to infer per-item build support.
 const (
 	tagB41 = "Build 41"
 	tagB42 = "Build 42"
 )
 
+// WorkshopTag returns the Workshop tag for the build ("" for Unknown), used
+// as the default search filter.
+func (b Build) WorkshopTag() string {
+	switch b {
+	case B41:
+		return tagB41
+	case B42:
+		return tagB42
+	default:
+		return ""
+	}
+}
+
 // itemTags lowercases an item's tag set for matching.
 func itemTags(item steam.WorkshopItem) map[string]bool {
 	tags := make(map[string]bool, len(item.Tags))
@@ -90,6 +119,56 @@ func CompatWarnings(b Build, items []steam.WorkshopItem) []domain.Finding {
 	return findings
 }
 
+// ModInfoWarnings returns findings for mods whose mod.info versionMin or
+// versionMax excludes the profile's build. Profiles only declare a major
+// version, so only the major part of each bound is compared.
+func ModInfoWarnings(b Build, infos map[string]modinfo.ModInfo) []domain.Finding {
+	if b == Unknown {
+		return nil
+	}
+	ids := make([]string, 0, len(infos))
+	for id := range infos {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var findings []domain.Finding
+	for _, id := range ids {
+		mi := infos[id]
+		var msg string
+		if min := majorOf(mi.VersionMin); min > b.Major() {
+			msg = modTitle(mi) + " needs game version " + mi.VersionMin + " or later, not " + b.Label()
+		} else if max := majorOf(mi.VersionMax); max > 0 && max < b.Major() {
+			msg = modTitle(mi) + " supports game versions up to " + mi.VersionMax + ", not " + b.Label()
+		}
+		if msg != "" {
+			findings = append(findings, domain.Finding{
+				Severity: domain.SeverityWarning,
+				Code:     domain.CodeBuildCompat,
+				Subject:  id,
+				Message:  msg,
+				Hint:     "from its " + mi.File() + "; look for a version of the mod made for " + b.Label(),
+			})
+		}
+	}
+	return findings
+}
+
+// majorOf returns the leading number of a version such as "42.0.0" (0 if
+// there is none).
+func majorOf(version string) int {
+	head, _, _ := strings.Cut(strings.TrimSpace(version), ".")
+	n, _ := strconv.Atoi(head)
+	return n
+}
+
+func modTitle(mi modinfo.ModInfo) string {
+	if mi.Name != "" && mi.Name != mi.ID {
+		return mi.Name + " (" + mi.ID + ")"
+	}
+	return mi.ID
+}
+
 func itemTitle(item steam.WorkshopItem) string {
 	if item.Title != "" {
 		return item.Title
@@ -13,6 +13,23 @@ func (d Delta) Empty() bool { return d.Added == 0 && d.Removed == 0 && !d.Reorde
 // ListDelta compares two ordered lists by set membership and order. Reordered is
 // true only when the elements common to both appear in a different relative order.
 func ListDelta(old, nw []string) Delta {
+	c := ListChanges(old, nw)
+	return Delta{Added: len(c.Added), Removed: len(c.Removed), Reordered: c.Reordered}
+}
+
+// ListChange names the entries that differ between two ordered lists, where
+// Delta only counts them.
+type ListChange struct {
+	Added     []string // in nw order
+	Removed   []string // in old order
+	Reordered bool
+}
+
+// Empty reports whether nothing changed.
+func (c ListChange) Empty() bool { return len(c.Added) == 0 && len(c.Removed) == 0 && !c.Reordered }
+
+// ListChanges is ListDelta with the added and removed entries listed.
+func ListChanges(old, nw []string) ListChange {
 	oldSet := make(map[string]struct{}, len(old))
 	for _, v := range old {
 		oldSet[v] = struct{}{}
@@ -21,15 +38,15 @@ func ListDelta(old, nw []string) Delta {
 	for _, v := range nw {
 		newSet[v] = struct{}{}
 	}
-	var d Delta
+	var d ListChange
 	for _, v := range nw {
 		if _, ok := oldSet[v]; !ok {
-			d.Added++
+			d.Added = append(d.Added, v)
 		}
 	}
 	for _, v := range old {
 		if _, ok := newSet[v]; !ok {
-			d.Removed++
+			d.Removed = append(d.Removed, v)
 		}
 	}
 	// Compare relative order of the intersection.
@@ -1,6 +1,9 @@
 package domain
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestListDelta(t *testing.T) {
 	cases := []struct {
@@ -26,3 +29,19 @@ func TestListDelta(t *testing.T) {
 		})
 	}
 }
+
+func TestListChanges(t *testing.T) {
+	got := ListChanges([]string{"a", "b", "c"}, []string{"c", "a", "d", "e"})
+	if !reflect.DeepEqual(got.Added, []string{"d", "e"}) {
+		t.Errorf("Added = %v; want [d e]", got.Added)
+	}
+	if !reflect.DeepEqual(got.Removed, []string{"b"}) {
+		t.Errorf("Removed = %v; want [b]", got.Removed)
+	}
+	if !got.Reordered {
+		t.Error("Reordered = false; want true")
+	}
+	if !ListChanges([]string{"a"}, []string{"a"}).Empty() {
+		t.Error("identical lists should be Empty")
+	}
+}
@@ -10,14 +10,17 @@ type MultiModItem struct {
 // Plan is the outcome of dependency resolution: the additions needed to satisfy
 // a set of seed items plus everything they (transitively) require.
 type Plan struct {
-	AddWorkshopItems []string          // Workshop IDs to add (excludes collections)
-	AddMods          []string          // mod IDs to add to the load order
-	AddMaps          []string          // map folders to add
-	Missing          []string          // required IDs that could not be fetched
-	MultiMod         []MultiModItem    // items declaring multiple mod IDs
-	NoModID          []string          // content items with no parseable mod ID
-	Cycles           [][]string        // dependency cycles detected during closure
-	AddModSources    map[string]string // mod ID -> Workshop ID that provides it
+	AddWorkshopItems []string            // Workshop IDs to add (excludes collections)
+	AddMods          []string            // mod IDs to add to the load order
+	AddMaps          []string            // map folders to add
+	Missing          []string            // required IDs that could not be fetched
+	Unresolved       []string            // IDs Steam failed to answer for; their dependencies are unknown
+	MultiMod         []MultiModItem      // items declaring multiple mod IDs
+	NoModID          []string            // content items with no parseable mod ID
+	Cycles           [][]string          // dependency cycles detected during closure
+	AddModSources    map[string]string   // mod ID -> Workshop ID that provides it
+	UnknownRequires  []string            // mod.info require= IDs no Workshop item was found for
+	Clashes          map[string][]string // added mod ID -> every resolved item declaring it, when several do
 }
 
 // Empty reports whether the plan adds nothing.
@@ -105,6 +105,36 @@ func (s ServerMods) RemoveMod(arg string) ServerMods {
 	return out
 }
 
+// PinMod makes workshop the provider of modID: its Mods= tokens collapse into
+// one FormatModRef(workshop, modID, explicit) at the position of the first. A
+// token already in that form is kept verbatim. No-op when modID isn't enabled.
+func (s ServerMods) PinMod(modID, workshop string, explicit bool) ServerMods {
+	out := s.Clone()
+	want := ParseModRef(FormatModRef(workshop, modID, explicit))
+	var token string
+	for _, t := range out.Mods {
+		if r := ParseModRef(t); r.ID == modID && r.Workshop == want.Workshop {
+			token = t
+			break
+		}
+	}
+	if token == "" {
+		token = FormatModRef(workshop, modID, explicit)
+	}
+	kept := out.Mods[:0:0]
+	placed := false
+	for _, t := range out.Mods {
+		if ParseModRef(t).ID != modID {
+			kept = append(kept, t)
+		} else if !placed {
+			kept = append(kept, token)
+			placed = true
+		}
+	}
+	out.Mods = kept
+	return out
+}
+
 // RemoveItem drops a Workshop ID.
 func (s ServerMods) RemoveItem(id string) ServerMods {
 	out := s.Clone()
@@ -0,0 +1,60 @@
+package domain
+
+// ProviderPlan is the outcome of choosing which of several installed Workshop
+// items provides a mod ID they all declare.
+type ProviderPlan struct {
+	ModID    string
+	Provider string   // the chosen Workshop ID
+	Drop     []string // losing items to remove from WorkshopItems
+	Kept     []string // losing items left installed: they provide other enabled mods or maps
+}
+
+// PlanProvider pins modID to provider. With drop, the other installed items
+// declaring modID are removed too, except those that also declare another
+// enabled mod or map; removing those would break something else, so they are
+// listed in Kept instead.
+func PlanProvider(modID, provider string, declarations map[string]ModDecl, current ServerMods, drop bool) ProviderPlan {
+	plan := ProviderPlan{ModID: modID, Provider: provider}
+	if !drop {
+		return plan
+	}
+	for _, id := range Dedupe(current.WorkshopItems) {
+		d, ok := declarations[id]
+		if id == provider || !ok || !contains(d.Mods, modID) {
+			continue
+		}
+		if providesOther(d, modID, current) {
+			plan.Kept = append(plan.Kept, id)
+		} else {
+			plan.Drop = append(plan.Drop, id)
+		}
+	}
+	return plan
+}
+
+// providesOther reports whether d declares an enabled mod other than modID, or
+// an enabled map.
+func providesOther(d ModDecl, modID string, current ServerMods) bool {
+	for _, m := range d.Mods {
+		if m != modID && current.HasMod(m) {
+			return true
+		}
+	}
+	for _, mp := range d.Maps {
+		if current.HasMap(mp) {
+			return true
+		}
+	}
+	return false
+}
+
+// Apply returns current with the mod pinned to the provider and the losing
+// items dropped. explicit is as for FormatModRef: on Build 41 the Mods= token
+// stays a plain ID, so only dropping the other items settles the clash.
+func (p ProviderPlan) Apply(current ServerMods, explicit bool) ServerMods {
+	out := current.PinMod(p.ModID, p.Provider, explicit)
+	for _, id := range p.Drop {
+		out = out.RemoveItem(id)
+	}
+	return out
+}
@@ -0,0 +1,50 @@
+package domain
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestPlanProvider(t *testing.T) {
+	current := ServerMods{
+		WorkshopItems: []string{"1", "2", "3", "4"},
+		Mods:          []string{"Cars", `2\Dupe`, "Other", `3\Dupe`},
+		Maps:          []string{"Town"},
+	}
+	decl := map[string]ModDecl{
+		"1": {Mods: []string{"Cars"}},
+		"2": {Mods: []string{"Dupe"}},
+		"3": {Mods: []string{"Dupe", "Other"}},                // also provides an enabled mod
+		"4": {Mods: []string{"Dupe"}, Maps: []string{"Town"}}, // and an enabled map
+	}
+
+	got := PlanProvider("Dupe", "3", decl, current, false)
+	if len(got.Drop) != 0 || len(got.Kept) != 0 {
+		t.Fatalf("without drop nothing should be removed: %+v", got)
+	}
+	out := got.Apply(current, true)
+	if want := []string{"Cars", `3\Dupe`, "Other"}; !reflect.DeepEqual(out.Mods, want) {
+		t.Errorf("Mods = %v; want %v (pinned at the first position, raw token kept)", out.Mods, want)
+	}
+	if !reflect.DeepEqual(out.WorkshopItems, current.WorkshopItems) {
+		t.Errorf("WorkshopItems = %v; want unchanged", out.WorkshopItems)
+	}
+
+	got = PlanProvider("Dupe", "1", map[string]ModDecl{"1": {Mods: []string{"Dupe"}}, "2": decl["2"], "3": decl["3"], "4": decl["4"]}, current, true)
+	if !reflect.DeepEqual(got.Drop, []string{"2"}) || !reflect.DeepEqual(got.Kept, []string{"3", "4"}) {
+		t.Fatalf("Drop = %v, Kept = %v; want [2] and [3 4]", got.Drop, got.Kept)
+	}
+	out = got.Apply(current, true)
+	if want := []string{"Cars", `1\Dupe`, "Other"}; !reflect.DeepEqual(out.Mods, want) {
+		t.Errorf("Mods = %v; want %v", out.Mods, want)
+	}
+	if want := []string{"1", "3", "4"}; !reflect.DeepEqual(out.WorkshopItems, want) {
+		t.Errorf("WorkshopItems = %v; want %v", out.WorkshopItems, want)
+	}
+
+	// Build 41 has no pinned form: the token stays a plain ID.
+	out = PlanProvider("Dupe", "2", decl, current, false).Apply(current, false)
+	if want := []string{"Cars", "Dupe", "Other"}; !reflect.DeepEqual(out.Mods, want) {
+		t.Errorf("B41 Mods = %v; want %v", out.Mods, want)
+	}
+}
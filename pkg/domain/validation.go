@@ -29,15 +29,24 @@ func (s Severity) String() string {
 // Finding codes.
 const (
 	CodeMissingDependency = "missing-dependency"
-	CodeDelisted          = "delisted"
+	CodeMissingRequire    = "missing-require"     // a mod.info require= that nothing enabled satisfies
+	CodeUnneededDep       = "unneeded-dependency" // a Workshop required item providing nothing mod.info requires
+	CodeDelisted          = "delisted"            // unavailable for an unreported reason
+	CodeNotFound          = "not-found"
+	CodePrivate           = "private"
+	CodeFriendsOnly       = "friends-only"
 	CodeBanned            = "banned"
 	CodeUnknownModID      = "unknown-mod-id"
 	CodeUnusedModID       = "unused-mod-id"
 	CodeUnusedMap         = "unused-map"
+	CodeUnknownMap        = "unknown-map" // enabled, but no installed mod ships it
 	CodeNoModID           = "no-mod-id"
 	CodeLoadOrder         = "load-order"
 	CodeBuildCompat       = "build-compat"
 	CodeModIDClash        = "mod-id-clash"
+	CodeIncompatible      = "incompatible"    // mod.info declares an enabled mod incompatible
+	CodeUnchecked         = "unchecked"       // Steam failed to answer for the item
+	CodeNotInSnapshot     = "not-in-snapshot" // the --workshop-snapshot predates the item
 )
 
 // Finding is one validation result.
@@ -46,13 +55,21 @@ type Finding struct {
 	Code     string
 	Message  string
 	Subject  string // the mod ID or workshop ID the finding concerns
+	Hint     string // how to fix it, when that isn't obvious from Message
 }
 
 // Report is an ordered set of findings.
 type Report struct {
 	Findings []Finding
+
+	// Unchecked lists Workshop IDs Steam failed to return (errors, rate
+	// limiting, maintenance). The report covers everything else.
+	Unchecked []string
 }
 
+// Incomplete reports whether some items could not be checked.
+func (r Report) Incomplete() bool { return len(r.Unchecked) > 0 }
+
 // Add appends a finding.
 func (r *Report) Add(f Finding) { r.Findings = append(r.Findings, f) }
 
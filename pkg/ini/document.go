@@ -37,6 +37,14 @@ func Parse(data []byte) *Document {
 	return d
 }
 
+// Reset replaces the document's content with data but keeps the saved
+// baseline, so HasUnsavedChanges still compares against the last save. Used to
+// roll in-memory edits back (or forward) byte-for-byte.
+func (d *Document) Reset(data []byte) {
+	fresh := Parse(data)
+	d.lines, d.index, d.eol = fresh.lines, fresh.index, fresh.eol
+}
+
 // New returns an empty document using the OS-default line ending.
 func New() *Document {
 	return &Document{index: make(map[string]int), eol: OSDefault(), original: []byte{}}
@@ -48,6 +56,17 @@ func (d *Document) EOL() LineEnding { return d.eol }
 // Lines returns the document's lines (read-only use).
 func (d *Document) Lines() []Line { return d.lines }
 
+// Keys returns the entry keys in first-seen order.
+func (d *Document) Keys() []string {
+	keys := make([]string, 0, len(d.index))
+	for i := range d.lines {
+		if k := d.lines[i].key; d.lines[i].Kind == KindEntry && d.index[k] == i {
+			keys = append(keys, k)
+		}
+	}
+	return keys
+}
+
 // Has reports whether key is present.
 func (d *Document) Has(key string) bool {
 	_, ok := d.index[key]
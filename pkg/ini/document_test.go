@@ -149,6 +149,23 @@ func TestHasUnsavedChangesAndMarkSaved(t *testing.T) {
 	}
 }
 
+func TestResetKeepsBaseline(t *testing.T) {
+	d := Parse([]byte("A=1 # one\n"))
+	before := d.Bytes()
+	d.Set("A", "2")
+	d.Reset(before)
+	if d.HasUnsavedChanges() {
+		t.Errorf("resetting to the saved bytes should be clean, got %q", d.String())
+	}
+	d.Reset([]byte("A=3\n"))
+	if !d.HasUnsavedChanges() {
+		t.Error("resetting to other bytes should be dirty")
+	}
+	if v, _ := d.Get("A"); v != "3" {
+		t.Errorf("A = %q after Reset; want 3", v)
+	}
+}
+
 func FuzzRoundTrip(f *testing.F) {
 	seeds := []string{
 		"", "Key=Value", "A=1\nB=2\n", "\r\n\r\n", "# c\nk=v\r\n",
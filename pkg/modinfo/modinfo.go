@@ -8,14 +8,53 @@ import (
 	"bufio"
 	"os"
 	"path/filepath"
+	"strconv"
 	"strings"
 )
 
-// ModInfo is the subset of a mod.info file relevant to load order.
+// ModInfo is a parsed mod.info file.
 type ModInfo struct {
 	ID      string   // the "id=" / "name=" field used in the Mods= list
 	Name    string   // human-friendly name
 	Require []string // mod IDs this mod requires (must load first)
+
+	Poster      []string // poster image files, relative to the mod folder
+	Description string
+	URL         string
+	ModVersion  string // the author's own version string
+	VersionMin  string // oldest game version supported, e.g. "42.0.0" ("" if unbounded)
+	VersionMax  string // newest game version supported ("" if unbounded)
+	Pack        []string
+	TileDef     []string
+	Category    string
+
+	// Build 42 ordering and conflict keys, as mod IDs.
+	LoadAfter    []string // loadModAfter: load after these when they're enabled
+	LoadBefore   []string // loadModBefore: load before these when they're enabled
+	Incompatible []string // mods that must not be enabled alongside this one
+
+	Path       string   // the mod.info file read
+	VersionDir string   // the Build 42 folder it came from ("42", "42.3", "common"), "" for the mod root
+	WorkshopID string   // the item folder under the content root it was found in ("" for a local mod)
+	Maps       []string // map folders the mod ships under media/maps
+}
+
+// File is the mod.info read, relative to the mod folder: "42.3/mod.info" for a
+// Build 42 version folder, "mod.info" for the mod root.
+func (mi ModInfo) File() string {
+	if mi.VersionDir == "" {
+		return "mod.info"
+	}
+	return mi.VersionDir + "/mod.info"
+}
+
+// Origin names the folder the mod.info was read from, for display: "42.3/"
+// for a Build 42 version folder, "the mod root" otherwise.
+func (mi ModInfo) Origin() string {
+	if mi.VersionDir == "" {
+		return "the mod root"
+	}
+	return mi.VersionDir + "/"
 }
 
 // Provider resolves mod.info data for installed mods.
@@ -26,18 +65,72 @@ type Provider interface {
 }
 
 // DiskProvider reads mod.info files under a Workshop content root of the form
-// <root>/<workshopID>/mods/<modName>/mod.info.
+// <root>/<workshopID>/mods/<modName>/mod.info. Build 42 mods keep theirs in
+// versioned subfolders instead (<modName>/42/mod.info, <modName>/42.3/, and
+// <modName>/common/), and the folder read is chosen by GameVersion the way
+// the game chooses it.
 type DiskProvider struct {
 	Root string
+
+	// GameVersion is the game version to select folders for, e.g. "41" or
+	// "42.3". Empty means unknown: the mod root is preferred, then the newest
+	// versioned folder.
+	GameVersion string
+
+	// Local means Root is a local mods directory (Zomboid/mods), holding mod
+	// folders directly rather than Workshop item folders.
+	Local bool
+}
+
+// Option configures a DiskProvider.
+type Option func(*DiskProvider)
+
+// WithGameVersion selects Build 42 version folders for the given game
+// version ("42", "42.3"); see DiskProvider.GameVersion.
+func WithGameVersion(v string) Option {
+	return func(p *DiskProvider) { p.GameVersion = strings.TrimSpace(v) }
 }
 
 // NewProvider returns a Provider for root. An empty root yields a no-op
 // provider so callers need not branch.
-func NewProvider(root string) Provider {
+func NewProvider(root string, opts ...Option) Provider {
 	if strings.TrimSpace(root) == "" {
 		return nopProvider{}
 	}
-	return &DiskProvider{Root: root}
+	p := &DiskProvider{Root: root}
+	for _, o := range opts {
+		o(p)
+	}
+	return p
+}
+
+// NewLocalProvider returns a Provider for a local mods directory
+// (Zomboid/mods/<modName>/mod.info), for mods installed without the Workshop.
+// Like NewProvider, an empty root yields a no-op provider.
+func NewLocalProvider(root string, opts ...Option) Provider {
+	p := NewProvider(root, opts...)
+	if dp, ok := p.(*DiskProvider); ok {
+		dp.Local = true
+	}
+	return p
+}
+
+// Chain returns a Provider that asks each provider in turn; for a mod found by
+// several, the first one's mod.info wins.
+func Chain(providers ...Provider) Provider { return chain(providers) }
+
+type chain []Provider
+
+func (c chain) Lookup(modIDs []string) map[string]ModInfo {
+	out := map[string]ModInfo{}
+	for _, p := range c {
+		for id, mi := range p.Lookup(modIDs) {
+			if _, ok := out[id]; !ok {
+				out[id] = mi
+			}
+		}
+	}
+	return out
 }
 
 type nopProvider struct{}
@@ -65,25 +158,184 @@ func (p *DiskProvider) Lookup(modIDs []string) map[string]ModInfo {
 	return out
 }
 
-// scan walks <root>/*/mods/*/mod.info.
+// scan walks the mod folders under <root>/*/mods/ (or <root>/ when Local)
+// and reads one mod.info from each.
 func (p *DiskProvider) scan() ([]ModInfo, error) {
-	matches, err := filepath.Glob(filepath.Join(p.Root, "*", "mods", "*", "mod.info"))
-	if err != nil {
-		return nil, err
+	var dirs []string
+	if p.Local {
+		entries, err := os.ReadDir(p.Root)
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				dirs = append(dirs, filepath.Join(p.Root, e.Name()))
+			}
+		}
+	} else {
+		var err error
+		dirs, err = filepath.Glob(filepath.Join(p.Root, "*", "mods", "*"))
+		if err != nil {
+			return nil, err
+		}
+		// Some installs nest under "Contents/mods"; include that layout too.
+		more, _ := filepath.Glob(filepath.Join(p.Root, "*", "*", "mods", "*"))
+		dirs = append(dirs, more...)
 	}
-	// Some installs nest under "Contents/mods"; include that layout too.
-	more, _ := filepath.Glob(filepath.Join(p.Root, "*", "*", "mods", "*", "mod.info"))
-	matches = append(matches, more...)
 
 	var out []ModInfo
-	for _, path := range matches {
-		if mi, ok := parseFile(path); ok {
+	for _, dir := range dirs {
+		versionDir, ok := p.pick(dir)
+		if !ok {
+			continue
+		}
+		if mi, ok := parseFile(filepath.Join(dir, versionDir, "mod.info")); ok {
+			mi.VersionDir = versionDir
+			if rel, err := filepath.Rel(p.Root, dir); err == nil && !p.Local {
+				mi.WorkshopID, _, _ = strings.Cut(filepath.ToSlash(rel), "/")
+			}
+			mi.Maps = mapFolders(dir, versionDir)
 			out = append(out, mi)
 		}
 	}
 	return out, nil
 }
 
+// Mods returns the mod.info of every mod under the root.
+func (p *DiskProvider) Mods() ([]ModInfo, error) { return p.scan() }
+
+// Items returns the mod.info of every mod under the content root, grouped by
+// Workshop item. Every item folder is present, with no mods if none of its
+// mod folders has a usable mod.info.
+func (p *DiskProvider) Items() (map[string][]ModInfo, error) {
+	entries, err := os.ReadDir(p.Root)
+	if err != nil {
+		return nil, err
+	}
+	items := map[string][]ModInfo{}
+	for _, e := range entries {
+		if e.IsDir() && !strings.HasPrefix(e.Name(), ".") {
+			items[e.Name()] = nil
+		}
+	}
+	infos, err := p.scan()
+	if err != nil {
+		return nil, err
+	}
+	for _, mi := range infos {
+		items[mi.WorkshopID] = append(items[mi.WorkshopID], mi)
+	}
+	return items, nil
+}
+
+// mapFolders lists the map folders under media/maps in the chosen folder of
+// a mod, and in common/ beside a Build 42 version folder.
+func mapFolders(modDir, versionDir string) []string {
+	dirs := []string{versionDir}
+	if versionDir != "" && versionDir != "common" {
+		dirs = append(dirs, "common")
+	}
+	var maps []string
+	seen := map[string]bool{}
+	for _, d := range dirs {
+		entries, _ := os.ReadDir(filepath.Join(modDir, d, "media", "maps"))
+		for _, e := range entries {
+			if e.IsDir() && !seen[e.Name()] {
+				seen[e.Name()] = true
+				maps = append(maps, e.Name())
+			}
+		}
+	}
+	return maps
+}
+
+// pick chooses the folder of modDir whose mod.info applies to p.GameVersion:
+// the newest version folder not newer than the game, then common/, then the
+// mod root (a Build 41 mod, still worth reading for its version bounds).
+// Build 41 only reads the mod root. With no GameVersion, the mod root wins,
+// then the newest version folder, then common/.
+func (p *DiskProvider) pick(modDir string) (string, bool) {
+	has := func(sub string) bool {
+		info, err := os.Stat(filepath.Join(modDir, sub, "mod.info"))
+		return err == nil && info.Mode().IsRegular()
+	}
+	game, known := parseVersion(p.GameVersion)
+	if known && game[0] < 42 {
+		return "", has("")
+	}
+	if !known && has("") {
+		return "", true
+	}
+
+	entries, err := os.ReadDir(modDir)
+	if err != nil {
+		return "", false
+	}
+	best, bestV := "", []int(nil)
+	for _, e := range entries {
+		v, ok := parseVersion(e.Name())
+		if !ok || !e.IsDir() || !has(e.Name()) {
+			continue
+		}
+		if known && !fits(v, game) {
+			continue
+		}
+		if bestV == nil || compareVersions(v, bestV) > 0 {
+			best, bestV = e.Name(), v
+		}
+	}
+	switch {
+	case best != "":
+		return best, true
+	case has("common"):
+		return "common", true
+	}
+	return "", known && has("")
+}
+
+// parseVersion parses a version folder name such as "42", "42.3" or "42.x";
+// an "x" part matches any number and sorts as 0.
+func parseVersion(s string) ([]int, bool) {
+	if s == "" {
+		return nil, false
+	}
+	parts := strings.Split(s, ".")
+	v := make([]int, len(parts))
+	for i, part := range parts {
+		if part == "x" || part == "X" {
+			continue
+		}
+		n, err := strconv.Atoi(part)
+		if err != nil || n < 0 {
+			return nil, false
+		}
+		v[i] = n
+	}
+	return v, true
+}
+
+// fits reports whether a version folder applies to the game version: it is
+// not newer, comparing only as many parts as the game version has, so a
+// "42" game takes any 42.x folder.
+func fits(folder, game []int) bool {
+	if len(folder) > len(game) {
+		folder = folder[:len(game)]
+	}
+	return compareVersions(folder, game) <= 0
+}
+
+func compareVersions(a, b []int) int {
+	for i := 0; i < len(a) && i < len(b); i++ {
+		if a[i] != b[i] {
+			if a[i] < b[i] {
+				return -1
+			}
+			return 1
+		}
+	}
+	return len(a) - len(b)
+}
+
 func parseFile(path string) (ModInfo, bool) {
 	f, err := os.Open(path)
 	if err != nil {
@@ -91,7 +343,7 @@ func parseFile(path string) (ModInfo, bool) {
 	}
 	defer f.Close()
 
-	var mi ModInfo
+	mi := ModInfo{Path: path}
 	scanner := bufio.NewScanner(f)
 	for scanner.Scan() {
 		key, val, ok := splitKV(scanner.Text())
@@ -107,11 +359,34 @@ func parseFile(path string) (ModInfo, bool) {
 				mi.ID = val // PZ uses the name field as the load ID when id is absent
 			}
 		case "require":
-			for _, r := range strings.Split(val, ",") {
-				if r = strings.TrimSpace(r); r != "" {
-					mi.Require = append(mi.Require, r)
-				}
+			mi.Require = append(mi.Require, splitIDs(val)...)
+		case "loadmodafter":
+			mi.LoadAfter = append(mi.LoadAfter, splitIDs(val)...)
+		case "loadmodbefore":
+			mi.LoadBefore = append(mi.LoadBefore, splitIDs(val)...)
+		case "incompatible":
+			mi.Incompatible = append(mi.Incompatible, splitIDs(val)...)
+		case "poster":
+			mi.Poster = append(mi.Poster, val)
+		case "description":
+			if mi.Description != "" {
+				mi.Description += "\n"
 			}
+			mi.Description += val
+		case "url":
+			mi.URL = val
+		case "modversion":
+			mi.ModVersion = val
+		case "versionmin":
+			mi.VersionMin = val
+		case "versionmax":
+			mi.VersionMax = val
+		case "pack":
+			mi.Pack = append(mi.Pack, val)
+		case "tiledef":
+			mi.TileDef = append(mi.TileDef, val)
+		case "category":
+			mi.Category = val
 		}
 	}
 	if mi.ID == "" {
@@ -120,6 +395,18 @@ func parseFile(path string) (ModInfo, bool) {
 	return mi, true
 }
 
+// splitIDs splits a mod ID list. Build 42 writes each ID with a leading
+// backslash ("\ModA,\ModB"), which is dropped; ';' separators are accepted too.
+func splitIDs(val string) []string {
+	var out []string
+	for _, r := range strings.FieldsFunc(val, func(c rune) bool { return c == ',' || c == ';' }) {
+		if r = strings.TrimLeft(strings.TrimSpace(r), "\\"); r != "" {
+			out = append(out, r)
+		}
+	}
+	return out
+}
+
 func splitKV(line string) (key, val string, ok bool) {
 	line = strings.TrimSpace(line)
 	if line == "" || strings.HasPrefix(line, "#") {
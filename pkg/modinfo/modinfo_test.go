@@ -50,3 +50,112 @@ func TestMissingRootIsSafe(t *testing.T) {
 		t.Errorf("missing root should be safe, got %v", got)
 	}
 }
+
+func TestParseFullSchema(t *testing.T) {
+	root := t.TempDir()
+	writeModInfo(t, root, "444", "Cars", `name=Better Cars
+id=BetterCars
+poster=poster.png
+poster=poster2.png
+description=Faster cars.
+description=Louder horns.
+url=https://example.com/cars
+modversion=1.4
+versionMin=42.0.0
+versionMax=42.9
+pack=cars
+tiledef=cars 8100
+category=vehicle
+require=\CoreLib,\VehicleAPI
+loadModAfter=\VehicleAPI
+loadModBefore=\Skins; \Decals
+incompatible=\OldCars
+`)
+	mi := NewProvider(root).Lookup([]string{"BetterCars"})["BetterCars"]
+	want := ModInfo{
+		ID: "BetterCars", Name: "Better Cars",
+		Require:     []string{"CoreLib", "VehicleAPI"},
+		Poster:      []string{"poster.png", "poster2.png"},
+		Description: "Faster cars.\nLouder horns.",
+		URL:         "https://example.com/cars",
+		ModVersion:  "1.4", VersionMin: "42.0.0", VersionMax: "42.9",
+		Pack: []string{"cars"}, TileDef: []string{"cars 8100"}, Category: "vehicle",
+		LoadAfter:    []string{"VehicleAPI"},
+		LoadBefore:   []string{"Skins", "Decals"},
+		Incompatible: []string{"OldCars"},
+		Path:         filepath.Join(root, "444", "mods", "Cars", "mod.info"),
+		WorkshopID:   "444",
+	}
+	if !reflect.DeepEqual(mi, want) {
+		t.Errorf("got  %+v\nwant %+v", mi, want)
+	}
+}
+
+func TestDiskProviderVersionFolders(t *testing.T) {
+	root := t.TempDir()
+	write := func(rel, id string) {
+		t.Helper()
+		dir := filepath.Join(root, "555", "mods", "Cars", filepath.FromSlash(rel))
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id="+id+"\n"), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("", "Cars41")
+	write("42", "Cars42")
+	write("42.3", "Cars423")
+	write("43", "Cars43")
+	os.MkdirAll(filepath.Join(root, "555", "mods", "Cars", "common", "media"), 0755)
+
+	all := []string{"Cars41", "Cars42", "Cars423", "Cars43"}
+	for _, tc := range []struct{ game, id, dir string }{
+		{"41", "Cars41", ""},
+		{"42", "Cars423", "42.3"},
+		{"42.1", "Cars42", "42"},
+		{"43.5", "Cars43", "43"},
+		{"", "Cars41", ""},
+	} {
+		got := NewProvider(root, WithGameVersion(tc.game)).Lookup(all)
+		if len(got) != 1 || got[tc.id].VersionDir != tc.dir {
+			t.Errorf("game %q: got %v; want %s from %q", tc.game, got, tc.id, tc.dir)
+		}
+	}
+
+	// A mod with only a common/ mod.info still resolves on Build 42.
+	dir := filepath.Join(root, "666", "mods", "Shared", "common")
+	os.MkdirAll(dir, 0755)
+	os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id=Shared\n"), 0644)
+	if got := NewProvider(root, WithGameVersion("42")).Lookup([]string{"Shared"}); got["Shared"].VersionDir != "common" {
+		t.Errorf("Shared = %+v; want it read from common/", got["Shared"])
+	}
+	// An unknown game version falls back to the newest folder without a root mod.info.
+	os.Remove(filepath.Join(root, "555", "mods", "Cars", "mod.info"))
+	if got := NewProvider(root).Lookup(all); got["Cars43"].VersionDir != "43" {
+		t.Errorf("unknown version: got %v; want Cars43 from 43", got)
+	}
+}
+
+func TestLocalProviderAndChain(t *testing.T) {
+	local := t.TempDir()
+	dir := filepath.Join(local, "ServerTweaks")
+	if err := os.MkdirAll(filepath.Join(dir, "media", "maps", "TweakMap"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "mod.info"), []byte("name=Server Tweaks\nid=ServerTweaks\nrequire=CoreLib\n"), 0644)
+
+	got := NewLocalProvider(local).Lookup([]string{"ServerTweaks"})["ServerTweaks"]
+	if got.WorkshopID != "" || !reflect.DeepEqual(got.Maps, []string{"TweakMap"}) || !reflect.DeepEqual(got.Require, []string{"CoreLib"}) {
+		t.Errorf("local mod = %+v", got)
+	}
+
+	workshop := t.TempDir()
+	writeModInfo(t, workshop, "111", "CoreLib", "id=CoreLib\n")
+	writeModInfo(t, workshop, "222", "ServerTweaks", "id=ServerTweaks\nname=Workshop copy\n")
+	all := Chain(NewProvider(workshop), NewLocalProvider(local), NewLocalProvider("")).
+		Lookup([]string{"CoreLib", "ServerTweaks"})
+	if len(all) != 2 || all["ServerTweaks"].Name != "Workshop copy" {
+		t.Errorf("chain = %v; want both mods, the first provider winning", all)
+	}
+}
@@ -0,0 +1,108 @@
+package serverconfig
+
+import (
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+)
+
+// KeyChange is one non-list key whose value differs between two configs.
+type KeyChange struct {
+	Key     string
+	Old     string
+	New     string
+	Added   bool // absent from the old config
+	Removed bool // absent from the new config
+}
+
+// Diff is a semantic comparison of two configs: which mods, items, and maps
+// were added, removed, or reordered, and which other keys changed value.
+type Diff struct {
+	Mods          domain.ListChange
+	WorkshopItems domain.ListChange
+	Maps          domain.ListChange
+	Keys          []KeyChange // new's key order, then keys only old has
+}
+
+// Empty reports whether the configs are semantically identical.
+func (d Diff) Empty() bool {
+	return d.Mods.Empty() && d.WorkshopItems.Empty() && d.Maps.Empty() && len(d.Keys) == 0
+}
+
+// listKeys are compared as lists by Compare rather than as raw values.
+var listKeys = map[string]bool{KeyMods: true, KeyWorkshop: true, KeyMap: true}
+
+// Compare diffs old against new. Mods= tokens are compared verbatim, so
+// re-pinning a mod to another workshop item shows as a removal plus an addition.
+func Compare(old, new *Config) Diff {
+	d := Diff{
+		Mods:          domain.ListChanges(old.Mods(), new.Mods()),
+		WorkshopItems: domain.ListChanges(old.WorkshopItems(), new.WorkshopItems()),
+		Maps:          domain.ListChanges(old.Maps(), new.Maps()),
+	}
+	for _, k := range new.doc.Keys() {
+		if listKeys[k] {
+			continue
+		}
+		nv, _ := new.Get(k)
+		ov, ok := old.Get(k)
+		if !ok {
+			d.Keys = append(d.Keys, KeyChange{Key: k, New: nv, Added: true})
+		} else if ov != nv {
+			d.Keys = append(d.Keys, KeyChange{Key: k, Old: ov, New: nv})
+		}
+	}
+	for _, k := range old.doc.Keys() {
+		if listKeys[k] || new.doc.Has(k) {
+			continue
+		}
+		ov, _ := old.Get(k)
+		d.Keys = append(d.Keys, KeyChange{Key: k, Old: ov, Removed: true})
+	}
+	return d
+}
+
+// IsSecretKey reports whether a key holds a credential whose value should not
+// be echoed in diffs (Password, RCONPassword, ...).
+func IsSecretKey(key string) bool {
+	return strings.Contains(strings.ToLower(key), "password")
+}
+
+// Selection picks the parts of a config that Splice copies.
+type Selection struct {
+	Mods          bool
+	WorkshopItems bool
+	Maps          bool
+	Keys          []string // other keys, copied value by value
+}
+
+// Empty reports whether nothing is selected.
+func (s Selection) Empty() bool {
+	return !s.Mods && !s.WorkshopItems && !s.Maps && len(s.Keys) == 0
+}
+
+// Splice copies the selected parts of src into c: the lists through their
+// setters, the other keys verbatim. Keys src does not have are left alone
+// and returned, so the caller can report them.
+func (c *Config) Splice(src *Config, sel Selection) (missing []string) {
+	if sel.Mods {
+		c.SetMods(src.Mods())
+	}
+	if sel.WorkshopItems {
+		c.SetWorkshopItems(src.WorkshopItems())
+	}
+	if sel.Maps {
+		c.SetMaps(src.Maps())
+	}
+	for _, k := range sel.Keys {
+		v, ok := src.Get(k)
+		if !ok {
+			missing = append(missing, k)
+			continue
+		}
+		if cur, had := c.Get(k); !had || cur != v {
+			c.Set(k, v)
+		}
+	}
+	return missing
+}
@@ -0,0 +1,63 @@
+package serverconfig
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestCompare(t *testing.T) {
+	old := FromBytes("x.ini", []byte("PublicName=A\nPassword=hunter2\nMods=a;b\nWorkshopItems=1;2\nMap=Muldraugh, KY\nPvP=true\n"))
+	neu := FromBytes("x.ini", []byte("PublicName=B\nPassword=hunter2\nMods=b;a;c\nWorkshopItems=1\nMap=Muldraugh, KY\nMaxPlayers=16\n"))
+
+	d := Compare(old, neu)
+	if !reflect.DeepEqual(d.Mods.Added, []string{"c"}) || !d.Mods.Reordered {
+		t.Errorf("Mods = %+v; want c added, reordered", d.Mods)
+	}
+	if !reflect.DeepEqual(d.WorkshopItems.Removed, []string{"2"}) {
+		t.Errorf("WorkshopItems.Removed = %v; want [2]", d.WorkshopItems.Removed)
+	}
+	if !d.Maps.Empty() {
+		t.Errorf("Maps = %+v; want unchanged", d.Maps)
+	}
+	want := []KeyChange{
+		{Key: "PublicName", Old: "A", New: "B"},
+		{Key: "MaxPlayers", New: "16", Added: true},
+		{Key: "PvP", Old: "true", Removed: true},
+	}
+	if !reflect.DeepEqual(d.Keys, want) {
+		t.Errorf("Keys = %+v\nwant %+v", d.Keys, want)
+	}
+	if !Compare(old, old).Empty() {
+		t.Error("a config compared with itself should be Empty")
+	}
+}
+
+func TestSplice(t *testing.T) {
+	src := FromBytes("b.ini", []byte("PublicName=Old\nMods=a;b\nWorkshopItems=1;2\nMap=Muldraugh, KY\n"))
+	cur := "PublicName=New\nMods=c\nWorkshopItems=3\nMap=Riverside, KY\nMaxPlayers=8\n"
+	c := FromBytes("c.ini", []byte(cur))
+
+	missing := c.Splice(src, Selection{Mods: true, Keys: []string{"PublicName", "MaxPlayers"}})
+	if !reflect.DeepEqual(missing, []string{"MaxPlayers"}) {
+		t.Errorf("missing = %v; want [MaxPlayers]", missing)
+	}
+	if got := c.Mods(); !reflect.DeepEqual(got, []string{"a", "b"}) {
+		t.Errorf("Mods = %v; want [a b]", got)
+	}
+	if got := c.WorkshopItems(); !reflect.DeepEqual(got, []string{"3"}) {
+		t.Errorf("WorkshopItems = %v; want untouched [3]", got)
+	}
+	if got := c.Name(); got != "Old" {
+		t.Errorf("Name = %q; want Old", got)
+	}
+	if got := c.MaxPlayers(); got != "8" {
+		t.Errorf("MaxPlayers = %q; want untouched 8", got)
+	}
+
+	// An empty selection leaves the document byte-identical.
+	c = FromBytes("c.ini", []byte(cur))
+	c.Splice(src, Selection{})
+	if string(c.Bytes()) != cur {
+		t.Errorf("empty splice changed the document:\n%s", c.Bytes())
+	}
+}
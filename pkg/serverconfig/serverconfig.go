@@ -52,6 +52,10 @@ func (c *Config) Bytes() []byte { return c.doc.Bytes() }
 // String renders the config to a string.
 func (c *Config) String() string { return c.doc.String() }
 
+// Reset replaces the in-memory content with data, keeping the saved baseline
+// (see ini.Document.Reset).
+func (c *Config) Reset(data []byte) { c.doc.Reset(data) }
+
 // HasUnsavedChanges reports in-memory edits not yet persisted.
 func (c *Config) HasUnsavedChanges() bool { return c.doc.HasUnsavedChanges() }
 
@@ -0,0 +1,86 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+// maxAuthorItems caps how many of an author's items AuthorItems collects.
+const maxAuthorItems = 500
+
+// steamID64Base is the lowest individual-account SteamID64. Workshop item IDs
+// are far smaller, which is how AuthorItems tells the two apart.
+const steamID64Base = 76561197960265728
+
+// Author is a Workshop creator and the items they published.
+type Author struct {
+	SteamID string
+	Name    string // persona name, when resolved
+	Items   []steam.WorkshopItem
+	Total   int // as reported by Steam; may exceed len(Items)
+}
+
+// IsSteamID64 reports whether ref looks like an individual SteamID64 rather
+// than a Workshop item ID.
+func IsSteamID64(ref string) bool {
+	n, err := strconv.ParseUint(ref, 10, 64)
+	return err == nil && n >= steamID64Base
+}
+
+// AuthorItems lists the Project Zomboid items published by a creator. ref is
+// either the creator's SteamID64 or the ID of one of their Workshop items.
+func (s *Services) AuthorItems(ctx context.Context, ref string) (Author, error) {
+	creator := ref
+	if !IsSteamID64(ref) {
+		items, _, err := s.Steam.GetDetails(ctx, []string{ref})
+		if err != nil {
+			return Author{}, err
+		}
+		if len(items) == 0 {
+			return Author{}, fmt.Errorf("item %s not found", ref)
+		}
+		if creator = items[0].Creator; creator == "" {
+			return Author{}, fmt.Errorf("no creator reported for item %s", ref)
+		}
+	}
+
+	a := Author{SteamID: creator}
+	for page := 1; len(a.Items) < maxAuthorItems; page++ {
+		p, err := s.Steam.UserFiles(ctx, creator, page, 100)
+		if err != nil {
+			return Author{}, err
+		}
+		a.Total = p.Total
+		a.Items = append(a.Items, p.Items...)
+		if len(p.Items) == 0 || len(a.Items) >= p.Total {
+			break
+		}
+	}
+	if len(a.Items) > maxAuthorItems {
+		a.Items = a.Items[:maxAuthorItems]
+	}
+	s.NameCreators(ctx, a.Items)
+	if names, _ := s.Steam.PlayerNames(ctx, []string{creator}); names[creator] != "" {
+		a.Name = names[creator]
+	}
+	return a, nil
+}
+
+// NameCreators fills in CreatorName on items whose creator's persona name can
+// be resolved. Names are cosmetic, so failures are ignored.
+func (s *Services) NameCreators(ctx context.Context, items []steam.WorkshopItem) {
+	ids := make([]string, 0, len(items))
+	for _, it := range items {
+		ids = append(ids, it.Creator)
+	}
+	names, _ := s.Steam.PlayerNames(ctx, domain.Dedupe(ids))
+	for i := range items {
+		if name, ok := names[items[i].Creator]; ok {
+			items[i].CreatorName = name
+		}
+	}
+}
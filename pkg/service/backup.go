@@ -0,0 +1,140 @@
+package service
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// LoadBackup parses a stored snapshot as a config. The returned config has no
+// path; it is only meant to be compared or spliced from.
+func (s *Services) LoadBackup(profileID, backupID string) (*serverconfig.Config, error) {
+	data, err := s.Store.ReadBackup(profileID, backupID)
+	if err != nil {
+		return nil, err
+	}
+	return serverconfig.FromBytes("", data), nil
+}
+
+// PartialRestore is the outcome of RestorePartial.
+type PartialRestore struct {
+	Summary serverconfig.Summary // what changed in the live config
+	Missing []string             // selected keys the backup does not have
+	Journal store.JournalEntry   // the journaled write, with its pre-restore snapshot
+}
+
+// RestorePartial copies only the selected parts of a backup into the profile's
+// config. Everything else in the live file is left byte-for-byte alone. Like a
+// full restore, it takes a "pre-restore" snapshot first.
+func (s *Services) RestorePartial(p store.Profile, backupID string, sel serverconfig.Selection) (PartialRestore, error) {
+	var res PartialRestore
+	if sel.Empty() {
+		return res, errors.New("nothing selected to restore")
+	}
+	src, err := s.LoadBackup(p.ID, backupID)
+	if err != nil {
+		return res, err
+	}
+	cfg, err := serverconfig.Load(p.IniPath)
+	if err != nil {
+		return res, err
+	}
+	before := serverconfig.FromBytes(p.IniPath, cfg.Bytes())
+	res.Missing = cfg.Splice(src, sel)
+	res.Summary = serverconfig.Summarize(before, cfg)
+	if !cfg.HasUnsavedChanges() {
+		return res, nil
+	}
+	res.Journal, err = s.ApplyChange(p, cfg, Op{
+		Command: "backup restore",
+		Args:    append([]string{backupID, "--only"}, selectionArgs(sel)...),
+		Note:    "before partial restore of " + backupID,
+		Kind:    "pre-restore",
+	})
+	return res, err
+}
+
+// RestoreBackup replaces the profile's config with a snapshot, journaled like
+// any other write. A "pre-restore" snapshot is taken first.
+func (s *Services) RestoreBackup(p store.Profile, backupID string) (store.JournalEntry, error) {
+	data, err := s.Store.ReadBackup(p.ID, backupID)
+	if err != nil {
+		return store.JournalEntry{}, err
+	}
+	return s.ApplyChange(p, serverconfig.FromBytes(p.IniPath, data), Op{
+		Command: "backup restore",
+		Args:    []string{backupID},
+		Note:    "before restore of " + backupID,
+		Kind:    "pre-restore",
+	})
+}
+
+// RestoreBackupContent restores a backup in full, config and archived
+// Workshop content alike, and returns the restored item IDs. The pre-restore
+// snapshot archives the current content first, so the restore can itself be
+// rolled back.
+func (s *Services) RestoreBackupContent(p store.Profile, backupID string) (store.JournalEntry, []string, error) {
+	if p.WorkshopContentPath == "" {
+		return store.JournalEntry{}, nil, ErrNoContentPath
+	}
+	if _, err := s.Store.BackupContent(p.ID, backupID); err != nil {
+		return store.JournalEntry{}, nil, err
+	}
+	// Pin the backup while restoring, so pruning after the pre-restore
+	// snapshot can't take it (and its content) away midway.
+	if b, err := s.findBackup(p.ID, backupID); err == nil && !b.Pinned {
+		if err := s.Store.PinBackup(p.ID, backupID, true); err != nil {
+			return store.JournalEntry{}, nil, err
+		}
+		defer s.Store.PinBackup(p.ID, backupID, false)
+	}
+	data, err := s.Store.ReadBackup(p.ID, backupID)
+	if err != nil {
+		return store.JournalEntry{}, nil, err
+	}
+	withContent := p
+	withContent.ArchiveContent = true
+	entry, err := s.ApplyChange(withContent, serverconfig.FromBytes(p.IniPath, data), Op{
+		Command: "backup restore",
+		Args:    []string{backupID, "--with-content"},
+		Note:    "before restore of " + backupID,
+		Kind:    "pre-restore",
+	})
+	if err != nil {
+		return entry, nil, err
+	}
+	ids, err := s.Store.RestoreContent(p.ID, backupID, p.WorkshopContentPath)
+	return entry, ids, err
+}
+
+func (s *Services) findBackup(profileID, backupID string) (store.BackupEntry, error) {
+	entries, err := s.Store.Backups(profileID)
+	if err != nil {
+		return store.BackupEntry{}, err
+	}
+	for _, e := range entries {
+		if e.ID == backupID {
+			return e, nil
+		}
+	}
+	return store.BackupEntry{}, store.ErrNoBackup
+}
+
+// selectionArgs renders a selection the way `backup restore --only` takes it.
+func selectionArgs(sel serverconfig.Selection) []string {
+	var out []string
+	for _, part := range []struct {
+		on   bool
+		name string
+	}{{sel.Mods, "mods"}, {sel.WorkshopItems, "items"}, {sel.Maps, "maps"}} {
+		if part.on {
+			out = append(out, part.name)
+		}
+	}
+	if len(sel.Keys) > 0 {
+		out = append(out, "keys="+strings.Join(sel.Keys, ","))
+	}
+	return out
+}
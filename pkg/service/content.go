@@ -0,0 +1,170 @@
+package service
+
+import (
+	"context"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/steamcmd"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// ContentItem is one Workshop item in a ContentReport.
+type ContentItem struct {
+	ID        string
+	Title     string    // from Steam, when fetched
+	Size      int64     // bytes on disk, or the Workshop's file size when not downloaded
+	Installed time.Time // revision on disk, per the appworkshop manifest (zero if unknown)
+	Workshop  time.Time // latest revision on the Workshop (zero if unknown)
+}
+
+// ContentReport compares a profile's downloaded Workshop content with its
+// config.
+type ContentReport struct {
+	ContentPath  string
+	Manifest     string        // the appworkshop manifest read ("" if there was none)
+	Missing      []ContentItem // configured but not downloaded
+	Outdated     []ContentItem // downloaded, but older than the Workshop revision
+	Unreferenced []ContentItem // downloaded, but no longer in WorkshopItems
+	Current      int           // configured items downloaded and up to date
+	Unchecked    []string      // downloaded items whose Workshop revision couldn't be fetched
+}
+
+// ContentStatus reads the profile's Workshop content directory and Steam's
+// appworkshop_108600.acf manifest beside it, and reports configured items
+// that aren't downloaded, downloaded items older than the Workshop's latest
+// revision, and downloaded items no longer configured. Without a manifest
+// (content copied by hand), item folders still count as downloaded but their
+// revision is unknown, so they are never reported as outdated.
+func (s *Services) ContentStatus(ctx context.Context, p store.Profile, sm domain.ServerMods) (ContentReport, error) {
+	content := p.WorkshopContentPath
+	if strings.TrimSpace(content) == "" {
+		return ContentReport{}, ErrNoContentPath
+	}
+	rep := ContentReport{ContentPath: content}
+
+	manifest := steamcmd.ManifestPath(content)
+	installed, err := steamcmd.ReadManifest(manifest)
+	switch {
+	case err == nil:
+		rep.Manifest = manifest
+	case os.IsNotExist(err):
+		installed = map[string]steamcmd.InstalledItem{}
+	default:
+		return rep, err
+	}
+
+	// An item is downloaded when its folder exists; the manifest, when it has
+	// an entry, supplies the revision and size.
+	onDisk := map[string]bool{}
+	entries, err := os.ReadDir(content)
+	if err != nil && !os.IsNotExist(err) {
+		return rep, err
+	}
+	for _, e := range entries {
+		if e.IsDir() && isWorkshopID(e.Name()) {
+			onDisk[e.Name()] = true
+		}
+	}
+	size := func(id string) int64 {
+		if it, ok := installed[id]; ok && it.Size > 0 {
+			return it.Size
+		}
+		return dirSize(filepath.Join(content, id))
+	}
+
+	configured := domain.Dedupe(sm.WorkshopItems)
+	var present []string
+	for _, id := range configured {
+		if onDisk[id] {
+			present = append(present, id)
+		} else {
+			rep.Missing = append(rep.Missing, ContentItem{ID: id})
+		}
+	}
+	want := toSet(configured)
+	var unreferenced []string
+	for id := range onDisk {
+		if !want[id] {
+			unreferenced = append(unreferenced, id)
+		}
+	}
+	sort.Strings(unreferenced)
+
+	// Titles for everything listed, and Workshop revisions for what's on disk.
+	lookup := append(append([]string{}, configured...), unreferenced...)
+	items := map[string]steam.WorkshopItem{}
+	if len(lookup) > 0 {
+		fetched, _, err := s.Steam.GetDetails(ctx, lookup)
+		partial, err := partialResult(err)
+		if err != nil {
+			return rep, err
+		}
+		if partial != nil {
+			failed := toSet(partial.IDs)
+			for _, id := range present {
+				if failed[id] {
+					rep.Unchecked = append(rep.Unchecked, id)
+				}
+			}
+		}
+		for _, it := range fetched {
+			items[it.PublishedFileID] = it
+		}
+	}
+	describe := func(id string) ContentItem {
+		ci := ContentItem{ID: id, Title: items[id].Title}
+		if onDisk[id] {
+			ci.Size = size(id)
+		} else {
+			ci.Size = int64(items[id].FileSize)
+		}
+		if it, ok := installed[id]; ok && it.TimeUpdated > 0 {
+			ci.Installed = time.Unix(it.TimeUpdated, 0)
+		}
+		if t := items[id].TimeUpdated; t > 0 {
+			ci.Workshop = time.Unix(t, 0)
+		}
+		return ci
+	}
+
+	for i := range rep.Missing {
+		rep.Missing[i] = describe(rep.Missing[i].ID)
+	}
+	unchecked := toSet(rep.Unchecked)
+	for _, id := range present {
+		if unchecked[id] {
+			continue // no Workshop revision to compare against
+		}
+		ci := describe(id)
+		if !ci.Installed.IsZero() && ci.Workshop.After(ci.Installed) {
+			rep.Outdated = append(rep.Outdated, ci)
+		} else {
+			rep.Current++
+		}
+	}
+	for _, id := range unreferenced {
+		rep.Unreferenced = append(rep.Unreferenced, describe(id))
+	}
+	return rep, nil
+}
+
+// dirSize sums the sizes of the regular files under dir.
+func dirSize(dir string) int64 {
+	var n int64
+	filepath.WalkDir(dir, func(_ string, d fs.DirEntry, err error) error {
+		if err == nil && d.Type().IsRegular() {
+			if info, err := d.Info(); err == nil {
+				n += info.Size()
+			}
+		}
+		return nil
+	})
+	return n
+}
@@ -0,0 +1,138 @@
+package service
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/build"
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// ValidateDisk checks a ServerMods against the content under the profile's
+// WorkshopContentPath instead of the Workshop, for servers that can't reach
+// the Steam Web API. Which mods and maps each item provides, and what they
+// require, come from the mod.info files and folder layout. It runs the checks
+// Validate does that don't need Workshop metadata (unknown, unused, and
+// clashing mod IDs, missing requires, map availability) plus ValidateProfile's
+// mod.info checks, and counts mods in the profile's local mods directory as
+// provided. Items that aren't downloaded can't be checked and make the
+// report incomplete.
+func (s *Services) ValidateDisk(p store.Profile, sm domain.ServerMods) (domain.Report, error) {
+	var report domain.Report
+	if strings.TrimSpace(p.WorkshopContentPath) == "" {
+		return report, ErrNoContentPath
+	}
+	if info, err := os.Stat(p.WorkshopContentPath); err != nil {
+		return report, err
+	} else if !info.IsDir() {
+		return report, fmt.Errorf("workshop content path %s is not a directory", p.WorkshopContentPath)
+	}
+
+	b := build.Parse(p.Build)
+	provider := &modinfo.DiskProvider{Root: p.WorkshopContentPath}
+	for _, o := range modinfoOptions(p) {
+		o(provider)
+	}
+	onDisk, err := provider.Items()
+	if err != nil {
+		return report, err
+	}
+
+	for _, id := range domain.Dedupe(sm.WorkshopItems) {
+		if _, ok := onDisk[id]; !ok {
+			report.Unchecked = append(report.Unchecked, id)
+			report.Add(domain.Finding{Severity: domain.SeverityWarning, Code: domain.CodeUnchecked, Subject: id,
+				Message: "workshop item " + id + " is not downloaded under " + filepath.Clean(p.WorkshopContentPath),
+				Hint:    "download it with `pzmod download`, or validate against Steam"})
+		}
+	}
+
+	declaredMods := map[string]bool{}
+	declaredBy := map[string][]string{}   // modID -> item IDs that declare it
+	infos := map[string]modinfo.ModInfo{} // enabled mod ID -> its mod.info
+	providedMaps := map[string]bool{}
+	for _, id := range domain.Dedupe(sm.WorkshopItems) {
+		mods, ok := onDisk[id]
+		if !ok {
+			continue
+		}
+		if len(mods) == 0 {
+			report.AddFinding(domain.SeverityInfo, domain.CodeNoModID, id,
+				"workshop item "+id+" has no mod.info on disk (you may need to set its Mod ID manually)")
+		}
+		inItem := map[string]bool{}
+		for _, mi := range mods {
+			if inItem[mi.ID] {
+				continue // the same mod in two layouts of one item
+			}
+			inItem[mi.ID] = true
+			declaredMods[mi.ID] = true
+			declaredBy[mi.ID] = append(declaredBy[mi.ID], id)
+			if !sm.HasMod(mi.ID) {
+				report.AddFinding(domain.SeverityWarning, domain.CodeUnusedModID, mi.ID,
+					"mod ID "+mi.ID+" (from item "+id+") is not enabled in Mods=")
+				continue
+			}
+			if _, seen := infos[mi.ID]; !seen {
+				infos[mi.ID] = mi
+			}
+			for _, mp := range mi.Maps {
+				providedMaps[mp] = true
+				if !sm.HasMap(mp) {
+					report.AddFinding(domain.SeverityInfo, domain.CodeUnusedMap, mp,
+						"map "+mp+" (from "+mi.ID+") is not enabled in Map=")
+				}
+			}
+		}
+	}
+
+	// Local mods count as provided; unlike Workshop items, a local mods
+	// directory often holds more than the server enables, so those aren't
+	// reported as unused.
+	localMods, err := s.LocalMods(p)
+	if err != nil {
+		return report, err
+	}
+	local := map[string]bool{}
+	for _, mi := range localMods {
+		local[mi.ID] = true
+		declaredMods[mi.ID] = true
+		if _, seen := infos[mi.ID]; seen || !sm.HasMod(mi.ID) {
+			continue
+		}
+		infos[mi.ID] = mi
+		for _, mp := range mi.Maps {
+			providedMaps[mp] = true
+		}
+	}
+
+	checkEnabledMods(&report, sm, declaredMods, declaredBy)
+
+	checkRequires(&report, sm, infos, declaredBy, local, nil, nil)
+
+	// Map availability: every custom Map= entry should come from an enabled mod.
+	for _, mp := range sm.Maps {
+		if mp == "" || domain.IsBaseMap(mp) || providedMaps[mp] {
+			continue
+		}
+		if report.Incomplete() {
+			report.AddFinding(domain.SeverityInfo, domain.CodeUnknownMap, mp,
+				"map "+mp+" is not provided by any checked mod")
+		} else {
+			report.AddFinding(domain.SeverityWarning, domain.CodeUnknownMap, mp,
+				"map "+mp+" is enabled but no enabled mod on disk provides it")
+		}
+	}
+
+	for _, f := range incompatibilities(sm, infos) {
+		report.Add(f)
+	}
+	for _, f := range build.ModInfoWarnings(b, infos) {
+		report.Add(f)
+	}
+	return report, nil
+}
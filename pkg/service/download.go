@@ -0,0 +1,151 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/steamcmd"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// ErrNoContentPath is returned by Download for a profile without a Workshop
+// content path.
+var ErrNoContentPath = errors.New("the profile has no Workshop content path - set one with `pzmod profile edit <id> --workshop-path <dir>`")
+
+// DownloadOptions configures Download.
+type DownloadOptions struct {
+	SteamCMD string               // steamcmd binary; empty for steamcmd on PATH
+	Prune    bool                 // remove content for items no longer in WorkshopItems
+	Progress func(steamcmd.Event) // called as steamcmd reports on each item; may be nil
+}
+
+// DownloadResult is what Download did.
+type DownloadResult struct {
+	ContentPath string
+	Downloaded  []string          // landed under ContentPath
+	Failed      map[string]string // item ID -> why
+	Pruned      []string          // content removed by Prune
+}
+
+// Download fetches the content of every item in sm.WorkshopItems with
+// steamcmd into the profile's WorkshopContentPath, then checks each item
+// actually landed there. steamcmd can only download into a
+// <dir>/steamapps/workshop/content/108600 tree, so the content path must be
+// one. With Prune, content folders for items no longer listed are removed.
+// Entries that aren't numeric Workshop IDs are never passed to steamcmd; they
+// are reported in Failed.
+func (s *Services) Download(ctx context.Context, p store.Profile, sm domain.ServerMods, opts DownloadOptions) (DownloadResult, error) {
+	content := p.WorkshopContentPath
+	if strings.TrimSpace(content) == "" {
+		return DownloadResult{}, ErrNoContentPath
+	}
+	installDir, err := steamcmdInstallDir(content)
+	if err != nil {
+		return DownloadResult{}, err
+	}
+	res := DownloadResult{ContentPath: content, Failed: map[string]string{}}
+
+	var ids []string
+	for _, id := range domain.Dedupe(sm.WorkshopItems) {
+		if isWorkshopID(id) {
+			ids = append(ids, id)
+		} else {
+			res.Failed[id] = "not a numeric Workshop ID"
+		}
+	}
+	if len(ids) > 0 {
+		runner := steamcmd.Runner{Binary: opts.SteamCMD, InstallDir: installDir}
+		failed := map[string]string{}
+		done, err := runner.Download(ctx, ids, func(e steamcmd.Event) {
+			if e.Kind == steamcmd.EventFailed {
+				failed[e.ID] = e.Err
+			}
+			if opts.Progress != nil {
+				opts.Progress(e)
+			}
+		})
+		if err != nil {
+			return res, err
+		}
+		for _, id := range ids {
+			dir := filepath.Join(content, id)
+			switch e, ok := done[id]; {
+			case !ok && failed[id] != "":
+				res.Failed[id] = failed[id]
+			case !ok:
+				res.Failed[id] = "steamcmd did not report on it"
+			case !hasContent(dir):
+				res.Failed[id] = fmt.Sprintf("steamcmd put it in %s, not %s", e.Path, dir)
+			default:
+				res.Downloaded = append(res.Downloaded, id)
+			}
+		}
+	}
+
+	if opts.Prune {
+		pruned, err := pruneContent(content, ids)
+		res.Pruned = pruned
+		if err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// steamcmdInstallDir maps a content path of the form
+// <dir>/steamapps/workshop/content/108600 to the <dir> steamcmd's
+// +force_install_dir takes.
+func steamcmdInstallDir(content string) (string, error) {
+	dir := filepath.Clean(content)
+	for _, want := range []string{strconv.Itoa(steamcmd.AppID), "content", "workshop", "steamapps"} {
+		if !strings.EqualFold(filepath.Base(dir), want) {
+			return "", fmt.Errorf("workshop path %s is not a .../steamapps/workshop/content/%d directory, which is where steamcmd downloads to", content, steamcmd.AppID)
+		}
+		dir = filepath.Dir(dir)
+	}
+	return dir, nil
+}
+
+// hasContent reports whether dir is a non-empty directory.
+func hasContent(dir string) bool {
+	entries, err := os.ReadDir(dir)
+	return err == nil && len(entries) > 0
+}
+
+// pruneContent removes item folders under content whose IDs are not in keep.
+// Only folders named like Workshop IDs are touched.
+func pruneContent(content string, keep []string) ([]string, error) {
+	entries, err := os.ReadDir(content)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	keepSet := toSet(keep)
+	var pruned []string
+	for _, e := range entries {
+		name := e.Name()
+		if !e.IsDir() || keepSet[name] || !isWorkshopID(name) {
+			continue
+		}
+		if err := os.RemoveAll(filepath.Join(content, name)); err != nil {
+			return pruned, err
+		}
+		pruned = append(pruned, name)
+	}
+	sort.Strings(pruned)
+	return pruned, nil
+}
+
+func isWorkshopID(s string) bool {
+	_, err := strconv.ParseUint(s, 10, 64)
+	return err == nil
+}
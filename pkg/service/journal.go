@@ -0,0 +1,189 @@
+package service
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"strconv"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// ErrConfigChanged is returned by Undo when the config on disk no longer
+// matches what the last journaled operation wrote.
+var ErrConfigChanged = errors.New("config changed outside pzmod")
+
+// Op describes a config write for the operation journal.
+type Op struct {
+	Command string // e.g. "mods add"
+	Args    []string
+	// Note is the pre-operation snapshot's note; empty skips the snapshot
+	// (--no-backup), which also makes the operation impossible to undo.
+	Note string
+	Kind string // snapshot kind, "auto" when empty
+
+	undoes []int
+}
+
+// ApplyChange saves cfg as the profile's config and journals the write: who
+// ran it, the arguments, what changed compared with the file on disk, and the
+// snapshot taken just before.
+func (s *Services) ApplyChange(p store.Profile, cfg *serverconfig.Config, op Op) (store.JournalEntry, error) {
+	before, err := serverconfig.Load(p.IniPath)
+	missing := errors.Is(err, os.ErrNotExist)
+	if missing {
+		before = serverconfig.FromBytes(p.IniPath, nil) // e.g. restoring a deleted config
+	} else if err != nil {
+		return store.JournalEntry{}, err
+	}
+	entry := store.JournalEntry{
+		Actor:   s.actor(),
+		Command: op.Command,
+		Args:    op.Args,
+		Summary: journalSummary(serverconfig.Summarize(before, cfg)),
+		Undoes:  op.undoes,
+	}
+	if op.Note != "" && !missing {
+		kind := op.Kind
+		if kind == "" {
+			kind = "auto"
+		}
+		backup, err := s.SnapshotProfile(p, op.Note, kind)
+		if err != nil {
+			return entry, err
+		}
+		entry.BackupID = backup.ID
+	}
+	if err := cfg.SaveTo(p.IniPath); err != nil {
+		return entry, err
+	}
+	entry.SHA256 = sha256Hex(cfg.Bytes())
+	return s.Store.AppendJournal(p.ID, entry)
+}
+
+// UndoResult is the outcome of Undo.
+type UndoResult struct {
+	Entry    store.JournalEntry   // the journaled undo itself
+	Reverted []store.JournalEntry // newest first
+}
+
+// Undoable returns the journal entries Undo can still revert, newest first:
+// operations that are neither undos themselves nor already undone.
+func Undoable(journal []store.JournalEntry) []store.JournalEntry {
+	undone := UndoneIDs(journal)
+	var out []store.JournalEntry
+	for i := len(journal) - 1; i >= 0; i-- {
+		if e := journal[i]; !e.IsUndo() && !undone[e.ID] {
+			out = append(out, e)
+		}
+	}
+	return out
+}
+
+// UndoneIDs returns the IDs of journal entries a later undo reverted.
+func UndoneIDs(journal []store.JournalEntry) map[int]bool {
+	undone := map[int]bool{}
+	for _, e := range journal {
+		for _, id := range e.Undoes {
+			undone[id] = true
+		}
+	}
+	return undone
+}
+
+// Undo reverts the last n journaled operations by restoring the snapshot taken
+// before the oldest of them. It refuses when the config was changed since the
+// last journaled write, unless force is set. The undo is journaled (and
+// snapshotted) too, so it can be audited and restored like any other write.
+func (s *Services) Undo(p store.Profile, n int, force bool) (UndoResult, error) {
+	var res UndoResult
+	if n < 1 {
+		return res, errors.New("undo count must be at least 1")
+	}
+	journal, err := s.Store.Journal(p.ID)
+	if err != nil {
+		return res, err
+	}
+	undoable := Undoable(journal)
+	if len(undoable) == 0 {
+		return res, errors.New("nothing to undo")
+	}
+	if n > len(undoable) {
+		return res, fmt.Errorf("only %d operation(s) can be undone", len(undoable))
+	}
+
+	last := journal[len(journal)-1]
+	current, err := os.ReadFile(p.IniPath)
+	if err != nil {
+		return res, err
+	}
+	if !force && sha256Hex(current) != last.SHA256 {
+		return res, fmt.Errorf("%w since #%d (%s by %s); use --force to undo anyway", ErrConfigChanged, last.ID, last.Command, last.Actor)
+	}
+
+	res.Reverted = undoable[:n]
+	target := res.Reverted[n-1]
+	if target.BackupID == "" {
+		return res, fmt.Errorf("#%d (%s) was saved without a backup and cannot be undone", target.ID, target.Command)
+	}
+	data, err := s.Store.ReadBackup(p.ID, target.BackupID)
+	if errors.Is(err, store.ErrNoBackup) {
+		return res, fmt.Errorf("the backup taken before #%d (%s) has been pruned", target.ID, target.BackupID)
+	}
+	if err != nil {
+		return res, err
+	}
+
+	op := Op{Command: "undo", Args: []string{strconv.Itoa(n)}, Note: fmt.Sprintf("before undo of #%d", target.ID), Kind: "pre-restore"}
+	for _, e := range res.Reverted {
+		op.undoes = append(op.undoes, e.ID)
+	}
+	res.Entry, err = s.ApplyChange(p, serverconfig.FromBytes(p.IniPath, data), op)
+	return res, err
+}
+
+// actor names who is running pzmod for the journal: $PZMOD_ACTOR when set,
+// else the invoking user (the sudo caller rather than root), as user@host.
+func (s *Services) actor() string {
+	if s.Actor != nil {
+		return s.Actor()
+	}
+	if a := os.Getenv("PZMOD_ACTOR"); a != "" {
+		return a
+	}
+	name := os.Getenv("SUDO_USER")
+	if name == "" {
+		if u, err := user.Current(); err == nil {
+			name = u.Username
+		}
+	}
+	if name == "" {
+		name = "unknown"
+	}
+	if host, err := os.Hostname(); err == nil && host != "" {
+		return name + "@" + host
+	}
+	return name
+}
+
+func journalSummary(sum serverconfig.Summary) store.JournalSummary {
+	delta := func(d domain.Delta) store.JournalDelta {
+		return store.JournalDelta{Added: d.Added, Removed: d.Removed, Reordered: d.Reordered}
+	}
+	return store.JournalSummary{
+		Mods:          delta(sum.Mods),
+		WorkshopItems: delta(sum.WorkshopItems),
+		Maps:          delta(sum.Maps),
+		ChangedFields: sum.ChangedFields,
+	}
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+	return hex.EncodeToString(sum[:])
+}
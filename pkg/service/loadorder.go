@@ -82,11 +82,18 @@ func (s *Services) SuggestLoadOrder(ctx context.Context, sm domain.ServerMods, p
 		}
 	}
 
-	// Optional on-disk enrichment via mod.info require= edges (keyed by mod ID).
+	// Optional on-disk enrichment via mod.info (keyed by mod ID): require= and
+	// loadModAfter= put the named mods first, loadModBefore= puts them after.
 	for mod, info := range s.providerFor(profile).Lookup(modIDsOf(sm.Mods)) {
 		for _, req := range info.Require {
 			addEdge(mod, req)
 		}
+		for _, after := range info.LoadAfter {
+			addEdge(mod, after)
+		}
+		for _, before := range info.LoadBefore {
+			addEdge(before, mod)
+		}
 	}
 
 	framework := map[string]bool{} // keyed by RAW token (TopoOrder iterates raw tokens)
@@ -0,0 +1,38 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// SteamOptions are the client options for a profile ("" for none): the
+// on-disk Workshop cache under the store root, the effective network settings
+// (proxy, CA certificates, timeout, base URL), and cache-only mode when
+// offline.
+func SteamOptions(st *store.Store, profileID string, offline bool) ([]steam.Option, error) {
+	opts := []steam.Option{steam.WithCache(steam.NewDiskCache(st.WorkshopCacheDir(), steam.DiskCacheConfig{}))}
+	if offline {
+		opts = append(opts, steam.WithOffline())
+	}
+
+	n, err := st.Network(profileID)
+	if err != nil {
+		return nil, err
+	}
+	if err := n.Validate(); err != nil {
+		return nil, fmt.Errorf("network settings: %w", err)
+	}
+	if n.Proxy != "" || len(n.CAFiles) > 0 || n.Timeout != "" {
+		hc, err := steam.Transport{Proxy: n.Proxy, CAFiles: n.CAFiles, Timeout: n.TimeoutDuration()}.HTTPClient()
+		if err != nil {
+			return nil, fmt.Errorf("network settings: %w", err)
+		}
+		opts = append(opts, steam.WithHTTPClient(hc))
+	}
+	if n.BaseURL != "" {
+		opts = append(opts, steam.WithBaseURL(n.BaseURL))
+	}
+	return opts, nil
+}
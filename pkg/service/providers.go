@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+// Clash is a mod ID that several installed Workshop items declare, typically
+// a mod and its forks or re-uploads.
+type Clash struct {
+	ModID  string
+	Items  []steam.WorkshopItem // the declaring items, in WorkshopItems order
+	Pinned string               // the item Mods= pins the ID to, if any
+}
+
+// Clashes lists the mod IDs several installed items declare, sorted by ID.
+// Items Steam doesn't answer for can't be checked and are left out.
+func (s *Services) Clashes(ctx context.Context, sm domain.ServerMods) ([]Clash, error) {
+	items, err := s.installedItems(ctx, sm)
+	if err != nil {
+		return nil, err
+	}
+	byMod := map[string][]steam.WorkshopItem{}
+	for _, it := range items {
+		for _, m := range domain.Dedupe(it.Parse().Mods) {
+			byMod[m] = append(byMod[m], it)
+		}
+	}
+	var out []Clash
+	for id, declaring := range byMod {
+		if len(declaring) < 2 {
+			continue
+		}
+		c := Clash{ModID: id, Items: declaring}
+		for _, raw := range sm.Mods {
+			if r := domain.ParseModRef(raw); r.ID == id && r.Workshop != "" {
+				c.Pinned = r.Workshop
+				break
+			}
+		}
+		out = append(out, c)
+	}
+	sort.Slice(out, func(i, j int) bool { return out[i].ModID < out[j].ModID })
+	return out, nil
+}
+
+// PlanProvider plans making item the provider of modID, optionally dropping
+// the other installed items that declare it (see domain.PlanProvider). item
+// must be an installed item that declares modID.
+func (s *Services) PlanProvider(ctx context.Context, sm domain.ServerMods, modID, item string, drop bool) (domain.ProviderPlan, error) {
+	items, err := s.installedItems(ctx, sm)
+	if err != nil {
+		return domain.ProviderPlan{}, err
+	}
+	decl := map[string]domain.ModDecl{}
+	var declaring []string
+	for _, it := range items {
+		p := it.Parse()
+		decl[it.PublishedFileID] = domain.ModDecl{Mods: p.Mods, Maps: p.Maps}
+		for _, m := range p.Mods {
+			if m == modID {
+				declaring = append(declaring, it.PublishedFileID)
+				break
+			}
+		}
+	}
+	if _, ok := decl[item]; !ok || !sm.HasItem(item) {
+		return domain.ProviderPlan{}, fmt.Errorf("workshop item %s is not installed", item)
+	}
+	if !toSet(declaring)[item] {
+		return domain.ProviderPlan{}, fmt.Errorf("workshop item %s does not declare mod ID %s (declared by: %s)",
+			item, modID, strings.Join(declaring, ", "))
+	}
+	return domain.PlanProvider(modID, item, decl, sm, drop), nil
+}
+
+// installedItems fetches the installed content items in WorkshopItems order,
+// skipping those Steam doesn't answer for.
+func (s *Services) installedItems(ctx context.Context, sm domain.ServerMods) ([]steam.WorkshopItem, error) {
+	ids := domain.Dedupe(sm.WorkshopItems)
+	fetched, _, err := s.Steam.GetDetails(ctx, ids)
+	if _, err := partialResult(err); err != nil {
+		return nil, err
+	}
+	byID := make(map[string]steam.WorkshopItem, len(fetched))
+	for _, it := range fetched {
+		byID[it.PublishedFileID] = it
+	}
+	out := make([]steam.WorkshopItem, 0, len(fetched))
+	for _, id := range ids {
+		if it, ok := byID[id]; ok && !it.IsCollection() {
+			out = append(out, it)
+		}
+	}
+	return out, nil
+}
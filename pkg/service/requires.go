@@ -0,0 +1,258 @@
+package service
+
+import (
+	"context"
+	"sort"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
+	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/store"
+)
+
+// checkRequires reports each mod ID an enabled mod's mod.info requires that
+// isn't enabled, once per required ID. The Workshop's dependency list doesn't
+// have to mention it: an unmet require= is what makes a mod silently fail to
+// load on a server that otherwise boots. Mods that missing Workshop children
+// provide are left to their missing-dependency findings.
+func checkRequires(report *domain.Report, sm domain.ServerMods, infos map[string]modinfo.ModInfo, declaredBy map[string][]string, local map[string]bool, items []steam.WorkshopItem, children map[string]steam.WorkshopItem) {
+	reported := map[string]bool{}
+	for id, child := range children {
+		if !sm.HasItem(id) {
+			for _, m := range child.Parse().Mods {
+				reported[m] = true
+			}
+		}
+	}
+	requiredBy := map[string][]string{} // required mod ID -> enabled mods requiring it
+	for id, mi := range infos {
+		for _, req := range mi.Require {
+			if req != id && !sm.HasMod(req) && !reported[req] {
+				requiredBy[req] = append(requiredBy[req], id)
+			}
+		}
+	}
+	byID := map[string]steam.WorkshopItem{}
+	for _, it := range items {
+		byID[it.PublishedFileID] = it
+	}
+
+	reqs := make([]string, 0, len(requiredBy))
+	for req := range requiredBy {
+		reqs = append(reqs, req)
+	}
+	sort.Strings(reqs)
+	for _, req := range reqs {
+		mods := requiredBy[req]
+		sort.Strings(mods)
+		f := domain.Finding{Severity: domain.SeverityError, Code: domain.CodeMissingRequire, Subject: req}
+		switch {
+		case len(declaredBy[req]) > 0:
+			f.Message = "mod " + req + ", required by " + strings.Join(mods, ", ") + " (mod.info), is not enabled in Mods="
+			f.Hint = "enable it; item " + declaredBy[req][0] + " provides it"
+		case local[req]:
+			f.Message = "mod " + req + ", required by " + strings.Join(mods, ", ") + " (mod.info), is not enabled in Mods="
+			f.Hint = "enable it; it is a local mod"
+		default:
+			f.Message = "mod " + req + ", required by " + strings.Join(mods, ", ") + " (mod.info), is not provided by any installed item"
+			f.Hint = "add the Workshop item that provides it"
+			if len(items) > 0 && listsNoDeps(mods, declaredBy, byID) {
+				f.Hint += "; the Workshop page lists no required items, so dependency resolution won't find it"
+			}
+			if report.Incomplete() {
+				f.Severity = domain.SeverityWarning // an unchecked item may provide it
+			}
+		}
+		report.Add(f)
+	}
+}
+
+// listsNoDeps reports whether none of the items providing mods declares a
+// required item on the Workshop.
+func listsNoDeps(mods []string, declaredBy map[string][]string, byID map[string]steam.WorkshopItem) bool {
+	for _, m := range mods {
+		for _, id := range declaredBy[m] {
+			if it := byID[id]; len(it.GetChildIDs()) > 0 {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// checkUnneededDeps reports an item's Workshop required items that provide no
+// mod its mods' mod.info requires. Only items whose mod.info was read, and
+// children that declare mods, are judged. These are advisory: the child may
+// still be needed for assets.
+func checkUnneededDeps(report *domain.Report, infos map[string]modinfo.ModInfo, items []steam.WorkshopItem, children map[string]steam.WorkshopItem) {
+	byID := map[string]steam.WorkshopItem{}
+	for _, it := range items {
+		byID[it.PublishedFileID] = it
+	}
+	for k, v := range children {
+		byID[k] = v
+	}
+
+	for _, item := range items {
+		if item.IsCollection() {
+			continue
+		}
+		requires := map[string]bool{}
+		known := false
+		for _, m := range item.Parse().Mods {
+			if mi, ok := infos[m]; ok {
+				known = true
+				for _, r := range mi.Require {
+					requires[r] = true
+				}
+			}
+		}
+		if !known {
+			continue
+		}
+		for _, c := range item.GetChildIDs() {
+			child, ok := byID[c]
+			if !ok {
+				continue
+			}
+			childMods := child.Parse().Mods
+			if len(childMods) == 0 {
+				continue
+			}
+			needed := false
+			for _, m := range childMods {
+				needed = needed || requires[m]
+			}
+			if !needed {
+				report.Add(domain.Finding{Severity: domain.SeverityInfo, Code: domain.CodeUnneededDep, Subject: c,
+					Message: title(item) + " lists " + title(child) + " as a required item, but its mod.info requires none of " +
+						strings.Join(childMods, ", "),
+					Hint: "it may only need the item's files; ask the author which is right"})
+			}
+		}
+	}
+}
+
+// ResolveRequires plans how to satisfy mod.info require= entries of the
+// enabled mods that nothing enabled provides (only modIDs, when given). A
+// required mod an installed item already provides is just enabled; otherwise
+// the Workshop is searched for an item declaring it, and that item is
+// resolved with its dependencies like Resolve. Required mods no item could be
+// found for are listed in UnknownRequires; a failed search (the keyless
+// client can't search at all) only puts that mod there, with the error in
+// SearchErr, so what installed items provide is still enabled.
+func (s *Services) ResolveRequires(ctx context.Context, p store.Profile, sm domain.ServerMods, modIDs []string) (ResolvePlan, error) {
+	var wanted []string
+	if len(modIDs) > 0 {
+		for _, id := range domain.Dedupe(modIDs) {
+			if !sm.HasMod(id) {
+				wanted = append(wanted, id)
+			}
+		}
+	} else {
+		seen := map[string]bool{}
+		infos := s.providerFor(p).Lookup(modIDsOf(sm.Mods))
+		ids := make([]string, 0, len(infos))
+		for id := range infos {
+			ids = append(ids, id)
+		}
+		sort.Strings(ids)
+		for _, id := range ids {
+			for _, req := range infos[id].Require {
+				if req != id && !sm.HasMod(req) && !seen[req] {
+					seen[req] = true
+					wanted = append(wanted, req)
+				}
+			}
+		}
+	}
+	if len(wanted) == 0 {
+		return ResolvePlan{Plan: domain.Plan{AddModSources: map[string]string{}}, Items: map[string]steam.WorkshopItem{}}, nil
+	}
+
+	// Installed items (and local mods) may already provide what's required.
+	provider := map[string]string{} // mod ID -> installed item ID ("" for a local mod)
+	installed, _, err := s.Steam.GetDetails(ctx, sm.WorkshopItems)
+	if _, err := partialResult(err); err != nil {
+		return ResolvePlan{}, err
+	}
+	for _, it := range installed {
+		for _, m := range it.Parse().Mods {
+			if _, ok := provider[m]; !ok {
+				provider[m] = it.PublishedFileID
+			}
+		}
+	}
+	localMods, err := s.LocalMods(p)
+	if err != nil {
+		return ResolvePlan{}, err
+	}
+	for _, mi := range localMods {
+		if _, ok := provider[mi.ID]; !ok {
+			provider[mi.ID] = ""
+		}
+	}
+
+	var enable, seeds, unknown []string
+	var searchErr error
+	for _, req := range wanted {
+		if _, ok := provider[req]; ok {
+			enable = append(enable, req)
+			continue
+		}
+		found, err := s.ProvidersOf(ctx, req)
+		if err != nil {
+			if ctx.Err() != nil {
+				return ResolvePlan{}, ctx.Err()
+			}
+			searchErr = err
+			unknown = append(unknown, req)
+			continue
+		}
+		if len(found) == 0 {
+			unknown = append(unknown, req)
+			continue
+		}
+		seeds = append(seeds, found[0].PublishedFileID)
+	}
+
+	plan, err := s.Resolve(ctx, seeds, sm)
+	if err != nil {
+		return plan, err
+	}
+	adding := toSet(plan.AddMods)
+	for _, m := range enable {
+		if !adding[m] {
+			plan.AddMods = append(plan.AddMods, m)
+		}
+		if _, ok := plan.AddModSources[m]; !ok && provider[m] != "" {
+			plan.AddModSources[m] = provider[m]
+		}
+	}
+	plan.UnknownRequires = unknown
+	plan.SearchErr = searchErr
+	return plan, nil
+}
+
+// ProvidersOf searches the Workshop for items that declare modID, most
+// relevant first.
+func (s *Services) ProvidersOf(ctx context.Context, modID string) ([]steam.WorkshopItem, error) {
+	page, err := s.Steam.QueryFiles(ctx, steam.Query{SearchText: modID, PerPage: 50})
+	if err != nil {
+		return nil, err
+	}
+	var out []steam.WorkshopItem
+	for _, it := range page.Items {
+		if it.IsCollection() {
+			continue
+		}
+		for _, m := range it.Parse().Mods {
+			if m == modID {
+				out = append(out, it)
+				break
+			}
+		}
+	}
+	return out, nil
+}
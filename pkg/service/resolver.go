@@ -12,6 +12,10 @@ import (
 type ResolvePlan struct {
 	domain.Plan
 	Items map[string]steam.WorkshopItem
+	// SearchErr is why the Workshop couldn't be searched for some of
+	// UnknownRequires (steam.ErrSearchNeedsKey without an API key), if it
+	// failed.
+	SearchErr error
 }
 
 // Resolve computes the transitive closure of seeds (Workshop IDs the user wants
@@ -22,6 +26,9 @@ type ResolvePlan struct {
 // classification is by the FETCHED item's own file type, so a collection nested
 // in a collection is still expanded, not installed. A visited set bounds the
 // BFS so cyclic dependencies terminate; cycles are reported, not failed.
+// IDs Steam fails to answer for are listed in Unresolved and the rest of the
+// closure is still computed. When several resolved items declare a mod ID
+// being added, the first one reached provides it and all are listed in Clashes.
 func (s *Services) Resolve(ctx context.Context, seeds []string, installed domain.ServerMods) (ResolvePlan, error) {
 	items := map[string]steam.WorkshopItem{}
 	edges := map[string][]string{} // itemID -> child IDs (for cycle detection)
@@ -36,7 +43,9 @@ func (s *Services) Resolve(ctx context.Context, seeds []string, installed domain
 	addMod := newOrderedSet()
 	addMap := newOrderedSet()
 	missing := newOrderedSet()
+	unresolved := newOrderedSet()
 
+	declaredBy := map[string][]string{} // mod ID -> resolved content items declaring it
 	visited := map[string]bool{}
 	var frontier []string
 	for _, id := range domain.Dedupe(seeds) {
@@ -48,9 +57,15 @@ func (s *Services) Resolve(ctx context.Context, seeds []string, installed domain
 
 	for len(frontier) > 0 {
 		fetched, miss, err := s.Steam.GetDetails(ctx, frontier)
+		partial, err := partialResult(err)
 		if err != nil {
 			return ResolvePlan{}, err
 		}
+		if partial != nil {
+			for _, id := range partial.IDs {
+				unresolved.add(id)
+			}
+		}
 		for _, m := range miss {
 			missing.add(m)
 		}
@@ -98,6 +113,7 @@ func (s *Services) Resolve(ctx context.Context, seeds []string, installed domain
 				if _, ok := plan.AddModSources[mod]; !ok {
 					plan.AddModSources[mod] = item.PublishedFileID
 				}
+				declaredBy[mod] = append(declaredBy[mod], item.PublishedFileID)
 			}
 			for _, mp := range parsed.Maps {
 				if !installedMaps[mp] {
@@ -113,7 +129,16 @@ func (s *Services) Resolve(ctx context.Context, seeds []string, installed domain
 	plan.AddMods = addMod.slice()
 	plan.AddMaps = addMap.slice()
 	plan.Missing = missing.slice()
+	plan.Unresolved = unresolved.slice()
 	plan.Cycles = domain.DetectCycles(edges)
+	for _, mod := range plan.AddMods {
+		if len(declaredBy[mod]) > 1 {
+			if plan.Clashes == nil {
+				plan.Clashes = map[string][]string{}
+			}
+			plan.Clashes[mod] = declaredBy[mod]
+		}
+	}
 
 	return ResolvePlan{Plan: plan, Items: items}, nil
 }
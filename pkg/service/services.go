@@ -6,8 +6,12 @@ package service
 
 import (
 	"context"
+	"fmt"
+	"os"
+	"strconv"
 	"time"
 
+	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/pkg/modinfo"
 	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/store"
@@ -24,6 +28,10 @@ type Services struct {
 
 	// Now is the clock used for backup notes/timestamps.
 	Now func() time.Time
+
+	// Actor, when set, names who is running pzmod in the operation journal
+	// (tests inject a fixed name here).
+	Actor func() string
 }
 
 // New constructs a Services aggregate.
@@ -31,12 +39,58 @@ func New(steamAPI steam.API, st *store.Store) *Services {
 	return &Services{Steam: steamAPI, Store: st, Now: time.Now}
 }
 
-// providerFor returns the mod.info provider for a profile (or the test override).
+// providerFor returns the mod.info provider for a profile (or the test
+// override): its Workshop content, then its local mods directory. Build 42
+// version folders are picked for the profile's build.
 func (s *Services) providerFor(p store.Profile) modinfo.Provider {
 	if s.ModInfoOverride != nil {
 		return s.ModInfoOverride
 	}
-	return modinfo.NewProvider(p.WorkshopContentPath)
+	opts := modinfoOptions(p)
+	return modinfo.Chain(
+		modinfo.NewProvider(p.WorkshopContentPath, opts...),
+		modinfo.NewLocalProvider(p.LocalModsPath, opts...),
+	)
+}
+
+func modinfoOptions(p store.Profile) []modinfo.Option {
+	if major := build.Parse(p.Build).Major(); major > 0 {
+		return []modinfo.Option{modinfo.WithGameVersion(strconv.Itoa(major))}
+	}
+	return nil
+}
+
+// LocalMods lists the mods in the profile's local mods directory (mods
+// installed without the Workshop). It is empty when the profile has none or
+// the directory doesn't exist.
+func (s *Services) LocalMods(p store.Profile) ([]modinfo.ModInfo, error) {
+	if p.LocalModsPath == "" {
+		return nil, nil
+	}
+	dp := &modinfo.DiskProvider{Root: p.LocalModsPath, Local: true}
+	for _, o := range modinfoOptions(p) {
+		o(dp)
+	}
+	mods, err := dp.Mods()
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	return mods, err
+}
+
+// ItemModInfos returns the mod.info files read from a downloaded item's folder
+// under the profile's WorkshopContentPath, in the order the item declares its
+// mods. Mods whose content isn't on disk are left out.
+func (s *Services) ItemModInfos(p store.Profile, item steam.WorkshopItem) []modinfo.ModInfo {
+	mods := item.Parse().Mods
+	infos := s.providerFor(p).Lookup(mods)
+	var out []modinfo.ModInfo
+	for _, id := range mods {
+		if mi, ok := infos[id]; ok && mi.WorkshopID == item.PublishedFileID {
+			out = append(out, mi)
+		}
+	}
+	return out
 }
 
 // Search runs a Workshop search.
@@ -44,22 +98,55 @@ func (s *Services) Search(ctx context.Context, q steam.Query) (steam.Page, error
 	return s.Steam.QueryFiles(ctx, q)
 }
 
+// SearchAll follows the result cursor from q until the search is exhausted or
+// limit items (0 for no limit) have been collected. q.Page is ignored.
+func (s *Services) SearchAll(ctx context.Context, q steam.Query, limit int) (steam.Page, error) {
+	q.Page = 0
+	var all steam.Page
+	for {
+		page, err := s.Steam.QueryFiles(ctx, q)
+		if err != nil {
+			return steam.Page{}, err
+		}
+		all.Total = page.Total
+		all.Items = append(all.Items, page.Items...)
+		if limit > 0 && len(all.Items) >= limit {
+			all.Items = all.Items[:limit]
+			all.NextCursor = page.NextCursor
+			return all, nil
+		}
+		if len(page.Items) == 0 || page.NextCursor == "" || page.NextCursor == q.Cursor {
+			return all, nil
+		}
+		q.Cursor = page.NextCursor
+	}
+}
+
 // Details fetches Workshop items by ID (for browse/detail views).
 func (s *Services) Details(ctx context.Context, ids []string) ([]steam.WorkshopItem, []string, error) {
 	return s.Steam.GetDetails(ctx, ids)
 }
 
-// SnapshotProfile backs up a profile's config file and prunes to its retention.
+// SnapshotProfile backs up a profile's config file and prunes by its retention
+// policy. With ArchiveContent set, the configured items' Workshop content is
+// archived with the snapshot too.
 func (s *Services) SnapshotProfile(p store.Profile, note, kind string) (store.BackupEntry, error) {
 	entry, err := s.Store.Snapshot(p.ID, p.IniPath, note, kind)
 	if err != nil {
 		return store.BackupEntry{}, err
 	}
-	keep := p.BackupRetention
-	if keep <= 0 {
-		keep = store.DefaultBackupRetention
+	if p.ArchiveContent && p.WorkshopContentPath != "" {
+		cfg, err := s.LoadBackup(p.ID, entry.ID)
+		if err != nil {
+			return entry, err
+		}
+		m, err := s.Store.ArchiveContent(p.ID, entry.ID, p.WorkshopContentPath, cfg.ServerMods().WorkshopItems)
+		if err != nil {
+			return entry, fmt.Errorf("archiving Workshop content: %w", err)
+		}
+		entry.Content, entry.ContentItems, entry.ContentSize = entry.ID+".content.json", len(m.Items), m.Size()
 	}
-	if err := s.Store.Prune(p.ID, keep); err != nil {
+	if _, err := s.Store.PruneWithPolicy(p.ID, p.RetentionPolicy()); err != nil {
 		return entry, err
 	}
 	return entry, nil
@@ -2,18 +2,26 @@ package service
 
 import (
 	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
 	"os"
 	"path/filepath"
 	"reflect"
+	"runtime"
 	"sort"
+	"strconv"
+	"strings"
 	"testing"
 	"time"
 
 	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/pkg/domain"
 	"github.com/kldzj/pzmod/pkg/modinfo"
+	"github.com/kldzj/pzmod/pkg/serverconfig"
 	"github.com/kldzj/pzmod/pkg/steam"
 	"github.com/kldzj/pzmod/pkg/steam/steamtest"
+	"github.com/kldzj/pzmod/pkg/steamcmd"
 	"github.com/kldzj/pzmod/pkg/store"
 )
 
@@ -175,6 +183,84 @@ func TestValidateDelisted(t *testing.T) {
 	}
 }
 
+func TestValidateUnavailableReasons(t *testing.T) {
+	f := canned()
+	f.Results = map[string]steam.Result{"901": steam.ResultFileNotFound, "902": steam.ResultAccessDenied}
+	friends := item("903", "Friends", []string{"F"}, nil, nil, steam.FileTypeMod, false)
+	friends.Visibility = steam.VisibilityFriendsOnly
+	f.Items["903"] = friends
+	f.Items["904"] = item("904", "Banned", []string{"B"}, nil, nil, steam.FileTypeMod, true)
+	s := svc(f)
+
+	sm := domain.ServerMods{WorkshopItems: []string{"901", "902", "903", "904", "999"}, Mods: []string{"F", "B"}}
+	report, err := s.Validate(context.Background(), sm, build.Unknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]struct {
+		code string
+		sev  domain.Severity
+	}{
+		"901": {domain.CodeNotFound, domain.SeverityError},
+		"902": {domain.CodePrivate, domain.SeverityError},
+		"903": {domain.CodeFriendsOnly, domain.SeverityError},
+		"904": {domain.CodeBanned, domain.SeverityWarning},
+		"999": {domain.CodeDelisted, domain.SeverityError},
+	}
+	for _, fd := range report.Findings {
+		w, ok := want[fd.Subject]
+		if !ok {
+			continue
+		}
+		if fd.Code != w.code || fd.Severity != w.sev || fd.Hint == "" {
+			t.Errorf("%s: finding = %+v; want %s at %v with a hint", fd.Subject, fd, w.code, w.sev)
+		}
+		delete(want, fd.Subject)
+	}
+	if len(want) > 0 {
+		t.Errorf("no finding for %v", want)
+	}
+}
+
+func TestValidatePartialReport(t *testing.T) {
+	f := canned()
+	f.Fail = map[string]error{"400": errors.New("steam api request failed with status 503")}
+	s := svc(f)
+
+	// 200 needs 100 (a real error); 400 fails, so MapPack can't be attributed.
+	sm := domain.ServerMods{WorkshopItems: []string{"200", "400"}, Mods: []string{"Weapons", "MapPack"}}
+	report, err := s.Validate(context.Background(), sm, build.Unknown)
+	if err != nil {
+		t.Fatalf("partial fetch failed validation outright: %v", err)
+	}
+	if !report.Incomplete() || !reflect.DeepEqual(report.Unchecked, []string{"400"}) {
+		t.Errorf("Unchecked = %v; want [400]", report.Unchecked)
+	}
+	if !hasFinding(report, domain.CodeUnchecked, "400") || !hasFinding(report, domain.CodeMissingDependency, "100") {
+		t.Errorf("findings = %+v; want unchecked 400 and the missing dependency", report.Findings)
+	}
+	for _, fd := range report.Findings {
+		if fd.Code == domain.CodeUnknownModID && fd.Severity != domain.SeverityInfo {
+			t.Errorf("unknown mod ID %s should be advisory while incomplete", fd.Subject)
+		}
+	}
+}
+
+func TestResolvePartial(t *testing.T) {
+	f := canned()
+	f.Fail = map[string]error{"100": errors.New("timeout")}
+	plan, err := svc(f).Resolve(context.Background(), []string{"200", "400"}, domain.ServerMods{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plan.Unresolved, []string{"100"}) {
+		t.Errorf("Unresolved = %v; want [100]", plan.Unresolved)
+	}
+	if !reflect.DeepEqual(plan.AddWorkshopItems, []string{"200", "400"}) {
+		t.Errorf("AddWorkshopItems = %v; want [200 400]", plan.AddWorkshopItems)
+	}
+}
+
 func TestValidateBuildCompat(t *testing.T) {
 	f := steamtest.New(func() steam.WorkshopItem {
 		it := item("900", "Old Mod", []string{"OldMod"}, nil, nil, steam.FileTypeMod, false)
@@ -378,3 +464,823 @@ func TestResolveB42(t *testing.T) {
 		t.Errorf("non-explicit Apply should add plain Weapons: %v", got.Mods)
 	}
 }
+
+func TestRestorePartial(t *testing.T) {
+	st, err := store.New(store.WithRoot(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ini := filepath.Join(t.TempDir(), "server.ini")
+	if err := os.WriteFile(ini, []byte("PublicName=Old\nMods=a;b\nWorkshopItems=1\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := store.Profile{ID: "p1", IniPath: ini}
+	s := &Services{Store: st, Now: time.Now}
+	golden, err := s.SnapshotProfile(p, "", "manual")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(ini, []byte("PublicName=New\nMods=c\nWorkshopItems=2\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	res, err := s.RestorePartial(p, golden.ID, serverconfig.Selection{Mods: true})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if res.Summary.Mods.Added != 2 || res.Journal.BackupID == "" {
+		t.Errorf("result = %+v; want 2 mods added and a pre-restore snapshot", res)
+	}
+	data, _ := os.ReadFile(ini)
+	if want := "PublicName=New\nMods=a;b\nWorkshopItems=2\n"; string(data) != want {
+		t.Errorf("config = %q; want %q", data, want)
+	}
+
+	if _, err := s.RestorePartial(p, golden.ID, serverconfig.Selection{}); err == nil {
+		t.Error("empty selection should fail")
+	}
+}
+
+func TestUndoJournal(t *testing.T) {
+	st, err := store.New(store.WithRoot(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	ini := filepath.Join(t.TempDir(), "server.ini")
+	if err := os.WriteFile(ini, []byte("Mods=a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	p := store.Profile{ID: "p1", IniPath: ini}
+	s := &Services{Store: st, Now: time.Now, Actor: func() string { return "alice@host" }}
+
+	write := func(mods string) {
+		t.Helper()
+		cfg, err := serverconfig.Load(ini)
+		if err != nil {
+			t.Fatal(err)
+		}
+		cfg.SetMods(strings.Split(mods, ";"))
+		if _, err := s.ApplyChange(p, cfg, Op{Command: "mods add", Args: []string{mods}, Note: "before mods add"}); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("a;b")
+	write("a;b;c")
+
+	journal, _ := st.Journal("p1")
+	if len(journal) != 2 || journal[1].ID != 2 || journal[1].Actor != "alice@host" || journal[1].Summary.Mods.Added != 1 {
+		t.Fatalf("journal = %+v", journal)
+	}
+
+	// A hand edit since the last journaled write blocks undo.
+	if err := os.WriteFile(ini, []byte("Mods=x\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := s.Undo(p, 1, false); !errors.Is(err, ErrConfigChanged) {
+		t.Fatalf("undo after hand edit = %v; want ErrConfigChanged", err)
+	}
+	if _, err := s.Undo(p, 3, true); err == nil {
+		t.Error("undoing more than was journaled should fail")
+	}
+
+	res, err := s.Undo(p, 2, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if data, _ := os.ReadFile(ini); string(data) != "Mods=a\n" {
+		t.Errorf("after undo 2 = %q; want the original config", data)
+	}
+	if !reflect.DeepEqual(res.Entry.Undoes, []int{2, 1}) {
+		t.Errorf("Undoes = %v; want [2 1]", res.Entry.Undoes)
+	}
+	if _, err := s.Undo(p, 1, false); err == nil {
+		t.Error("everything is undone; another undo should fail")
+	}
+}
+
+// pagedAPI serves QueryFiles in pages of one item, chained by cursor.
+type pagedAPI struct {
+	steamtest.Fake
+	ids []string
+}
+
+func (p *pagedAPI) QueryFiles(_ context.Context, q steam.Query) (steam.Page, error) {
+	i := 0
+	if q.Cursor != "" {
+		i, _ = strconv.Atoi(q.Cursor)
+	}
+	if i >= len(p.ids) {
+		return steam.Page{Total: len(p.ids), NextCursor: q.Cursor}, nil
+	}
+	return steam.Page{
+		Items:      []steam.WorkshopItem{{PublishedFileID: p.ids[i]}},
+		Total:      len(p.ids),
+		NextCursor: strconv.Itoa(i + 1),
+	}, nil
+}
+
+func TestSearchAllFollowsCursor(t *testing.T) {
+	s := &Services{Steam: &pagedAPI{ids: []string{"a", "b", "c"}}}
+	page, err := s.SearchAll(context.Background(), steam.Query{}, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(page.Items) != 3 || page.Total != 3 {
+		t.Errorf("all = %+v; want 3 items", page)
+	}
+
+	page, _ = s.SearchAll(context.Background(), steam.Query{}, 2)
+	if len(page.Items) != 2 || page.NextCursor != "2" {
+		t.Errorf("capped = %+v; want 2 items and a cursor to resume", page)
+	}
+}
+
+func TestAuthorItems(t *testing.T) {
+	const creator = "76561198000000001"
+	f := steamtest.New(
+		steam.WorkshopItem{PublishedFileID: "1", Creator: creator},
+		steam.WorkshopItem{PublishedFileID: "2", Creator: creator},
+		steam.WorkshopItem{PublishedFileID: "3", Creator: "76561198000000002"},
+	)
+	s := svc(f)
+	for _, ref := range []string{"1", creator} {
+		a, err := s.AuthorItems(context.Background(), ref)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if a.SteamID != creator || len(a.Items) != 2 {
+			t.Errorf("AuthorItems(%s) = %+v", ref, a)
+		}
+	}
+	if _, err := s.AuthorItems(context.Background(), "999"); err == nil {
+		t.Error("unknown item should fail")
+	}
+}
+
+func TestSteamOptionsUseProfileNetwork(t *testing.T) {
+	var hits int
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		hits++
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"result":1,"publishedfileid":"1"}]}}`))
+	}))
+	t.Cleanup(srv.Close)
+
+	st, err := store.New(store.WithRoot(t.TempDir()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	p, _ := st.AddProfile(store.Profile{Name: "main", IniPath: "server.ini", Network: &store.Network{BaseURL: srv.URL, Timeout: "5s"}})
+
+	opts, err := SteamOptions(st, p.ID, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := steam.New("KEY", opts...).GetDetails(context.Background(), []string{"1"}); err != nil {
+		t.Fatal(err)
+	}
+	if hits != 1 {
+		t.Errorf("hits = %d; want the profile's base URL used", hits)
+	}
+
+	// Settings that no longer load (a deleted CA file) are an error.
+	p.Network.CAFiles = []string{filepath.Join(t.TempDir(), "gone.pem")}
+	_ = st.UpdateProfile(p)
+	if _, err := SteamOptions(st, p.ID, false); err == nil {
+		t.Error("missing CA file accepted")
+	}
+}
+
+func TestWorkshopSnapshotReproducesValidation(t *testing.T) {
+	f := canned()
+	f.Results = map[string]steam.Result{"901": steam.ResultAccessDenied}
+	sm := domain.ServerMods{WorkshopItems: []string{"300", "700", "901"}, Mods: []string{"Weapons", "Seven", "Ghost"}}
+
+	snap, err := svc(f).WorkshopSnapshot(context.Background(), sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	path := filepath.Join(t.TempDir(), "ws.json")
+	if err := snap.Save(path); err != nil {
+		t.Fatal(err)
+	}
+	var ids []string
+	for _, it := range snap.Items {
+		ids = append(ids, it.PublishedFileID)
+	}
+	if want := []string{"100", "200", "300", "700", "800"}; !reflect.DeepEqual(ids, want) {
+		t.Errorf("snapshot items = %v; want the installed items and their children %v", ids, want)
+	}
+
+	loaded, err := steam.LoadSnapshot(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	live, err := svc(f).Validate(context.Background(), sm, build.Unknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	frozen, err := (&Services{Steam: steam.NewSnapshotAPI(loaded), Now: time.Now}).Validate(context.Background(), sm, build.Unknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(live.Findings, frozen.Findings) {
+		t.Errorf("snapshot validation differs:\nlive   %+v\nfrozen %+v", live.Findings, frozen.Findings)
+	}
+
+	// An item added to the config after the snapshot was taken is not reported
+	// as gone from Steam.
+	sm.WorkshopItems = append(sm.WorkshopItems, "950")
+	stale, err := (&Services{Steam: steam.NewSnapshotAPI(loaded), Now: time.Now}).Validate(context.Background(), sm, build.Unknown)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var codes []string
+	for _, f := range stale.Findings {
+		if f.Subject == "950" {
+			codes = append(codes, f.Code)
+		}
+	}
+	if !reflect.DeepEqual(codes, []string{domain.CodeNotInSnapshot}) {
+		t.Errorf("findings for 950 = %v; want [%s]", codes, domain.CodeNotInSnapshot)
+	}
+}
+
+func TestWorkshopSnapshotNeedsEveryAnswer(t *testing.T) {
+	f := canned()
+	f.Fail = map[string]error{"100": steam.ErrCircuitOpen}
+	_, err := svc(f).WorkshopSnapshot(context.Background(), domain.ServerMods{WorkshopItems: []string{"200"}})
+	if err == nil || !strings.Contains(err.Error(), "100") {
+		t.Errorf("err = %v; want a failure naming 100", err)
+	}
+}
+
+// fakeSteamCMD writes a steamcmd stand-in that "downloads" each item into
+// +force_install_dir, except item 666, which fails.
+func fakeSteamCMD(t *testing.T) string {
+	t.Helper()
+	if runtime.GOOS == "windows" {
+		t.Skip("the fake steamcmd is a shell script")
+	}
+	script := `#!/bin/sh
+echo "$@" >> "$0.args"
+dir=.
+while [ $# -gt 0 ]; do
+  case "$1" in
+    +force_install_dir) dir=$2; shift 2 ;;
+    +workshop_download_item)
+      id=$3; shift 3
+      echo "Downloading item $id ..."
+      if [ "$id" = 666 ]; then echo "ERROR! Download item $id failed (File Not Found)."; continue; fi
+      out="$dir/steamapps/workshop/content/108600/$id"
+      mkdir -p "$out/mods/M$id" && echo "id=M$id" > "$out/mods/M$id/mod.info"
+      echo "Success. Downloaded item $id to \"$out\" (42 bytes)" ;;
+    *) shift ;;
+  esac
+done
+`
+	path := filepath.Join(t.TempDir(), "steamcmd")
+	if err := os.WriteFile(path, []byte(script), 0755); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestDownloadVerifiesAndPrunes(t *testing.T) {
+	content := filepath.Join(t.TempDir(), "steamapps", "workshop", "content", "108600")
+	stale := filepath.Join(content, "999", "mods", "Old")
+	if err := os.MkdirAll(stale, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Join(content, "notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	p := store.Profile{ID: "p", WorkshopContentPath: content}
+	sm := domain.ServerMods{WorkshopItems: []string{"100", "666", "200"}}
+
+	var events int
+	res, err := svc(canned()).Download(context.Background(), p, sm, DownloadOptions{
+		SteamCMD: fakeSteamCMD(t),
+		Prune:    true,
+		Progress: func(steamcmd.Event) { events++ },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Downloaded, []string{"100", "200"}) {
+		t.Errorf("downloaded = %v; want [100 200]", res.Downloaded)
+	}
+	if res.Failed["666"] != "File Not Found" || len(res.Failed) != 1 {
+		t.Errorf("failed = %v; want 666: File Not Found", res.Failed)
+	}
+	if !reflect.DeepEqual(res.Pruned, []string{"999"}) {
+		t.Errorf("pruned = %v; want [999] (and not the non-ID folder)", res.Pruned)
+	}
+	if _, err := os.Stat(filepath.Join(content, "notes")); err != nil {
+		t.Error("prune removed a folder that isn't a Workshop item")
+	}
+	if events != 6 {
+		t.Errorf("progress events = %d; want 6", events)
+	}
+}
+
+func TestDownloadSkipsNonNumericIDs(t *testing.T) {
+	content := filepath.Join(t.TempDir(), "steamapps", "workshop", "content", "108600")
+	p := store.Profile{ID: "p", WorkshopContentPath: content}
+	sm := domain.ServerMods{WorkshopItems: []string{"100", "+app_update", "1 +login x"}}
+	bin := fakeSteamCMD(t)
+
+	res, err := svc(canned()).Download(context.Background(), p, sm, DownloadOptions{SteamCMD: bin})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(res.Downloaded, []string{"100"}) {
+		t.Errorf("downloaded = %v; want [100]", res.Downloaded)
+	}
+	if len(res.Failed) != 2 || res.Failed["+app_update"] == "" || res.Failed["1 +login x"] == "" {
+		t.Errorf("failed = %v; want both non-numeric entries", res.Failed)
+	}
+	args, err := os.ReadFile(bin + ".args")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if strings.Contains(string(args), "app_update") || strings.Contains(string(args), "+login x") {
+		t.Errorf("steamcmd args = %q; want only numeric IDs passed", args)
+	}
+}
+
+func TestDownloadNeedsSteamappsLayout(t *testing.T) {
+	p := store.Profile{ID: "p", WorkshopContentPath: t.TempDir()}
+	_, err := svc(canned()).Download(context.Background(), p, domain.ServerMods{WorkshopItems: []string{"100"}}, DownloadOptions{})
+	if err == nil || !strings.Contains(err.Error(), "steamapps") {
+		t.Errorf("err = %v; want a steamapps layout error", err)
+	}
+	if _, err := svc(canned()).Download(context.Background(), store.Profile{}, domain.ServerMods{}, DownloadOptions{}); !errors.Is(err, ErrNoContentPath) {
+		t.Errorf("err = %v; want ErrNoContentPath", err)
+	}
+}
+
+func TestContentStatus(t *testing.T) {
+	workshop := filepath.Join(t.TempDir(), "steamapps", "workshop")
+	content := filepath.Join(workshop, "content", "108600")
+	for _, id := range []string{"100", "200", "999"} {
+		if err := os.MkdirAll(filepath.Join(content, id, "mods", "M"+id), 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	os.WriteFile(filepath.Join(content, "999", "mods", "M999", "big.pack"), make([]byte, 1000), 0644)
+	acf := `"AppWorkshop"
+{
+	"appid"		"108600"
+	"WorkshopItemsInstalled"
+	{
+		"100"	{ "size" "500" "timeupdated" "1700000000" }
+		"200"	{ "size" "700" "timeupdated" "1600000000" }
+	}
+}
+`
+	if err := os.WriteFile(filepath.Join(workshop, "appworkshop_108600.acf"), []byte(acf), 0644); err != nil {
+		t.Fatal(err)
+	}
+	f := canned()
+	for id, ts := range map[string]int64{"100": 1700000000, "200": 1650000000} {
+		it := f.Items[id]
+		it.TimeUpdated = ts
+		f.Items[id] = it
+	}
+
+	p := store.Profile{ID: "p", WorkshopContentPath: content}
+	sm := domain.ServerMods{WorkshopItems: []string{"100", "200", "400"}}
+	rep, err := svc(f).ContentStatus(context.Background(), p, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ids := func(items []ContentItem) (out []string) {
+		for _, it := range items {
+			out = append(out, it.ID)
+		}
+		return out
+	}
+	if got := ids(rep.Missing); !reflect.DeepEqual(got, []string{"400"}) {
+		t.Errorf("missing = %v; want [400]", got)
+	}
+	if got := ids(rep.Outdated); !reflect.DeepEqual(got, []string{"200"}) || rep.Outdated[0].Size != 700 {
+		t.Errorf("outdated = %+v; want 200 at 700 bytes", rep.Outdated)
+	}
+	if got := ids(rep.Unreferenced); !reflect.DeepEqual(got, []string{"999"}) || rep.Unreferenced[0].Size != 1000 {
+		t.Errorf("unreferenced = %+v; want 999 sized from disk", rep.Unreferenced)
+	}
+	if rep.Current != 1 || rep.Manifest == "" {
+		t.Errorf("current = %d, manifest = %q; want 1 and the acf path", rep.Current, rep.Manifest)
+	}
+
+	// An item Steam doesn't answer for is unchecked, not also up to date.
+	f.Fail = map[string]error{"100": errors.New("timeout")}
+	rep, err = svc(f).ContentStatus(context.Background(), p, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(rep.Unchecked, []string{"100"}) || rep.Current != 0 {
+		t.Errorf("unchecked = %v, current = %d; want [100] and 0", rep.Unchecked, rep.Current)
+	}
+	if got := ids(rep.Outdated); !reflect.DeepEqual(got, []string{"200"}) {
+		t.Errorf("outdated = %v; want [200]", got)
+	}
+}
+
+// writeModInfos lays out mod.info files under a content root, one item each.
+func writeModInfos(t *testing.T, infos map[string]string) string {
+	t.Helper()
+	root := t.TempDir()
+	n := 100
+	for mod, body := range infos {
+		n++
+		dir := filepath.Join(root, strconv.Itoa(n), "mods", mod)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id="+mod+"\n"+body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	return root
+}
+
+func TestSuggestLoadOrderModInfoOrderKeys(t *testing.T) {
+	root := writeModInfos(t, map[string]string{
+		"Skins":  "loadModAfter=\\Cars\n",
+		"Cars":   "",
+		"Decals": "loadModBefore=\\Cars\n",
+	})
+	sm := domain.ServerMods{Mods: []string{"Skins", "Cars", "Decals"}}
+	plan, err := svc(steamtest.New()).SuggestLoadOrder(context.Background(), sm, store.Profile{WorkshopContentPath: root})
+	if err != nil {
+		t.Fatal(err)
+	}
+	// No framework keywords here: only the order keys can move anything.
+	if want := []string{"Decals", "Cars", "Skins"}; !reflect.DeepEqual(plan.Ordered, want) {
+		t.Errorf("Ordered = %v; want %v", plan.Ordered, want)
+	}
+}
+
+func TestValidateProfileModInfo(t *testing.T) {
+	root := writeModInfos(t, map[string]string{
+		"NewCars": "incompatible=\\OldCars\nversionMin=42.0\n",
+		"OldCars": "incompatible=\\NewCars\nversionMax=41.78\n",
+		"Other":   "incompatible=\\NotEnabled\n",
+	})
+	sm := domain.ServerMods{Mods: []string{"NewCars", "OldCars", "Other"}}
+
+	report, err := svc(steamtest.New()).ValidateProfile(context.Background(),
+		store.Profile{Build: "b41", WorkshopContentPath: root}, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var incompatible, compat []string
+	for _, f := range report.Findings {
+		switch f.Code {
+		case domain.CodeIncompatible:
+			incompatible = append(incompatible, f.Subject)
+		case domain.CodeBuildCompat:
+			compat = append(compat, f.Subject)
+		}
+	}
+	// Both mods name each other; that is one conflict, reported once.
+	if !reflect.DeepEqual(incompatible, []string{"NewCars"}) {
+		t.Errorf("incompatible findings = %v; want [NewCars]", incompatible)
+	}
+	if !report.HasErrors() {
+		t.Error("incompatible mods should fail validation")
+	}
+	if !reflect.DeepEqual(compat, []string{"NewCars"}) {
+		t.Errorf("build-compat findings on b41 = %v; want [NewCars]", compat)
+	}
+
+	report, _ = svc(steamtest.New()).ValidateProfile(context.Background(),
+		store.Profile{Build: "b42", WorkshopContentPath: root}, sm)
+	compat = nil
+	for _, f := range report.Findings {
+		if f.Code == domain.CodeBuildCompat {
+			compat = append(compat, f.Subject)
+		}
+	}
+	if !reflect.DeepEqual(compat, []string{"OldCars"}) {
+		t.Errorf("build-compat findings on b42 = %v; want [OldCars]", compat)
+	}
+}
+
+func TestValidateProfileIncompatibleWithoutModInfo(t *testing.T) {
+	// OldCars is enabled but its content isn't downloaded, so it has no mod.info.
+	root := writeModInfos(t, map[string]string{"NewCars": "incompatible=\\OldCars\n"})
+	sm := domain.ServerMods{Mods: []string{"NewCars", "OldCars"}}
+
+	report, err := svc(steamtest.New()).ValidateProfile(context.Background(),
+		store.Profile{Build: "b41", WorkshopContentPath: root}, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var incompatible []string
+	for _, f := range report.Findings {
+		if f.Code == domain.CodeIncompatible {
+			incompatible = append(incompatible, f.Subject)
+		}
+	}
+	if !reflect.DeepEqual(incompatible, []string{"NewCars"}) {
+		t.Errorf("incompatible findings = %v; want [NewCars]", incompatible)
+	}
+}
+
+func TestValidateProfileReadsB42VersionFolders(t *testing.T) {
+	root := t.TempDir()
+	for mod, body := range map[string]string{"NewCars": "incompatible=\\OldCars\n", "OldCars": ""} {
+		dir := filepath.Join(root, "900", "mods", mod, "42")
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id="+mod+"\n"+body), 0644)
+	}
+	sm := domain.ServerMods{Mods: []string{`\NewCars`, `\OldCars`}}
+
+	report, err := svc(steamtest.New()).ValidateProfile(context.Background(),
+		store.Profile{Build: "b42", WorkshopContentPath: root}, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	found := false
+	for _, f := range report.Findings {
+		found = found || (f.Code == domain.CodeIncompatible && strings.Contains(f.Hint, "42/mod.info"))
+	}
+	if !found {
+		t.Errorf("findings = %+v; want an incompatible finding naming 42/mod.info", report.Findings)
+	}
+}
+
+func TestValidateDisk(t *testing.T) {
+	root := t.TempDir()
+	mod := func(item, name, body string, maps ...string) {
+		t.Helper()
+		dir := filepath.Join(root, item, "mods", name)
+		for _, m := range maps {
+			if err := os.MkdirAll(filepath.Join(dir, "media", "maps", m), 0755); err != nil {
+				t.Fatal(err)
+			}
+		}
+		os.MkdirAll(dir, 0755)
+		if err := os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id="+name+"\n"+body), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+	mod("100", "CoreLib", "")
+	mod("200", "Weapons", "require=CoreLib,Ammo\n")
+	mod("300", "Town", "", "TownMap")
+	mod("300", "TownExtras", "")
+	os.MkdirAll(filepath.Join(root, "400", "textures"), 0755) // no mod.info
+
+	sm := domain.ServerMods{
+		Mods:          []string{"Weapons", "Town", "Ghost"},
+		WorkshopItems: []string{"100", "200", "300", "400"},
+		Maps:          []string{"Nowhere", "Muldraugh, KY"},
+	}
+	report, err := svc(nil).ValidateDisk(store.Profile{WorkshopContentPath: root}, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string][]string{}
+	for _, f := range report.Findings {
+		got[f.Code] = append(got[f.Code], f.Subject)
+	}
+	want := map[string][]string{
+		domain.CodeMissingRequire: {"Ammo", "CoreLib"},
+		domain.CodeUnusedModID:    {"CoreLib", "TownExtras"},
+		domain.CodeUnknownModID:   {"Ghost"},
+		domain.CodeUnusedMap:      {"TownMap"},
+		domain.CodeUnknownMap:     {"Nowhere"},
+		domain.CodeNoModID:        {"400"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("findings by code = %v\nwant %v", got, want)
+	}
+	if report.Incomplete() {
+		t.Error("every item is downloaded; the report should be complete")
+	}
+
+	// An item that isn't downloaded can't be checked.
+	sm.WorkshopItems = append(sm.WorkshopItems, "999")
+	report, _ = svc(nil).ValidateDisk(store.Profile{WorkshopContentPath: root}, sm)
+	if !reflect.DeepEqual(report.Unchecked, []string{"999"}) {
+		t.Errorf("unchecked = %v; want [999]", report.Unchecked)
+	}
+
+	if _, err := svc(nil).ValidateDisk(store.Profile{}, sm); !errors.Is(err, ErrNoContentPath) {
+		t.Errorf("no content path: err = %v; want ErrNoContentPath", err)
+	}
+}
+
+func TestLocalModsCountAsProvided(t *testing.T) {
+	local := t.TempDir()
+	dir := filepath.Join(local, "ServerTweaks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		t.Fatal(err)
+	}
+	os.WriteFile(filepath.Join(dir, "mod.info"), []byte("id=ServerTweaks\nloadModAfter=\\Weapons\n"), 0644)
+	p := store.Profile{LocalModsPath: local}
+	sm := domain.ServerMods{Mods: []string{"ServerTweaks", "Weapons", "CoreLib"}, WorkshopItems: []string{"100", "200"}}
+	s := svc(canned())
+
+	report, err := s.ValidateProfile(context.Background(), p, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, f := range report.Findings {
+		if f.Code == domain.CodeUnknownModID {
+			t.Errorf("unexpected %s finding for %s", f.Code, f.Subject)
+		}
+	}
+	// Plain Validate knows nothing about local mods.
+	report, _ = s.Validate(context.Background(), sm, build.Unknown)
+	if len(report.Findings) != 1 || report.Findings[0].Subject != "ServerTweaks" {
+		t.Errorf("Validate findings = %+v; want ServerTweaks unknown", report.Findings)
+	}
+
+	// The local mod's loadModAfter= orders it after Weapons.
+	plan, err := s.SuggestLoadOrder(context.Background(), sm, p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := []string{"CoreLib", "Weapons", "ServerTweaks"}; !reflect.DeepEqual(plan.Ordered, want) {
+		t.Errorf("Ordered = %v; want %v", plan.Ordered, want)
+	}
+
+	// A missing local directory is simply empty.
+	if mods, err := s.LocalMods(store.Profile{LocalModsPath: filepath.Join(local, "nope")}); err != nil || len(mods) != 0 {
+		t.Errorf("LocalMods(missing) = %v, %v", mods, err)
+	}
+}
+
+func requiresFake() *steamtest.Fake {
+	return steamtest.New(
+		item("900", "Ammo Pack", []string{"Ammo"}, nil, nil, steam.FileTypeMod, false),
+		item("910", "Guns", []string{"Guns", "GunsExtra"}, nil, nil, steam.FileTypeMod, false),
+		item("920", "Rifles", []string{"Rifles"}, nil, []string{"900"}, steam.FileTypeMod, false),
+	)
+}
+
+func TestValidateProfileMissingRequires(t *testing.T) {
+	root := writeModInfos(t, map[string]string{
+		"Guns":   "require=\\Ammo,\\GunsExtra,\\Nowhere\n",
+		"Rifles": "",
+	})
+	p := store.Profile{Build: "b41", WorkshopContentPath: root}
+	sm := domain.ServerMods{Mods: []string{"Guns"}, WorkshopItems: []string{"910"}}
+
+	report, err := svc(requiresFake()).ValidateProfile(context.Background(), p, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	hints := map[string]string{}
+	for _, f := range report.Findings {
+		if f.Code == domain.CodeMissingRequire {
+			if f.Severity != domain.SeverityError {
+				t.Errorf("%s: severity = %v; want error", f.Subject, f.Severity)
+			}
+			hints[f.Subject] = f.Hint
+		}
+	}
+	if len(hints) != 3 {
+		t.Fatalf("missing-require findings = %v; want Ammo, GunsExtra, Nowhere", hints)
+	}
+	if !strings.Contains(hints["GunsExtra"], "item 910 provides it") {
+		t.Errorf("GunsExtra hint = %q; want the installed provider named", hints["GunsExtra"])
+	}
+	if !strings.Contains(hints["Ammo"], "lists no required items") {
+		t.Errorf("Ammo hint = %q; want a note that the Workshop page lists no dependencies", hints["Ammo"])
+	}
+
+	// Rifles lists Ammo Pack as a required item, but its mod.info requires nothing.
+	sm = domain.ServerMods{Mods: []string{"Rifles", "Ammo"}, WorkshopItems: []string{"920", "900"}}
+	report, err = svc(requiresFake()).ValidateProfile(context.Background(), p, sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var unneeded []string
+	for _, f := range report.Findings {
+		if f.Code == domain.CodeUnneededDep {
+			unneeded = append(unneeded, f.Subject)
+		}
+	}
+	if !reflect.DeepEqual(unneeded, []string{"900"}) {
+		t.Errorf("unneeded-dependency findings = %v; want [900]", unneeded)
+	}
+}
+
+func TestResolveRequires(t *testing.T) {
+	root := writeModInfos(t, map[string]string{"Guns": "require=\\Ammo,\\GunsExtra,\\Nowhere\n"})
+	p := store.Profile{Build: "b41", WorkshopContentPath: root}
+	sm := domain.ServerMods{Mods: []string{"Guns"}, WorkshopItems: []string{"910"}}
+
+	plan, err := svc(requiresFake()).ResolveRequires(context.Background(), p, sm, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plan.AddWorkshopItems, []string{"900"}) {
+		t.Errorf("AddWorkshopItems = %v; want [900] (found by searching for Ammo)", plan.AddWorkshopItems)
+	}
+	mods := append([]string{}, plan.AddMods...)
+	sort.Strings(mods)
+	if !reflect.DeepEqual(mods, []string{"Ammo", "GunsExtra"}) {
+		t.Errorf("AddMods = %v; want [Ammo GunsExtra]", plan.AddMods)
+	}
+	if plan.AddModSources["GunsExtra"] != "910" {
+		t.Errorf("GunsExtra source = %q; want the installed item 910", plan.AddModSources["GunsExtra"])
+	}
+	if !reflect.DeepEqual(plan.UnknownRequires, []string{"Nowhere"}) {
+		t.Errorf("UnknownRequires = %v; want [Nowhere]", plan.UnknownRequires)
+	}
+
+	plan, err = svc(requiresFake()).ResolveRequires(context.Background(), p, sm, []string{"GunsExtra"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(plan.AddWorkshopItems) != 0 || !reflect.DeepEqual(plan.AddMods, []string{"GunsExtra"}) {
+		t.Errorf("resolving GunsExtra only = items %v, mods %v; want just GunsExtra enabled", plan.AddWorkshopItems, plan.AddMods)
+	}
+}
+
+func clashFake() *steamtest.Fake {
+	return steamtest.New(
+		item("100", "Core Library", []string{"CoreLib"}, nil, nil, steam.FileTypeMod, false),
+		item("110", "Core Library (fixed)", []string{"CoreLib"}, nil, nil, steam.FileTypeMod, false),
+		item("120", "Core Bundle", []string{"CoreLib", "Extra"}, nil, nil, steam.FileTypeMod, false),
+		item("200", "Weapons", []string{"Weapons"}, nil, []string{"100", "110"}, steam.FileTypeMod, false),
+	)
+}
+
+func TestClashesAndPlanProvider(t *testing.T) {
+	s := svc(clashFake())
+	sm := domain.ServerMods{WorkshopItems: []string{"110", "100", "120"}, Mods: []string{`100\CoreLib`, "Extra"}}
+
+	clashes, err := s.Clashes(context.Background(), sm)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(clashes) != 1 || clashes[0].ModID != "CoreLib" || clashes[0].Pinned != "100" {
+		t.Fatalf("clashes = %+v; want CoreLib pinned to 100", clashes)
+	}
+	var ids []string
+	for _, it := range clashes[0].Items {
+		ids = append(ids, it.PublishedFileID)
+	}
+	if !reflect.DeepEqual(ids, []string{"110", "100", "120"}) {
+		t.Errorf("clash items = %v; want WorkshopItems order", ids)
+	}
+
+	plan, err := s.PlanProvider(context.Background(), sm, "CoreLib", "110", true)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plan.Drop, []string{"100"}) || !reflect.DeepEqual(plan.Kept, []string{"120"}) {
+		t.Errorf("Drop = %v, Kept = %v; want [100] and [120] (120 also provides Extra)", plan.Drop, plan.Kept)
+	}
+	out := plan.Apply(sm, true)
+	if !reflect.DeepEqual(out.Mods, []string{`110\CoreLib`, "Extra"}) {
+		t.Errorf("Mods = %v; want CoreLib pinned to 110", out.Mods)
+	}
+
+	if _, err := s.PlanProvider(context.Background(), sm, "Extra", "100", false); err == nil {
+		t.Error("pinning to an item that doesn't declare the mod should fail")
+	}
+	if _, err := s.PlanProvider(context.Background(), sm, "Weapons", "200", false); err == nil {
+		t.Error("pinning to an item that isn't installed should fail")
+	}
+}
+
+func TestResolveReportsClashes(t *testing.T) {
+	plan, err := svc(clashFake()).Resolve(context.Background(), []string{"200"}, domain.ServerMods{})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(plan.Clashes, map[string][]string{"CoreLib": {"100", "110"}}) {
+		t.Errorf("Clashes = %v; want CoreLib from 100 and 110", plan.Clashes)
+	}
+	if plan.AddModSources["CoreLib"] != "100" {
+		t.Errorf("CoreLib source = %q; want the first item reached", plan.AddModSources["CoreLib"])
+	}
+}
+
+func TestResolveRequiresWithoutSearch(t *testing.T) {
+	root := writeModInfos(t, map[string]string{"Guns": "require=\\Ammo,\\GunsExtra\n"})
+	p := store.Profile{Build: "b41", WorkshopContentPath: root}
+	sm := domain.ServerMods{Mods: []string{"Guns"}, WorkshopItems: []string{"910"}}
+	f := requiresFake()
+	f.QueryErr = steam.ErrSearchNeedsKey // the keyless client can't search
+
+	plan, err := svc(f).ResolveRequires(context.Background(), p, sm, nil)
+	if err != nil {
+		t.Fatalf("a failed search should not abort the plan: %v", err)
+	}
+	if !reflect.DeepEqual(plan.AddMods, []string{"GunsExtra"}) {
+		t.Errorf("AddMods = %v; want the installed item's GunsExtra still enabled", plan.AddMods)
+	}
+	if !reflect.DeepEqual(plan.UnknownRequires, []string{"Ammo"}) || !errors.Is(plan.SearchErr, steam.ErrSearchNeedsKey) {
+		t.Errorf("UnknownRequires = %v, SearchErr = %v; want [Ammo] and ErrSearchNeedsKey", plan.UnknownRequires, plan.SearchErr)
+	}
+}
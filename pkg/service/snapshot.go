@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/steam"
+)
+
+// WorkshopSnapshot fetches every Workshop item a config depends on - its
+// WorkshopItems and, transitively, their children and collection members -
+// into a Snapshot that validation, resolution, and load-order suggestion can
+// later run against without Steam. IDs Steam reports unavailable are recorded
+// with their result. A snapshot must be complete to be reproducible, so
+// unlike Validate this fails if Steam does not answer for some IDs.
+func (s *Services) WorkshopSnapshot(ctx context.Context, sm domain.ServerMods) (*steam.Snapshot, error) {
+	snap := &steam.Snapshot{Created: s.Now().UTC(), Items: []steam.WorkshopItem{}}
+	visited := map[string]bool{}
+	var frontier []string
+	enqueue := func(ids []string) {
+		for _, id := range ids {
+			if id != "" && !visited[id] {
+				visited[id] = true
+				frontier = append(frontier, id)
+			}
+		}
+	}
+	enqueue(sm.WorkshopItems)
+
+	for len(frontier) > 0 {
+		batch := frontier
+		frontier = nil
+		items, missing, err := s.Steam.GetDetails(ctx, batch)
+		if partial, perr := partialResult(err); perr != nil {
+			return nil, perr
+		} else if partial != nil {
+			return nil, fmt.Errorf("steam did not answer for %s: %w", strings.Join(partial.IDs, ", "), err)
+		}
+		for _, id := range missing {
+			if snap.Missing == nil {
+				snap.Missing = map[string]steam.Result{}
+			}
+			snap.Missing[id] = steam.MissingResult(s.Steam, id)
+		}
+		for _, it := range items {
+			it.Stale = false
+			snap.Items = append(snap.Items, it)
+			enqueue(it.GetChildIDs())
+		}
+	}
+
+	s.NameCreators(ctx, snap.Items)
+	for _, it := range snap.Items {
+		if it.CreatorName != "" {
+			if snap.Names == nil {
+				snap.Names = map[string]string{}
+			}
+			snap.Names[it.Creator] = it.CreatorName
+		}
+	}
+	return snap, nil
+}
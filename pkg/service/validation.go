@@ -2,29 +2,47 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"sort"
 	"strings"
 
 	"github.com/kldzj/pzmod/pkg/build"
 	"github.com/kldzj/pzmod/pkg/domain"
+	"github.com/kldzj/pzmod/pkg/modinfo"
 	"github.com/kldzj/pzmod/pkg/steam"
+	"github.com/kldzj/pzmod/pkg/store"
 )
 
 // Validate checks a ServerMods against the Steam Workshop and returns a report.
 // It is pure with respect to disk: it never writes, so dry-run validation simply
 // calls it on a projected ServerMods.
 func (s *Services) Validate(ctx context.Context, sm domain.ServerMods, b build.Build) (domain.Report, error) {
+	return s.validate(ctx, sm, b, nil, nil)
+}
+
+// validate is Validate, with local counting mod IDs installed outside the
+// Workshop as provided, and infos (the enabled mods' mod.info) checked
+// against the Workshop's dependency lists.
+func (s *Services) validate(ctx context.Context, sm domain.ServerMods, b build.Build, local map[string]bool, infos map[string]modinfo.ModInfo) (domain.Report, error) {
 	var report domain.Report
 
 	items, missing, err := s.Steam.GetDetails(ctx, sm.WorkshopItems)
+	partial, err := partialResult(err)
 	if err != nil {
 		return report, err
 	}
+	if partial != nil {
+		report.Unchecked = partial.IDs
+		for _, id := range partial.IDs {
+			report.Add(domain.Finding{Severity: domain.SeverityWarning, Code: domain.CodeUnchecked, Subject: id,
+				Message: fmt.Sprintf("workshop item %s could not be checked (%v)", id, partial.Errs[id]),
+				Hint:    "Steam may be down or rate limiting; validate again later"})
+		}
+	}
 
 	for _, id := range missing {
-		report.AddFinding(domain.SeverityError, domain.CodeDelisted, id,
-			"workshop item "+id+" could not be fetched (delisted, private, or removed)")
+		report.Add(unavailable(id, steam.MissingResult(s.Steam, id)))
 	}
 
 	declaredMods := map[string]bool{}
@@ -33,9 +51,8 @@ func (s *Services) Validate(ctx context.Context, sm domain.ServerMods, b build.B
 	installedItems := toSet(sm.WorkshopItems)
 
 	for _, item := range items {
-		if item.Banned {
-			report.AddFinding(domain.SeverityError, domain.CodeBanned, item.PublishedFileID,
-				title(item)+" is banned on the Workshop")
+		if f, ok := restricted(item); ok {
+			report.Add(f)
 		}
 		parsed := item.Parse()
 		if !item.IsCollection() && len(parsed.Mods) == 0 {
@@ -59,10 +76,104 @@ func (s *Services) Validate(ctx context.Context, sm domain.ServerMods, b build.B
 		}
 	}
 
+	for id := range local {
+		declaredMods[id] = true
+	}
+	checkEnabledMods(&report, sm, declaredMods, declaredBy)
+
+	// Missing dependencies: an item's required children that aren't installed.
+	children, err := s.appendMissingDeps(ctx, items, installedItems, &report)
+	if err != nil {
+		return report, err
+	}
+	checkRequires(&report, sm, infos, declaredBy, local, items, children)
+	checkUnneededDeps(&report, infos, items, children)
+
+	// Build compatibility warnings.
+	for _, f := range build.CompatWarnings(b, items) {
+		report.Add(f)
+	}
+
+	return report, nil
+}
+
+// ValidateProfile is Validate with the profile's build, plus the checks only
+// the mods' own mod.info files can answer: mods declared incompatible with one
+// another, and game version bounds that exclude the build. Mods in the
+// profile's local mods directory count as provided. Without a
+// WorkshopContentPath or LocalModsPath it is plain Validate.
+func (s *Services) ValidateProfile(ctx context.Context, p store.Profile, sm domain.ServerMods) (domain.Report, error) {
+	b := build.Parse(p.Build)
+	localMods, err := s.LocalMods(p)
+	if err != nil {
+		return domain.Report{}, err
+	}
+	local := map[string]bool{}
+	for _, mi := range localMods {
+		local[mi.ID] = true
+	}
+	infos := s.providerFor(p).Lookup(modIDsOf(sm.Mods))
+	report, err := s.validate(ctx, sm, b, local, infos)
+	if err != nil {
+		return report, err
+	}
+	for _, f := range incompatibilities(sm, infos) {
+		report.Add(f)
+	}
+	for _, f := range build.ModInfoWarnings(b, infos) {
+		report.Add(f)
+	}
+	return report, nil
+}
+
+// incompatibilities reports each pair of enabled mods where one's mod.info
+// lists the other as incompatible, once per pair. The other mod only has to be
+// enabled in sm; its content need not be on disk.
+func incompatibilities(sm domain.ServerMods, infos map[string]modinfo.ModInfo) []domain.Finding {
+	ids := make([]string, 0, len(infos))
+	for id := range infos {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+
+	var findings []domain.Finding
+	reported := map[[2]string]bool{}
+	for _, id := range ids {
+		for _, other := range infos[id].Incompatible {
+			if !sm.HasMod(other) || other == id {
+				continue
+			}
+			pair := [2]string{id, other}
+			if other < id {
+				pair = [2]string{other, id}
+			}
+			if reported[pair] {
+				continue
+			}
+			reported[pair] = true
+			findings = append(findings, domain.Finding{Severity: domain.SeverityError, Code: domain.CodeIncompatible, Subject: id,
+				Message: "mod " + id + " is incompatible with " + other + ", which is also enabled",
+				Hint:    "its " + infos[id].File() + " says so; disable one of them"})
+		}
+	}
+	return findings
+}
+
+// checkEnabledMods reports Mods= entries no checked item provides, and mod IDs
+// more than one installed item declares. It is shared by the Workshop and
+// on-disk validations, which differ only in where declarations come from.
+func checkEnabledMods(report *domain.Report, sm domain.ServerMods, declaredMods map[string]bool, declaredBy map[string][]string) {
 	// Mods enabled but not provided by any installed item (matched on logical ID).
+	// An unchecked item might provide them, so those are only advisory then.
 	for _, raw := range sm.Mods {
 		id := domain.ParseModRef(raw).ID
-		if id != "" && !declaredMods[id] {
+		if id == "" || declaredMods[id] {
+			continue
+		}
+		if report.Incomplete() {
+			report.AddFinding(domain.SeverityInfo, domain.CodeUnknownModID, raw,
+				"mod ID "+id+" is not provided by any checked workshop item")
+		} else {
 			report.AddFinding(domain.SeverityWarning, domain.CodeUnknownModID, raw,
 				"mod ID "+id+" is enabled but not provided by any workshop item")
 		}
@@ -88,21 +199,60 @@ func (s *Services) Validate(ctx context.Context, sm domain.ServerMods, b build.B
 			fmt.Sprintf("mod ID %s is declared by %d items (%s) - %s",
 				id, len(declaredBy[id]), strings.Join(declaredBy[id], ", "), pin))
 	}
+}
 
-	// Missing dependencies: an item's required children that aren't installed.
-	if err := s.appendMissingDeps(ctx, items, installedItems, &report); err != nil {
-		return report, err
+// unavailable explains why GetDetails reported id as missing. The hint differs
+// by cause: a deleted item needs replacing, a private one needs its author.
+func unavailable(id string, r steam.Result) domain.Finding {
+	f := domain.Finding{Severity: domain.SeverityError, Subject: id}
+	switch r {
+	case steam.ResultFileNotFound:
+		f.Code = domain.CodeNotFound
+		f.Message = "workshop item " + id + " no longer exists"
+		f.Hint = "remove it, or replace it with a maintained alternative"
+	case steam.ResultAccessDenied:
+		f.Code = domain.CodePrivate
+		f.Message = "workshop item " + id + " is private or hidden"
+		f.Hint = "ask the author to make it public or unlisted; the server cannot download it"
+	case steam.ResultNotInSnapshot:
+		f.Code = domain.CodeNotInSnapshot
+		f.Message = "workshop item " + id + " is not in the Workshop snapshot"
+		f.Hint = "the snapshot is older than the config; refresh it with `pzmod workshop snapshot`"
+	default:
+		f.Code = domain.CodeDelisted
+		f.Message = "workshop item " + id + " could not be fetched (delisted, private, or removed)"
+		if r != steam.ResultUnknown {
+			f.Message = fmt.Sprintf("workshop item %s could not be fetched (Steam result %d)", id, r)
+		}
+		f.Hint = "check its Workshop page; remove or replace it if it is gone"
 	}
+	return f
+}
 
-	// Build compatibility warnings.
-	for _, f := range build.CompatWarnings(b, items) {
-		report.Add(f)
+// restricted reports an item that fetched fine but that a dedicated server,
+// which downloads anonymously, may not be able to get.
+func restricted(item steam.WorkshopItem) (domain.Finding, bool) {
+	id := item.PublishedFileID
+	switch {
+	case item.Visibility == steam.VisibilityFriendsOnly:
+		return domain.Finding{Severity: domain.SeverityError, Code: domain.CodeFriendsOnly, Subject: id,
+			Message: title(item) + " is friends-only",
+			Hint:    "ask the author to make it public or unlisted; the server cannot download it"}, true
+	case item.Visibility == steam.VisibilityPrivate:
+		return domain.Finding{Severity: domain.SeverityError, Code: domain.CodePrivate, Subject: id,
+			Message: title(item) + " is private",
+			Hint:    "make it public or unlisted (or ask its author to); the server cannot download it"}, true
+	case item.Banned:
+		return domain.Finding{Severity: domain.SeverityWarning, Code: domain.CodeBanned, Subject: id,
+			Message: title(item) + " is banned on the Workshop but still downloadable",
+			Hint:    "it may vanish without notice; look for a replacement"}, true
 	}
-
-	return report, nil
+	return domain.Finding{}, false
 }
 
-func (s *Services) appendMissingDeps(ctx context.Context, items []steam.WorkshopItem, installed map[string]bool, report *domain.Report) error {
+// appendMissingDeps reports items' required children that aren't installed,
+// and returns the children it fetched, by ID.
+func (s *Services) appendMissingDeps(ctx context.Context, items []steam.WorkshopItem, installed map[string]bool, report *domain.Report) (map[string]steam.WorkshopItem, error) {
 	var childIDs []string
 	seen := map[string]bool{}
 	for _, item := range items {
@@ -114,12 +264,13 @@ func (s *Services) appendMissingDeps(ctx context.Context, items []steam.Workshop
 		}
 	}
 	if len(childIDs) == 0 {
-		return nil
+		return nil, nil
 	}
 
+	// Children are fetched for their titles and mods; any that fail keep their IDs.
 	children, _, err := s.Steam.GetDetails(ctx, childIDs)
-	if err != nil {
-		return err
+	if _, err := partialResult(err); err != nil {
+		return nil, err
 	}
 	childByID := map[string]steam.WorkshopItem{}
 	for _, c := range children {
@@ -139,7 +290,17 @@ func (s *Services) appendMissingDeps(ctx context.Context, items []steam.Workshop
 				"missing dependency "+depName+" ("+c+") required by "+title(item))
 		}
 	}
-	return nil
+	return childByID, nil
+}
+
+// partialResult splits a GetDetails error into a *steam.PartialError, whose
+// results are usable, and any other error, which is fatal.
+func partialResult(err error) (*steam.PartialError, error) {
+	var partial *steam.PartialError
+	if errors.As(err, &partial) {
+		return partial, nil
+	}
+	return nil, err
 }
 
 func title(item steam.WorkshopItem) string {
@@ -7,12 +7,13 @@ import (
 	"context"
 	"encoding/json"
 	"errors"
-	"fmt"
+	"io"
 	"net/http"
 	"net/url"
+	"strings"
+	"sync"
 	"time"
 
-	"github.com/hashicorp/go-retryablehttp"
 	"golang.org/x/time/rate"
 )
 
@@ -21,14 +22,43 @@ const AppID = 108600
 
 const defaultBaseURL = "https://api.steampowered.com"
 
-// ErrInvalidAPIKey is returned when the Steam API rejects the key (HTTP 401).
-var ErrInvalidAPIKey = errors.New("steam api key is invalid")
+var (
+	// ErrInvalidAPIKey is returned when the Steam API rejects the key (HTTP 401).
+	ErrInvalidAPIKey = errors.New("steam api key is invalid")
+	// ErrAccessDenied is returned when the keyless endpoints refuse a request
+	// (HTTP 401/403): there is no key to blame, so Steam is blocking the caller.
+	ErrAccessDenied = errors.New("steam api refused the keyless request (HTTP 403)")
+	// ErrOffline is returned in offline mode for anything the cache can't answer.
+	ErrOffline = errors.New("not available offline")
+	// ErrSearchNeedsKey is returned by PublicClient.QueryFiles: the keyless
+	// endpoints cannot search the Workshop.
+	ErrSearchNeedsKey = errors.New("search requires a Steam Web API key")
+)
 
 // API is the consumer-facing interface the services depend on, so they can be
 // tested against a fake (see steamtest).
 type API interface {
 	GetDetails(ctx context.Context, ids []string) (items []WorkshopItem, missing []string, err error)
 	QueryFiles(ctx context.Context, q Query) (Page, error)
+	UserFiles(ctx context.Context, steamID string, page, perPage int) (Page, error)
+	PlayerNames(ctx context.Context, steamIDs []string) (map[string]string, error)
+}
+
+// MissingReasons is implemented by APIs that remember why GetDetails reported
+// an ID as missing.
+type MissingReasons interface {
+	// MissingResult returns the Steam result last reported for a missing id,
+	// or ResultUnknown.
+	MissingResult(id string) Result
+}
+
+// MissingResult returns why api last reported id as missing, or ResultUnknown
+// when api doesn't say.
+func MissingResult(api API, id string) Result {
+	if mr, ok := api.(MissingReasons); ok {
+		return mr.MissingResult(id)
+	}
+	return ResultUnknown
 }
 
 // Client talks to the Steam Web API. Construct it with New.
@@ -37,10 +67,28 @@ type Client struct {
 	appID     int
 	http      *http.Client
 	cache     Cache
+	names     *memNames
 	chunkSize int
+	workers   int
 	baseURL   string
 	limiter   *rate.Limiter
 	now       func() time.Time
+	offline   bool
+	tracer    Tracer
+
+	retries      int
+	maxRetryWait time.Duration
+	sleep        func(ctx context.Context, d time.Duration) error
+	breaker      breaker
+
+	// details fetches one batch, unavailable items included: IPublishedFileService
+	// for keyed clients, ISteamRemoteStorage for a PublicClient.
+	details func(ctx context.Context, ids []string) ([]WorkshopItem, error)
+
+	resultsMu sync.Mutex
+	results   map[string]Result // why IDs were last reported missing
+
+	revalidating sync.WaitGroup
 }
 
 // Option configures a Client.
@@ -67,6 +115,44 @@ func WithChunkSize(n int) Option {
 	}
 }
 
+// WithConcurrency sets how many GetDetails batches may be in flight at once
+// (default 4). Requests still pass through the rate limiter.
+func WithConcurrency(n int) Option {
+	return func(c *Client) {
+		if n > 0 {
+			c.workers = n
+		}
+	}
+}
+
+// WithOffline makes the client answer GetDetails purely from its cache (stale
+// entries included) and never touch the network.
+func WithOffline() Option { return func(c *Client) { c.offline = true } }
+
+// WithRetries sets how many times a request that got no response, a 429, or a
+// 5xx is retried (default 3), and the longest Retry-After the client will
+// wait out (default a minute); Steam asking for longer fails the request.
+func WithRetries(n int, maxWait time.Duration) Option {
+	return func(c *Client) {
+		c.retries = max(n, 0)
+		if maxWait > 0 {
+			c.maxRetryWait = maxWait
+		}
+	}
+}
+
+// WithCircuitBreaker sets how many consecutive failed requests open the
+// circuit breaker (default 5; 0 disables it) and how long it then rejects
+// requests before trying Steam again (default 30s).
+func WithCircuitBreaker(failures int, cooldown time.Duration) Option {
+	return func(c *Client) {
+		c.breaker.threshold = max(failures, 0)
+		if cooldown > 0 {
+			c.breaker.cooldown = cooldown
+		}
+	}
+}
+
 // WithRateLimiter overrides the request rate limiter (nil disables limiting).
 func WithRateLimiter(l *rate.Limiter) Option { return func(c *Client) { c.limiter = l } }
 
@@ -76,50 +162,121 @@ func New(apiKey string, opts ...Option) *Client {
 		apiKey:    apiKey,
 		appID:     AppID,
 		chunkSize: 10,
+		workers:   4,
 		baseURL:   defaultBaseURL,
 		limiter:   rate.NewLimiter(rate.Every(200*time.Millisecond), 5),
 		now:       time.Now,
+
+		retries:      DefaultRetries,
+		maxRetryWait: DefaultMaxRetryWait,
+		sleep:        sleepCtx,
+		breaker:      breaker{threshold: DefaultBreakerFailures, cooldown: DefaultBreakerCooldown},
 	}
 	for _, o := range opts {
 		o(c)
 	}
 	if c.http == nil {
-		rc := retryablehttp.NewClient()
-		rc.Logger = nil
-		rc.RetryMax = 3
-		c.http = rc.StandardClient()
+		c.http = &http.Client{Timeout: DefaultTimeout}
+	}
+	if c.tracer != nil {
+		c.traceHTTP()
 	}
+	c.breaker.now = c.now
 	if c.cache == nil {
 		c.cache = NewMemCache(5*time.Minute, c.now)
 	}
+	c.names = &memNames{now: c.now, m: map[string]cachedName{}}
+	c.details = c.getDetailsChunk
+	c.results = map[string]Result{}
 	return c
 }
 
+// NewAPI returns a Client for apiKey, or a keyless PublicClient when apiKey
+// is empty.
+func NewAPI(apiKey string, opts ...Option) API {
+	if apiKey == "" {
+		return NewPublic(opts...)
+	}
+	return New(apiKey, opts...)
+}
+
 // doGet performs a rate-limited GET against the API and decodes JSON into out.
 func (c *Client) doGet(ctx context.Context, endpoint string, query url.Values, out any) error {
+	return c.do(ctx, http.MethodGet, c.baseURL+endpoint+"?"+query.Encode(), nil, out)
+}
+
+// doPost performs a rate-limited form POST against the API and decodes JSON
+// into out.
+func (c *Client) doPost(ctx context.Context, endpoint string, form url.Values, out any) error {
+	return c.do(ctx, http.MethodPost, c.baseURL+endpoint, form, out)
+}
+
+// do sends a request through the circuit breaker, retrying temporary failures
+// with backoff (or as long as Retry-After says, up to maxRetryWait).
+func (c *Client) do(ctx context.Context, method, u string, form url.Values, out any) error {
+	probe, err := c.breaker.allow()
+	if err != nil {
+		return err
+	}
+	if probe {
+		defer c.breaker.endProbe()
+	}
+	for attempt := 0; ; attempt++ {
+		err := c.doOnce(ctx, method, u, form, out)
+		if ctx.Err() != nil {
+			return err
+		}
+		if err == nil || !temporary(err) {
+			c.breaker.record(false)
+			return err
+		}
+		wait := retryWait(err, attempt)
+		if attempt >= c.retries || wait > c.maxRetryWait {
+			c.breaker.record(true)
+			return err
+		}
+		if serr := c.sleep(ctx, wait); serr != nil {
+			return serr
+		}
+	}
+}
+
+func (c *Client) doOnce(ctx context.Context, method, u string, form url.Values, out any) error {
 	if c.limiter != nil {
 		if err := c.limiter.Wait(ctx); err != nil {
 			return err
 		}
 	}
 
-	u := c.baseURL + endpoint + "?" + query.Encode()
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, u, nil)
+	var body io.Reader
+	if form != nil {
+		body = strings.NewReader(form.Encode())
+	}
+	req, err := http.NewRequestWithContext(ctx, method, u, body)
 	if err != nil {
 		return err
 	}
+	if form != nil {
+		req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	}
 
 	resp, err := c.http.Do(req)
 	if err != nil {
-		return err
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return &transportError{err}
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusOK {
 		if resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden {
+			if c.apiKey == "" {
+				return ErrAccessDenied
+			}
 			return ErrInvalidAPIKey
 		}
-		return fmt.Errorf("steam api request failed with status %s", resp.Status)
+		return &statusError{code: resp.StatusCode, status: resp.Status, retryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), c.now())}
 	}
 
 	return json.NewDecoder(resp.Body).Decode(out)
@@ -8,6 +8,7 @@ import (
 	"net/http/httptest"
 	"net/url"
 	"strings"
+	"sync/atomic"
 	"testing"
 	"time"
 )
@@ -185,6 +186,67 @@ func TestQueryFilesTextSearch(t *testing.T) {
 	}
 }
 
+func TestQueryFilesFilters(t *testing.T) {
+	var captured url.Values
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		captured = r.URL.Query()
+		w.Write([]byte(`{"response":{"total":0,"publishedfiledetails":[]}}`))
+	})
+	since := time.Unix(1700000000, 0)
+	_, err := client.QueryFiles(context.Background(), Query{
+		Sort:         SortSubscribed,
+		Tags:         []string{"Build 41", "Build 42"},
+		MatchAnyTag:  true,
+		ExcludedTags: []string{"Map"},
+		UpdatedAfter: since,
+		Page:         3,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"query_type":                          "9",
+		"match_all_tags":                      "false",
+		"excludedtags[0]":                     "Map",
+		"date_range_updated[timestamp_start]": "1700000000",
+		"page":                                "3",
+		"cursor":                              "",
+		"date_range_created[timestamp_start]": "",
+	}
+	for k, v := range want {
+		if got := captured.Get(k); got != v {
+			t.Errorf("%s = %q; want %q", k, got, v)
+		}
+	}
+
+	if _, err := ParseSort("Updated"); err != nil {
+		t.Errorf("ParseSort(Updated) = %v", err)
+	}
+	if _, err := ParseSort("best"); err == nil {
+		t.Error("ParseSort(best) should fail")
+	}
+}
+
+func TestUserFiles(t *testing.T) {
+	var captured url.Values
+	var path string
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		captured, path = r.URL.Query(), r.URL.Path
+		w.Write([]byte(`{"response":{"total":3,"publishedfiledetails":[{"result":1,"publishedfileid":"5"}]}}`))
+	})
+	page, err := client.UserFiles(context.Background(), "76561198000000001", 2, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if path != "/IPublishedFileService/GetUserFiles/v1/" || captured.Get("steamid") != "76561198000000001" ||
+		captured.Get("page") != "2" || captured.Get("appid") != "108600" {
+		t.Errorf("request = %s %v", path, captured)
+	}
+	if page.Total != 3 || len(page.Items) != 1 {
+		t.Errorf("page = %+v", page)
+	}
+}
+
 func TestQueryFilesBrowseDefault(t *testing.T) {
 	var captured url.Values
 	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
@@ -201,3 +263,71 @@ func TestQueryFilesBrowseDefault(t *testing.T) {
 		t.Errorf("search_text should be absent for browse")
 	}
 }
+
+func TestGetDetailsConcurrentOrder(t *testing.T) {
+	var inFlight, peak atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := inFlight.Add(1)
+		defer inFlight.Add(-1)
+		for {
+			p := peak.Load()
+			if n <= p || peak.CompareAndSwap(p, n) {
+				break
+			}
+		}
+		ids := requestedIDs(r.URL.Query())
+		// The first batch answers last, so arrival order differs from input order.
+		delay := 20 * time.Millisecond
+		if ids[0] == "0" {
+			delay = 60 * time.Millisecond
+		}
+		time.Sleep(delay)
+		var parts []string
+		for _, id := range ids {
+			result := 1
+			if id == "3" {
+				result = 9
+			}
+			parts = append(parts, fmt.Sprintf(`{"result":%d,"publishedfileid":%q}`, result, id))
+		}
+		fmt.Fprintf(w, `{"response":{"publishedfiledetails":[%s]}}`, strings.Join(parts, ","))
+	}, WithChunkSize(2), WithConcurrency(3))
+
+	ids := []string{"0", "1", "2", "3", "4", "5", "1"}
+	items, missing, err := client.GetDetails(context.Background(), ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for _, it := range items {
+		got = append(got, it.PublishedFileID)
+	}
+	if strings.Join(got, ",") != "0,1,2,4,5" {
+		t.Errorf("items = %v; want input order without duplicates", got)
+	}
+	if len(missing) != 1 || missing[0] != "3" {
+		t.Errorf("missing = %v; want [3]", missing)
+	}
+	if p := peak.Load(); p < 2 || p > 3 {
+		t.Errorf("peak concurrency = %d; want 2..3", p)
+	}
+}
+
+func TestGetDetailsCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	var requests atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			cancel()
+		}
+		<-r.Context().Done()
+	}, WithChunkSize(1), WithConcurrency(2))
+
+	_, _, err := client.GetDetails(ctx, []string{"1", "2", "3", "4", "5", "6"})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("err = %v; want context.Canceled", err)
+	}
+	if n := requests.Load(); n > 2 {
+		t.Errorf("requests = %d; want no new batches after cancel", n)
+	}
+}
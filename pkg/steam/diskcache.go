@@ -0,0 +1,243 @@
+package steam
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// Disk cache defaults. Workshop metadata changes rarely, so entries are fresh
+// for hours and then served stale (while a refetch runs) for a week.
+const (
+	DefaultDiskCacheTTL      = 6 * time.Hour
+	DefaultDiskCacheStaleFor = 7 * 24 * time.Hour
+	DefaultNegativeTTL       = time.Hour
+)
+
+// CacheEntry is a StaleCache hit.
+type CacheEntry struct {
+	Item    WorkshopItem
+	Missing bool   // the API reported the ID as unavailable
+	Result  Result // why, when Missing
+	Stale   bool   // past its TTL; usable, but should be revalidated
+}
+
+// StaleCache is a Cache that also remembers missing IDs and hands out expired
+// entries, enabling negative caching and stale-while-revalidate in the Client.
+type StaleCache interface {
+	Cache
+	// Lookup returns a fresh or stale entry; expired-beyond-stale entries miss.
+	Lookup(id string) (CacheEntry, bool)
+	// SetMissing records that the API reported id as unavailable, and why.
+	SetMissing(id string, result Result)
+}
+
+// DiskCacheConfig tunes NewDiskCache. Zero fields take the defaults.
+type DiskCacheConfig struct {
+	TTL         time.Duration // how long an entry is fresh
+	StaleFor    time.Duration // how long after that it may be served stale
+	NegativeTTL time.Duration // how long a missing ID is remembered
+	Now         func() time.Time
+}
+
+// diskRecord is one cached ID as stored on disk.
+type diskRecord struct {
+	FetchedAt time.Time     `json:"fetched_at"`
+	Missing   bool          `json:"missing,omitempty"`
+	Result    Result        `json:"result,omitempty"`
+	Item      *WorkshopItem `json:"item,omitempty"`
+}
+
+// DiskCache is a StaleCache persisted as one JSON file per Workshop ID, so
+// repeated CLI invocations share what earlier ones fetched.
+type DiskCache struct {
+	mu  sync.Mutex
+	dir string
+	cfg DiskCacheConfig
+}
+
+// NewDiskCache returns a disk cache rooted at dir (created on first write).
+func NewDiskCache(dir string, cfg DiskCacheConfig) *DiskCache {
+	if cfg.TTL <= 0 {
+		cfg.TTL = DefaultDiskCacheTTL
+	}
+	if cfg.StaleFor < 0 {
+		cfg.StaleFor = 0
+	} else if cfg.StaleFor == 0 {
+		cfg.StaleFor = DefaultDiskCacheStaleFor
+	}
+	if cfg.NegativeTTL <= 0 {
+		cfg.NegativeTTL = DefaultNegativeTTL
+	}
+	if cfg.Now == nil {
+		cfg.Now = time.Now
+	}
+	return &DiskCache{dir: dir, cfg: cfg}
+}
+
+// path returns the file for id, or "" when id is not a plain Workshop ID (so a
+// crafted ID can never escape the cache directory).
+func (c *DiskCache) path(id string) string {
+	if id == "" {
+		return ""
+	}
+	for _, r := range id {
+		if r < '0' || r > '9' {
+			return ""
+		}
+	}
+	return filepath.Join(c.dir, id+".json")
+}
+
+func (c *DiskCache) read(id string) (diskRecord, bool) {
+	p := c.path(id)
+	if p == "" {
+		return diskRecord{}, false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return diskRecord{}, false
+	}
+	var rec diskRecord
+	if json.Unmarshal(data, &rec) != nil || (!rec.Missing && rec.Item == nil) {
+		return diskRecord{}, false
+	}
+	return rec, true
+}
+
+func (c *DiskCache) write(id string, rec diskRecord) {
+	p := c.path(id)
+	if p == "" {
+		return
+	}
+	data, err := json.Marshal(rec)
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	// The cache is best effort: a failed write only costs a refetch later.
+	if os.MkdirAll(c.dir, 0755) != nil {
+		return
+	}
+	tmp := p + ".tmp"
+	if os.WriteFile(tmp, data, 0644) != nil {
+		return
+	}
+	if os.Rename(tmp, p) != nil {
+		_ = os.Remove(tmp)
+	}
+}
+
+// Lookup implements StaleCache.
+func (c *DiskCache) Lookup(id string) (CacheEntry, bool) {
+	rec, ok := c.read(id)
+	if !ok {
+		return CacheEntry{}, false
+	}
+	age := c.cfg.Now().Sub(rec.FetchedAt)
+	if rec.Missing {
+		if age > c.cfg.NegativeTTL {
+			return CacheEntry{}, false
+		}
+		return CacheEntry{Missing: true, Result: rec.Result}, true
+	}
+	if age > c.cfg.TTL+c.cfg.StaleFor {
+		return CacheEntry{}, false
+	}
+	return CacheEntry{Item: *rec.Item, Stale: age > c.cfg.TTL}, true
+}
+
+// Get implements Cache: only fresh, available items hit.
+func (c *DiskCache) Get(id string) (WorkshopItem, bool) {
+	e, ok := c.Lookup(id)
+	if !ok || e.Missing || e.Stale {
+		return WorkshopItem{}, false
+	}
+	return e.Item, true
+}
+
+// Set implements Cache.
+func (c *DiskCache) Set(id string, item WorkshopItem) {
+	item.Stale = false
+	c.write(id, diskRecord{FetchedAt: c.cfg.Now(), Item: &item})
+}
+
+// SetMissing implements StaleCache.
+func (c *DiskCache) SetMissing(id string, result Result) {
+	c.write(id, diskRecord{FetchedAt: c.cfg.Now(), Missing: true, Result: result})
+}
+
+// Delete implements Cache.
+func (c *DiskCache) Delete(id string) {
+	if p := c.path(id); p != "" {
+		c.mu.Lock()
+		defer c.mu.Unlock()
+		_ = os.Remove(p)
+	}
+}
+
+// namePath returns the file for a persona name, under players/.
+func (c *DiskCache) namePath(steamID string) string {
+	if p := c.path(steamID); p != "" {
+		return filepath.Join(c.dir, "players", filepath.Base(p))
+	}
+	return ""
+}
+
+// Name implements NameCache. Names are reused for DefaultNameTTL.
+func (c *DiskCache) Name(steamID string) (string, bool) {
+	p := c.namePath(steamID)
+	if p == "" {
+		return "", false
+	}
+	data, err := os.ReadFile(p)
+	if err != nil {
+		return "", false
+	}
+	var rec struct {
+		FetchedAt time.Time `json:"fetched_at"`
+		Name      string    `json:"name"`
+	}
+	if json.Unmarshal(data, &rec) != nil || c.cfg.Now().Sub(rec.FetchedAt) > DefaultNameTTL {
+		return "", false
+	}
+	return rec.Name, true
+}
+
+// SetName implements NameCache.
+func (c *DiskCache) SetName(steamID, name string) {
+	p := c.namePath(steamID)
+	if p == "" {
+		return
+	}
+	data, err := json.Marshal(map[string]any{"fetched_at": c.cfg.Now(), "name": name})
+	if err != nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if os.MkdirAll(filepath.Dir(p), 0755) != nil {
+		return
+	}
+	if os.WriteFile(p+".tmp", data, 0644) != nil || os.Rename(p+".tmp", p) != nil {
+		_ = os.Remove(p + ".tmp")
+	}
+}
+
+// Clear implements Cache.
+func (c *DiskCache) Clear() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	entries, err := os.ReadDir(c.dir)
+	if err != nil {
+		return
+	}
+	for _, e := range entries {
+		if filepath.Ext(e.Name()) == ".json" {
+			_ = os.Remove(filepath.Join(c.dir, e.Name()))
+		}
+	}
+}
@@ -0,0 +1,143 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestDiskCacheLookup(t *testing.T) {
+	cur := time.Unix(1000, 0)
+	dir := t.TempDir()
+	c := NewDiskCache(dir, DiskCacheConfig{TTL: time.Hour, StaleFor: time.Hour, NegativeTTL: time.Minute, Now: func() time.Time { return cur }})
+
+	c.Set("1", WorkshopItem{PublishedFileID: "1", Title: "One"})
+	c.SetMissing("2", ResultAccessDenied)
+	if e, ok := c.Lookup("1"); !ok || e.Stale || e.Item.Title != "One" {
+		t.Fatalf("fresh lookup = %+v, %v", e, ok)
+	}
+	if e, ok := c.Lookup("2"); !ok || !e.Missing || e.Result != ResultAccessDenied {
+		t.Fatalf("negative lookup = %+v, %v", e, ok)
+	}
+
+	// A second cache over the same dir (a later process) sees the entries.
+	if _, ok := NewDiskCache(dir, DiskCacheConfig{Now: func() time.Time { return cur }}).Get("1"); !ok {
+		t.Error("entry not persisted across cache instances")
+	}
+
+	cur = cur.Add(90 * time.Minute)
+	if e, ok := c.Lookup("1"); !ok || !e.Stale {
+		t.Errorf("past TTL lookup = %+v, %v; want stale hit", e, ok)
+	}
+	if _, ok := c.Get("1"); ok {
+		t.Error("Get served a stale entry")
+	}
+	if _, ok := c.Lookup("2"); ok {
+		t.Error("negative entry outlived NegativeTTL")
+	}
+
+	cur = cur.Add(time.Hour)
+	if _, ok := c.Lookup("1"); ok {
+		t.Error("entry served beyond its stale window")
+	}
+
+	// Non-numeric IDs are never turned into paths.
+	c.Set("../evil", WorkshopItem{PublishedFileID: "../evil"})
+	if _, err := os.Stat(filepath.Join(filepath.Dir(dir), "evil.json")); !os.IsNotExist(err) {
+		t.Error("crafted ID escaped the cache dir")
+	}
+}
+
+func TestGetDetailsNegativeCache(t *testing.T) {
+	var requests atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"result":9,"publishedfileid":"7"}]}}`))
+	}, WithCache(NewDiskCache(t.TempDir(), DiskCacheConfig{})))
+
+	for i := 0; i < 2; i++ {
+		_, missing, err := client.GetDetails(context.Background(), []string{"7"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if len(missing) != 1 || missing[0] != "7" {
+			t.Fatalf("missing = %v; want [7]", missing)
+		}
+		if r := client.MissingResult("7"); r != ResultFileNotFound {
+			t.Fatalf("MissingResult = %d; want file not found", r)
+		}
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("requests = %d; want 1 (missing ID cached)", n)
+	}
+}
+
+func TestGetDetailsStaleWhileRevalidate(t *testing.T) {
+	var requests atomic.Int32
+	cur := time.Unix(1000, 0)
+	cache := NewDiskCache(t.TempDir(), DiskCacheConfig{TTL: time.Hour, Now: func() time.Time { return cur }})
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		n := requests.Add(1)
+		title := "old"
+		if n > 1 {
+			title = "new"
+		}
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"result":1,"publishedfileid":"5","title":"` + title + `"}]}}`))
+	}, WithCache(cache))
+
+	if _, _, err := client.GetDetails(context.Background(), []string{"5"}); err != nil {
+		t.Fatal(err)
+	}
+	cur = cur.Add(2 * time.Hour)
+
+	items, _, err := client.GetDetails(context.Background(), []string{"5"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Title != "old" || !items[0].Stale {
+		t.Fatalf("items = %+v; want the stale 'old' entry", items)
+	}
+	client.WaitRevalidation()
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d; want 2 (background revalidation)", n)
+	}
+	if it, ok := cache.Get("5"); !ok || it.Title != "new" {
+		t.Errorf("cache after revalidation = %+v, %v; want fresh 'new'", it, ok)
+	}
+}
+
+func TestGetDetailsOffline(t *testing.T) {
+	dir := t.TempDir()
+	cur := time.Unix(1000, 0)
+	cache := NewDiskCache(dir, DiskCacheConfig{TTL: time.Hour, Now: func() time.Time { return cur }})
+	cache.Set("1", WorkshopItem{PublishedFileID: "1", Title: "cached"})
+	cur = cur.Add(2 * time.Hour)
+
+	var requests atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+	}, WithCache(cache), WithOffline())
+
+	items, _, err := client.GetDetails(context.Background(), []string{"1"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || !items[0].Stale {
+		t.Errorf("items = %+v; want the stale cached entry", items)
+	}
+	if _, _, err := client.GetDetails(context.Background(), []string{"1", "2"}); !errors.Is(err, ErrOffline) {
+		t.Errorf("uncached ID err = %v; want ErrOffline", err)
+	}
+	if _, err := client.QueryFiles(context.Background(), Query{SearchText: "x"}); !errors.Is(err, ErrOffline) {
+		t.Errorf("search err = %v; want ErrOffline", err)
+	}
+	client.WaitRevalidation()
+	if n := requests.Load(); n != 0 {
+		t.Errorf("requests = %d; want none offline", n)
+	}
+}
@@ -2,7 +2,12 @@ package steam
 
 import (
 	"context"
+	"errors"
+	"fmt"
 	"strconv"
+	"strings"
+	"sync"
+	"time"
 )
 
 type detailsResponse struct {
@@ -12,58 +17,243 @@ type detailsResponse struct {
 }
 
 // GetDetails fetches Workshop items by ID, serving cached entries where
-// possible and batching the rest. Items the API reports as unavailable
-// (result != 1) are returned in missing rather than items.
+// possible and batching the rest, with up to the configured number of batches
+// in flight at once. Items the API reports as unavailable (result != 1) are
+// returned in missing rather than items; MissingResult says why. Both follow
+// the order of ids, with duplicates dropped, however the requests interleave.
+//
+// With a StaleCache, missing IDs are remembered too, and entries past their
+// TTL are returned at once (marked Stale) while a background fetch refreshes
+// them. Offline, only the cache is consulted and uncached IDs are an error.
+//
+// If some batches fail while others succeed, the error is a *PartialError
+// naming the IDs that could not be fetched, returned with everything else.
 func (c *Client) GetDetails(ctx context.Context, ids []string) (items []WorkshopItem, missing []string, err error) {
-	var toFetch []string
+	stale, _ := c.cache.(StaleCache)
+	var order, toFetch, revalidate, uncached []string
+	seen := map[string]bool{}
+	found := map[string]WorkshopItem{}
+	gone := map[string]bool{}
 	for _, id := range ids {
-		if id == "" {
+		if id == "" || seen[id] {
 			continue
 		}
-		if item, ok := c.cache.Get(id); ok {
-			items = append(items, item)
+		seen[id] = true
+		order = append(order, id)
+		if stale != nil {
+			if e, ok := stale.Lookup(id); ok {
+				switch {
+				case e.Missing:
+					c.traceCache(id, "negative")
+					gone[id] = true
+					c.setMissingResult(id, e.Result)
+				default:
+					e.Item.Stale = e.Stale
+					found[id] = e.Item
+					if e.Stale {
+						c.traceCache(id, "stale")
+						revalidate = append(revalidate, id)
+					} else {
+						c.traceCache(id, "hit")
+					}
+				}
+				continue
+			}
+		} else if item, ok := c.cache.Get(id); ok {
+			c.traceCache(id, "hit")
+			found[id] = item
+			continue
+		}
+		c.traceCache(id, "miss")
+		if c.offline {
+			uncached = append(uncached, id)
 			continue
 		}
 		toFetch = append(toFetch, id)
 	}
+	if len(uncached) > 0 {
+		return nil, nil, fmt.Errorf("%d item(s) not in the Workshop cache (%s): %w", len(uncached), strings.Join(uncached, ", "), ErrOffline)
+	}
 
-	for _, batch := range chunk(toFetch, c.chunkSize) {
-		fetched, miss, err := c.getDetailsChunk(ctx, batch)
-		if err != nil {
-			return nil, nil, err
-		}
-		missing = append(missing, miss...)
-		for _, item := range fetched {
-			c.cache.Set(item.PublishedFileID, item)
+	fetched, miss, failed, err := c.fetchAll(ctx, toFetch)
+	if err != nil {
+		return nil, nil, err
+	}
+	for _, item := range fetched {
+		found[item.PublishedFileID] = item
+	}
+	for _, id := range miss {
+		gone[id] = true
+	}
+	var partial *PartialError
+	for _, id := range order {
+		if item, ok := found[id]; ok {
 			items = append(items, item)
+		} else if gone[id] {
+			missing = append(missing, id)
+		} else if _, ok := failed[id]; ok {
+			if partial == nil {
+				partial = &PartialError{Errs: failed}
+			}
+			partial.IDs = append(partial.IDs, id)
 		}
 	}
 
+	if len(revalidate) > 0 && !c.offline {
+		c.revalidate(ctx, revalidate)
+	}
+	if partial != nil {
+		if len(items)+len(missing) == 0 {
+			// Nothing to show for it: a plain failure, not a partial one.
+			return nil, nil, failed[partial.IDs[0]]
+		}
+		return items, missing, partial
+	}
 	return items, missing, nil
 }
 
-func (c *Client) getDetailsChunk(ctx context.Context, ids []string) (items []WorkshopItem, missing []string, err error) {
-	q := c.baseQuery()
-	q.Set("includechildren", "true")
-	for i, id := range ids {
-		q.Set("publishedfileids["+strconv.Itoa(i)+"]", id)
+// fetchAll fetches ids in chunks on up to c.workers goroutines, all sharing
+// the rate limiter. Results are concatenated in chunk order. A chunk that
+// fails on its own (Steam erroring or unreachable) is recorded in failed and
+// the rest carry on; an invalid key or a cancelled ctx stops them all and is
+// returned as err.
+func (c *Client) fetchAll(ctx context.Context, ids []string) (items []WorkshopItem, missing []string, failed map[string]error, err error) {
+	batches := chunk(ids, c.chunkSize)
+	if len(batches) == 0 {
+		return nil, nil, nil, nil
 	}
+	type result struct {
+		items   []WorkshopItem
+		missing []string
+		err     error
+	}
+	results := make([]result, len(batches))
 
-	var resp detailsResponse
-	if err := c.doGet(ctx, "/IPublishedFileService/GetDetails/v1/", q, &resp); err != nil {
-		return nil, nil, err
+	parent := ctx
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	var (
+		wg       sync.WaitGroup
+		once     sync.Once
+		firstErr error
+	)
+	next := make(chan int)
+	for range min(c.workers, len(batches)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range next {
+				items, missing, err := c.fetchChunk(ctx, batches[i])
+				if err != nil && (errors.Is(err, ErrInvalidAPIKey) || errors.Is(err, ErrAccessDenied) || parent.Err() != nil) {
+					once.Do(func() { firstErr = err; cancel() })
+				}
+				results[i] = result{items, missing, err}
+			}
+		}()
+	}
+feed:
+	for i := range batches {
+		select {
+		case next <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(next)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, nil, nil, firstErr
+	}
+	if err := parent.Err(); err != nil {
+		return nil, nil, nil, err
 	}
+	for i, r := range results {
+		if r.err != nil {
+			if failed == nil {
+				failed = map[string]error{}
+			}
+			for _, id := range batches[i] {
+				failed[id] = r.err
+			}
+			continue
+		}
+		items = append(items, r.items...)
+		missing = append(missing, r.missing...)
+	}
+	return items, missing, failed, nil
+}
 
-	for _, item := range resp.Response.PublishedFileDetails {
-		if item.Result != 1 {
+// fetchChunk fetches one batch, splits off the items the API reported as
+// unavailable, and records both in the cache.
+func (c *Client) fetchChunk(ctx context.Context, ids []string) (items []WorkshopItem, missing []string, err error) {
+	all, err := c.details(ctx, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+	stale, _ := c.cache.(StaleCache)
+	for _, item := range all {
+		if item.Result != ResultOK {
 			missing = append(missing, item.PublishedFileID)
+			c.setMissingResult(item.PublishedFileID, item.Result)
+			c.trace(TraceEntry{Kind: TraceItem, ID: item.PublishedFileID, Note: fmt.Sprintf("unavailable (steam result %d)", item.Result)})
+			if stale != nil {
+				stale.SetMissing(item.PublishedFileID, item.Result)
+			}
 			continue
 		}
+		c.cache.Set(item.PublishedFileID, item)
 		items = append(items, item)
 	}
 	return items, missing, nil
 }
 
+// MissingResult implements MissingReasons.
+func (c *Client) MissingResult(id string) Result {
+	c.resultsMu.Lock()
+	defer c.resultsMu.Unlock()
+	return c.results[id]
+}
+
+func (c *Client) setMissingResult(id string, r Result) {
+	c.resultsMu.Lock()
+	defer c.resultsMu.Unlock()
+	c.results[id] = r
+}
+
+// revalidateTimeout bounds a background refresh of stale entries.
+const revalidateTimeout = 30 * time.Second
+
+// revalidate refreshes stale entries in the background; the caller already has
+// the stale data. Failures are ignored, leaving the stale entries in place.
+func (c *Client) revalidate(ctx context.Context, ids []string) {
+	c.revalidating.Add(1)
+	go func() {
+		defer c.revalidating.Done()
+		ctx, cancel := context.WithTimeout(context.WithoutCancel(ctx), revalidateTimeout)
+		defer cancel()
+		_, _, _, _ = c.fetchAll(ctx, ids)
+	}()
+}
+
+// WaitRevalidation blocks until background refreshes of stale cache entries
+// have finished (so a short-lived process can persist them before exiting).
+func (c *Client) WaitRevalidation() { c.revalidating.Wait() }
+
+func (c *Client) getDetailsChunk(ctx context.Context, ids []string) ([]WorkshopItem, error) {
+	q := c.baseQuery()
+	q.Set("includechildren", "true")
+	for i, id := range ids {
+		q.Set("publishedfileids["+strconv.Itoa(i)+"]", id)
+	}
+
+	var resp detailsResponse
+	if err := c.doGet(ctx, "/IPublishedFileService/GetDetails/v1/", q, &resp); err != nil {
+		return nil, err
+	}
+	return resp.Response.PublishedFileDetails, nil
+}
+
 func chunk(s []string, size int) [][]string {
 	if size <= 0 {
 		size = 10
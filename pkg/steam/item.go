@@ -13,6 +13,35 @@ const (
 	FileTypeCollection = 2
 )
 
+// Result is a Steam EResult code, as reported per item by the details APIs.
+type Result uint8
+
+// Results the details APIs report for unavailable items. Anything else that
+// is not ResultOK is an unspecified failure.
+const (
+	ResultUnknown      Result = 0 // not reported (e.g. a fake, or an old cache entry)
+	ResultOK           Result = 1
+	ResultFail         Result = 2
+	ResultFileNotFound Result = 9  // deleted, or never existed
+	ResultAccessDenied Result = 15 // private, or hidden from the caller
+
+	// ResultNotInSnapshot is pzmod's own, not a Steam code: a SnapshotAPI was
+	// asked for an item the snapshot never fetched.
+	ResultNotInSnapshot Result = 255
+)
+
+// Visibility is who may see a Workshop item.
+type Visibility uint8
+
+// Workshop visibilities. Only public and unlisted items can be downloaded by a
+// dedicated server, which fetches anonymously.
+const (
+	VisibilityPublic      Visibility = 0
+	VisibilityFriendsOnly Visibility = 1
+	VisibilityPrivate     Visibility = 2
+	VisibilityUnlisted    Visibility = 3
+)
+
 // WorkshopItemChild is a member of a collection or a declared dependency.
 type WorkshopItemChild struct {
 	PublishedFileID string `json:"publishedfileid"`
@@ -26,7 +55,7 @@ type WorkshopTag struct {
 
 // WorkshopItem is a published Workshop file (mod, map, or collection).
 type WorkshopItem struct {
-	Result          uint8               `json:"result"`
+	Result          Result              `json:"result"`
 	FileType        uint8               `json:"file_type"`
 	FileSize        ItemSize            `json:"file_size"`
 	PublishedFileID string              `json:"publishedfileid"`
@@ -35,12 +64,21 @@ type WorkshopItem struct {
 	ShortDesc       string              `json:"short_description,omitempty"`
 	Title           string              `json:"title,omitempty"`
 	Banned          bool                `json:"banned,omitempty"`
+	Visibility      Visibility          `json:"visibility,omitempty"`
 	PreviewURL      string              `json:"preview_url,omitempty"`
 	TimeUpdated     int64               `json:"time_updated,omitempty"`
 	Subscriptions   int64               `json:"subscriptions,omitempty"`
 	Views           int64               `json:"views,omitempty"`
 	Tags            []WorkshopTag       `json:"tags,omitempty"`
 	Children        []WorkshopItemChild `json:"children,omitempty"`
+
+	// CreatorName is the creator's persona name, when resolved (see
+	// Services.NameCreators). Never sent by the details API.
+	CreatorName string `json:"-"`
+
+	// Stale is set on items served from cache past their TTL (or offline), so
+	// callers can say the data may be out of date. Never sent by the API.
+	Stale bool `json:"-"`
 }
 
 // ParsedItem holds the mod IDs and map folders scraped from a mod's description.
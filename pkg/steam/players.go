@@ -0,0 +1,105 @@
+package steam
+
+import (
+	"context"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+)
+
+// playerBatch is the most SteamIDs GetPlayerSummaries accepts per request.
+const playerBatch = 100
+
+// DefaultNameTTL is how long a resolved persona name is reused.
+const DefaultNameTTL = 24 * time.Hour
+
+// NameCache stores resolved persona names. DiskCache implements it; other
+// caches fall back to an in-memory map on the Client.
+type NameCache interface {
+	Name(steamID string) (string, bool)
+	SetName(steamID, name string)
+}
+
+// memNames is the Client's in-memory NameCache.
+type memNames struct {
+	mu  sync.Mutex
+	now func() time.Time
+	m   map[string]cachedName
+}
+
+type cachedName struct {
+	name    string
+	expires time.Time
+}
+
+func (n *memNames) Name(id string) (string, bool) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	e, ok := n.m[id]
+	if !ok || n.now().After(e.expires) {
+		return "", false
+	}
+	return e.name, true
+}
+
+func (n *memNames) SetName(id, name string) {
+	n.mu.Lock()
+	defer n.mu.Unlock()
+	n.m[id] = cachedName{name: name, expires: n.now().Add(DefaultNameTTL)}
+}
+
+type playerSummariesResponse struct {
+	Response struct {
+		Players []struct {
+			SteamID     string `json:"steamid"`
+			PersonaName string `json:"personaname"`
+		} `json:"players"`
+	} `json:"response"`
+}
+
+// nameCache returns the cache persona names go in.
+func (c *Client) nameCache() NameCache {
+	if nc, ok := c.cache.(NameCache); ok {
+		return nc
+	}
+	return c.names
+}
+
+// PlayerNames resolves SteamID64s to persona names. IDs Steam doesn't know
+// are left out of the result. Offline, or without a key, only cached names
+// are returned.
+func (c *Client) PlayerNames(ctx context.Context, steamIDs []string) (map[string]string, error) {
+	names := map[string]string{}
+	cache := c.nameCache()
+	var toFetch []string
+	for _, id := range steamIDs {
+		if id == "" {
+			continue
+		}
+		if _, done := names[id]; done {
+			continue
+		}
+		if name, ok := cache.Name(id); ok {
+			names[id] = name
+			continue
+		}
+		if !c.offline && c.apiKey != "" && !slices.Contains(toFetch, id) {
+			toFetch = append(toFetch, id)
+		}
+	}
+
+	for _, batch := range chunk(toFetch, playerBatch) {
+		q := c.baseQuery()
+		q.Set("steamids", strings.Join(batch, ","))
+		var resp playerSummariesResponse
+		if err := c.doGet(ctx, "/ISteamUser/GetPlayerSummaries/v2/", q, &resp); err != nil {
+			return names, err
+		}
+		for _, p := range resp.Response.Players {
+			names[p.SteamID] = p.PersonaName
+			cache.SetName(p.SteamID, p.PersonaName)
+		}
+	}
+	return names, nil
+}
@@ -0,0 +1,58 @@
+package steam
+
+import (
+	"context"
+	"net/http"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestPlayerNamesCached(t *testing.T) {
+	var requests atomic.Int32
+	var asked []string
+	handler := func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		asked = append(asked, r.URL.Query().Get("steamids"))
+		w.Write([]byte(`{"response":{"players":[{"steamid":"1","personaname":"Alice"}]}}`))
+	}
+	cache := NewDiskCache(t.TempDir(), DiskCacheConfig{})
+	client := testClient(t, handler, WithCache(cache))
+
+	for i := 0; i < 2; i++ {
+		names, err := client.PlayerNames(context.Background(), []string{"1", "1"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if names["1"] != "Alice" {
+			t.Fatalf("names = %v", names)
+		}
+	}
+	// Only the uncached ID is asked for; unknown ones are left out.
+	if names, _ := client.PlayerNames(context.Background(), []string{"1", "2"}); len(names) != 1 {
+		t.Errorf("names = %v; want only 1", names)
+	}
+	if n := requests.Load(); n != 2 || asked[0] != "1" || asked[1] != "2" {
+		t.Errorf("requests = %d %v; want [1 2]", n, asked)
+	}
+	if name, ok := cache.Name("1"); !ok || name != "Alice" {
+		t.Errorf("disk name = %q, %v", name, ok)
+	}
+}
+
+func TestPlayerNamesKeylessUsesCacheOnly(t *testing.T) {
+	cur := time.Unix(1000, 0)
+	cache := NewDiskCache(t.TempDir(), DiskCacheConfig{Now: func() time.Time { return cur }})
+	cache.SetName("1", "Alice")
+	client := NewPublic(WithBaseURL("http://127.0.0.1:0"), WithRateLimiter(nil), WithCache(cache))
+
+	names, err := client.PlayerNames(context.Background(), []string{"1", "2"})
+	if err != nil || len(names) != 1 || names["1"] != "Alice" {
+		t.Errorf("names = %v, %v; want cached Alice only", names, err)
+	}
+
+	cur = cur.Add(DefaultNameTTL + time.Minute)
+	if _, ok := cache.Name("1"); ok {
+		t.Error("name outlived DefaultNameTTL")
+	}
+}
@@ -0,0 +1,148 @@
+package steam
+
+import (
+	"context"
+	"net/url"
+	"strconv"
+)
+
+// PublicClient is an API backed by the ISteamRemoteStorage endpoints, which
+// answer item and collection lookups without an API key. It shares the
+// Client's cache, rate limiting, and batching; only search is unavailable.
+type PublicClient struct {
+	c *Client
+}
+
+// NewPublic returns a keyless client. Options apply as for New.
+func NewPublic(opts ...Option) *PublicClient {
+	c := New("", opts...)
+	c.details = c.getRemoteStorageChunk
+	return &PublicClient{c: c}
+}
+
+// GetDetails fetches Workshop items by ID; see Client.GetDetails.
+func (p *PublicClient) GetDetails(ctx context.Context, ids []string) ([]WorkshopItem, []string, error) {
+	return p.c.GetDetails(ctx, ids)
+}
+
+// QueryFiles always fails: the Workshop cannot be searched without a key.
+func (p *PublicClient) QueryFiles(ctx context.Context, q Query) (Page, error) {
+	return Page{}, ErrSearchNeedsKey
+}
+
+// UserFiles always fails: listing a user's files needs a key too.
+func (p *PublicClient) UserFiles(ctx context.Context, steamID string, page, perPage int) (Page, error) {
+	return Page{}, ErrSearchNeedsKey
+}
+
+// PlayerNames returns cached persona names only: resolving new ones needs a
+// key. Names are cosmetic, so this never fails.
+func (p *PublicClient) PlayerNames(ctx context.Context, steamIDs []string) (map[string]string, error) {
+	return p.c.PlayerNames(ctx, steamIDs)
+}
+
+// MissingResult implements MissingReasons.
+func (p *PublicClient) MissingResult(id string) Result { return p.c.MissingResult(id) }
+
+// WaitRevalidation blocks until background cache refreshes have finished.
+func (p *PublicClient) WaitRevalidation() { p.c.WaitRevalidation() }
+
+// remoteDetail is an ISteamRemoteStorage item. It differs from WorkshopItem in
+// field names and types, and carries neither a file type nor children.
+type remoteDetail struct {
+	Result          Result        `json:"result"`
+	PublishedFileID string        `json:"publishedfileid"`
+	Creator         string        `json:"creator"`
+	FileSize        ItemSize      `json:"file_size"`
+	HContentFile    string        `json:"hcontent_file"`
+	PreviewURL      string        `json:"preview_url"`
+	Title           string        `json:"title"`
+	Description     string        `json:"description"`
+	TimeUpdated     int64         `json:"time_updated"`
+	Banned          int           `json:"banned"`
+	Visibility      Visibility    `json:"visibility"`
+	Subscriptions   int64         `json:"subscriptions"`
+	Views           int64         `json:"views"`
+	Tags            []WorkshopTag `json:"tags"`
+}
+
+type remoteDetailsResponse struct {
+	Response struct {
+		PublishedFileDetails []remoteDetail `json:"publishedfiledetails"`
+	} `json:"response"`
+}
+
+type remoteCollectionResponse struct {
+	Response struct {
+		CollectionDetails []struct {
+			PublishedFileID string `json:"publishedfileid"`
+			Result          uint8  `json:"result"`
+			Children        []struct {
+				PublishedFileID string `json:"publishedfileid"`
+				FileType        int    `json:"filetype"`
+			} `json:"children"`
+		} `json:"collectiondetails"`
+	} `json:"response"`
+}
+
+// getRemoteStorageChunk fetches one batch with two keyless POSTs: the item
+// details, then their children (collection members and required items).
+func (c *Client) getRemoteStorageChunk(ctx context.Context, ids []string) (items []WorkshopItem, err error) {
+	form := func(countKey string) url.Values {
+		f := url.Values{}
+		f.Set(countKey, strconv.Itoa(len(ids)))
+		for i, id := range ids {
+			f.Set("publishedfileids["+strconv.Itoa(i)+"]", id)
+		}
+		return f
+	}
+
+	var details remoteDetailsResponse
+	if err := c.doPost(ctx, "/ISteamRemoteStorage/GetPublishedFileDetails/v1/", form("itemcount"), &details); err != nil {
+		return nil, err
+	}
+	var colls remoteCollectionResponse
+	if err := c.doPost(ctx, "/ISteamRemoteStorage/GetCollectionDetails/v1/", form("collectioncount"), &colls); err != nil {
+		return nil, err
+	}
+	children := map[string][]WorkshopItemChild{}
+	for _, cd := range colls.Response.CollectionDetails {
+		if cd.Result != 1 {
+			continue
+		}
+		for _, ch := range cd.Children {
+			children[cd.PublishedFileID] = append(children[cd.PublishedFileID], WorkshopItemChild{PublishedFileID: ch.PublishedFileID, FileType: ch.FileType})
+		}
+	}
+
+	for _, d := range details.Response.PublishedFileDetails {
+		if d.Result != ResultOK {
+			items = append(items, WorkshopItem{PublishedFileID: d.PublishedFileID, Result: d.Result})
+			continue
+		}
+		item := WorkshopItem{
+			Result:          d.Result,
+			FileType:        FileTypeMod,
+			FileSize:        d.FileSize,
+			PublishedFileID: d.PublishedFileID,
+			Creator:         d.Creator,
+			Description:     d.Description,
+			Title:           d.Title,
+			Banned:          d.Banned != 0,
+			Visibility:      d.Visibility,
+			PreviewURL:      d.PreviewURL,
+			TimeUpdated:     d.TimeUpdated,
+			Subscriptions:   d.Subscriptions,
+			Views:           d.Views,
+			Tags:            d.Tags,
+			Children:        children[d.PublishedFileID],
+		}
+		// The remote-storage API has no file type. A collection is the only
+		// kind of item with children but no content of its own.
+		if len(item.Children) > 0 && d.FileSize == 0 && (d.HContentFile == "" || d.HContentFile == "0") {
+			item.FileType = FileTypeCollection
+		}
+		items = append(items, item)
+	}
+	return items, nil
+}
@@ -0,0 +1,96 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+func TestPublicClientGetDetails(t *testing.T) {
+	var paths []string
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			t.Errorf("method = %s; want POST", r.Method)
+		}
+		if err := r.ParseForm(); err != nil {
+			t.Fatal(err)
+		}
+		if r.Form.Has("key") {
+			t.Error("keyless client sent a key")
+		}
+		paths = append(paths, r.URL.Path)
+		switch r.URL.Path {
+		case "/ISteamRemoteStorage/GetPublishedFileDetails/v1/":
+			if r.Form.Get("itemcount") != "3" || r.Form.Get("publishedfileids[2]") != "9" {
+				t.Errorf("details form = %v", r.Form)
+			}
+			w.Write([]byte(`{"response":{"result":1,"resultcount":3,"publishedfiledetails":[
+				{"publishedfileid":"1","result":1,"title":"Lib","description":"Mod ID: Lib","file_size":"2048","hcontent_file":"123","banned":0},
+				{"publishedfileid":"2","result":1,"title":"Pack","file_size":0,"banned":1},
+				{"publishedfileid":"9","result":9}
+			]}}`))
+		case "/ISteamRemoteStorage/GetCollectionDetails/v1/":
+			if r.Form.Get("collectioncount") != "3" {
+				t.Errorf("collection form = %v", r.Form)
+			}
+			w.Write([]byte(`{"response":{"result":1,"resultcount":3,"collectiondetails":[
+				{"publishedfileid":"1","result":9},
+				{"publishedfileid":"2","result":1,"children":[{"publishedfileid":"1","sortorder":1,"filetype":0}]},
+				{"publishedfileid":"9","result":9}
+			]}}`))
+		default:
+			t.Errorf("unexpected path %s", r.URL.Path)
+		}
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewPublic(WithBaseURL(srv.URL), WithRateLimiter(nil))
+	items, missing, err := client.GetDetails(context.Background(), []string{"1", "2", "9"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(paths) != 2 {
+		t.Errorf("requests = %v; want details + collections", paths)
+	}
+	if len(items) != 2 || len(missing) != 1 || missing[0] != "9" {
+		t.Fatalf("items = %+v, missing = %v", items, missing)
+	}
+	if r := MissingResult(client, "9"); r != ResultFileNotFound {
+		t.Errorf("MissingResult(9) = %d; want %d", r, ResultFileNotFound)
+	}
+	lib, pack := items[0], items[1]
+	if lib.IsCollection() || lib.FileSize != 2048 || lib.Parse().Mods[0] != "Lib" {
+		t.Errorf("lib = %+v", lib)
+	}
+	if !pack.IsCollection() || !pack.Banned || len(pack.GetChildIDs()) != 1 {
+		t.Errorf("pack = %+v; want a banned collection with one child", pack)
+	}
+
+	if _, err := client.QueryFiles(context.Background(), Query{SearchText: "x"}); !errors.Is(err, ErrSearchNeedsKey) {
+		t.Errorf("search err = %v; want ErrSearchNeedsKey", err)
+	}
+}
+
+func TestPublicClientForbidden(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	t.Cleanup(srv.Close)
+
+	client := NewPublic(WithBaseURL(srv.URL), WithRateLimiter(nil))
+	_, _, err := client.GetDetails(context.Background(), []string{"1"})
+	if !errors.Is(err, ErrAccessDenied) || errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("err = %v; want ErrAccessDenied, not ErrInvalidAPIKey", err)
+	}
+}
+
+func TestNewAPISelectsByKey(t *testing.T) {
+	if _, ok := NewAPI("").(*PublicClient); !ok {
+		t.Error("NewAPI(\"\") should be keyless")
+	}
+	if _, ok := NewAPI("KEY").(*Client); !ok {
+		t.Error("NewAPI(key) should use the keyed client")
+	}
+}
@@ -2,30 +2,104 @@ package steam
 
 import (
 	"context"
+	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // Query types (EPublishedFileQueryType). Verified against the live API for
 // appid 108600: 12 returns relevance-ranked results for a text search, 3 ranks
 // by trend for browsing without a query.
 const (
-	queryRankedByTrend      = 3
-	queryRankedByTextSearch = 12
+	queryRankedByPublicationDate    = 1
+	queryRankedByTrend              = 3
+	queryRankedByTotalSubscriptions = 9
+	queryRankedByTextSearch         = 12
+	queryRankedByLastUpdatedDate    = 21
 )
 
+// Sort is a search ranking.
+type Sort string
+
+// Search rankings. SortDefault ranks by relevance for a text search and by
+// trend otherwise.
+const (
+	SortDefault    Sort = ""
+	SortRelevance  Sort = "relevance"
+	SortTrend      Sort = "trend"
+	SortSubscribed Sort = "subscribed"
+	SortRecent     Sort = "recent"
+	SortUpdated    Sort = "updated"
+)
+
+// Sorts lists the named rankings, for flag help and completion.
+var Sorts = []Sort{SortRelevance, SortTrend, SortSubscribed, SortRecent, SortUpdated}
+
+// ParseSort validates a ranking name ("" is SortDefault).
+func ParseSort(s string) (Sort, error) {
+	if s == "" {
+		return SortDefault, nil
+	}
+	for _, v := range Sorts {
+		if string(v) == strings.ToLower(s) {
+			return v, nil
+		}
+	}
+	return "", fmt.Errorf("unknown sort %q (want one of %s)", s, joinSorts())
+}
+
+func joinSorts() string {
+	names := make([]string, len(Sorts))
+	for i, v := range Sorts {
+		names[i] = string(v)
+	}
+	return strings.Join(names, ", ")
+}
+
+// queryType maps a ranking to its EPublishedFileQueryType.
+func (s Sort) queryType(hasText bool) int {
+	switch s {
+	case SortTrend:
+		return queryRankedByTrend
+	case SortSubscribed:
+		return queryRankedByTotalSubscriptions
+	case SortRecent:
+		return queryRankedByPublicationDate
+	case SortUpdated:
+		return queryRankedByLastUpdatedDate
+	}
+	if hasText {
+		return queryRankedByTextSearch
+	}
+	return queryRankedByTrend
+}
+
 // Query parameterizes a Workshop search.
 type Query struct {
 	// SearchText is the free-text query. When empty, results are browsed by
 	// trend instead of ranked by relevance.
 	SearchText string
+	// Sort picks the ranking (see SortDefault).
+	Sort Sort
 	// Tags restricts results to items carrying all of these Workshop tags
-	// (e.g. "Mod", "Build 41").
-	Tags []string
+	// (e.g. "Mod", "Build 41"), or any of them with MatchAnyTag.
+	Tags        []string
+	MatchAnyTag bool
+	// ExcludedTags drops items carrying any of these tags.
+	ExcludedTags []string
+	// CreatedAfter/Before and UpdatedAfter/Before bound results by date. Zero
+	// values leave that end of the window open.
+	CreatedAfter, CreatedBefore time.Time
+	UpdatedAfter, UpdatedBefore time.Time
 	// PerPage is the page size (default 20, max 100 per the API).
 	PerPage int
 	// Cursor pages through results; use "" or "*" for the first page and the
 	// returned NextCursor for subsequent pages.
 	Cursor string
+	// Page jumps to a 1-based page number instead. It is only used when
+	// Cursor is empty, and such a page has no NextCursor.
+	Page int
 }
 
 // Page is a single page of search results.
@@ -45,24 +119,24 @@ type queryResponse struct {
 
 // QueryFiles searches the Workshop for the configured app, returning one page.
 func (c *Client) QueryFiles(ctx context.Context, q Query) (Page, error) {
+	if c.offline {
+		return Page{}, fmt.Errorf("workshop search: %w", ErrOffline)
+	}
 	perPage := q.PerPage
 	if perPage <= 0 {
 		perPage = 20
 	}
-	cursor := q.Cursor
-	if cursor == "" {
-		cursor = "*"
-	}
-
-	queryType := queryRankedByTrend
-	if q.SearchText != "" {
-		queryType = queryRankedByTextSearch
-	}
-
 	v := c.baseQuery()
 	v.Set("appid", strconv.Itoa(c.appID))
-	v.Set("query_type", strconv.Itoa(queryType))
-	v.Set("cursor", cursor)
+	v.Set("query_type", strconv.Itoa(q.Sort.queryType(q.SearchText != "")))
+	switch {
+	case q.Cursor != "":
+		v.Set("cursor", q.Cursor)
+	case q.Page > 0:
+		v.Set("page", strconv.Itoa(q.Page))
+	default:
+		v.Set("cursor", "*")
+	}
 	v.Set("numperpage", strconv.Itoa(perPage))
 	v.Set("return_metadata", "true")
 	v.Set("return_short_description", "true")
@@ -73,6 +147,22 @@ func (c *Client) QueryFiles(ctx context.Context, q Query) (Page, error) {
 	for i, tag := range q.Tags {
 		v.Set("requiredtags["+strconv.Itoa(i)+"]", tag)
 	}
+	if q.MatchAnyTag && len(q.Tags) > 0 {
+		v.Set("match_all_tags", "false")
+	}
+	for i, tag := range q.ExcludedTags {
+		v.Set("excludedtags["+strconv.Itoa(i)+"]", tag)
+	}
+	// The date ranges are nested messages, addressed field by field.
+	setTime := func(key string, t time.Time) {
+		if !t.IsZero() {
+			v.Set(key, strconv.FormatInt(t.Unix(), 10))
+		}
+	}
+	setTime("date_range_created[timestamp_start]", q.CreatedAfter)
+	setTime("date_range_created[timestamp_end]", q.CreatedBefore)
+	setTime("date_range_updated[timestamp_start]", q.UpdatedAfter)
+	setTime("date_range_updated[timestamp_end]", q.UpdatedBefore)
 
 	var resp queryResponse
 	if err := c.doGet(ctx, "/IPublishedFileService/QueryFiles/v1/", v, &resp); err != nil {
@@ -0,0 +1,182 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Retry and circuit-breaker defaults.
+const (
+	DefaultRetries         = 3
+	DefaultMaxRetryWait    = time.Minute
+	DefaultBreakerFailures = 5
+	DefaultBreakerCooldown = 30 * time.Second
+
+	retryBaseWait = 500 * time.Millisecond
+)
+
+// ErrCircuitOpen is returned without contacting Steam while the circuit
+// breaker is open after repeated failures.
+var ErrCircuitOpen = errors.New("steam api is failing; pausing requests")
+
+// statusError is a non-OK HTTP response. 429 and 5xx responses are retried,
+// honouring Retry-After when Steam sends it.
+type statusError struct {
+	code       int
+	status     string
+	retryAfter time.Duration
+}
+
+func (e *statusError) Error() string { return "steam api request failed with status " + e.status }
+
+func (e *statusError) temporary() bool {
+	return e.code == http.StatusTooManyRequests || e.code >= 500
+}
+
+// transportError is a request that got no response at all.
+type transportError struct{ err error }
+
+func (e *transportError) Error() string { return e.err.Error() }
+func (e *transportError) Unwrap() error { return e.err }
+
+// temporary reports whether err is worth retrying and counts against the
+// circuit breaker: no response, a 429, or a 5xx.
+func temporary(err error) bool {
+	var se *statusError
+	if errors.As(err, &se) {
+		return se.temporary()
+	}
+	var te *transportError
+	return errors.As(err, &te)
+}
+
+// parseRetryAfter reads a Retry-After header given in seconds or as an HTTP
+// date. It returns 0 when the header is absent or unparseable.
+func parseRetryAfter(h string, now time.Time) time.Duration {
+	h = strings.TrimSpace(h)
+	if h == "" {
+		return 0
+	}
+	if secs, err := strconv.Atoi(h); err == nil && secs > 0 {
+		return time.Duration(secs) * time.Second
+	}
+	if t, err := http.ParseTime(h); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
+
+// retryWait is how long to wait before retry attempt+1: what Steam asked for,
+// or exponential backoff from retryBaseWait.
+func retryWait(err error, attempt int) time.Duration {
+	var se *statusError
+	if errors.As(err, &se) && se.retryAfter > 0 {
+		return se.retryAfter
+	}
+	return retryBaseWait << attempt
+}
+
+// sleepCtx waits for d or until ctx is done.
+func sleepCtx(ctx context.Context, d time.Duration) error {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+// breaker is a consecutive-failure circuit breaker. After threshold temporary
+// failures in a row it rejects requests for cooldown; after that a single probe
+// is let through while the rest are still rejected, and its outcome closes the
+// breaker or re-opens it.
+type breaker struct {
+	mu        sync.Mutex
+	threshold int
+	cooldown  time.Duration
+	now       func() time.Time
+	failures  int
+	openUntil time.Time
+	probing   bool // a half-open probe is in flight
+}
+
+// allow reports whether a request may go out, and whether it is the probe; a
+// probe must be finished with endProbe.
+func (b *breaker) allow() (probe bool, err error) {
+	if b.threshold <= 0 {
+		return false, nil
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.failures < b.threshold {
+		return false, nil
+	}
+	if now := b.now(); now.Before(b.openUntil) {
+		return false, fmt.Errorf("%w (retry in %s)", ErrCircuitOpen, b.openUntil.Sub(now).Round(time.Second))
+	}
+	if b.probing {
+		return false, fmt.Errorf("%w (checking whether Steam is back)", ErrCircuitOpen)
+	}
+	b.probing = true
+	return true, nil
+}
+
+// endProbe lets the next request probe again. A probe that was cancelled
+// before recording an outcome leaves the breaker half-open.
+func (b *breaker) endProbe() {
+	b.mu.Lock()
+	b.probing = false
+	b.mu.Unlock()
+}
+
+func (b *breaker) record(failed bool) {
+	if b.threshold <= 0 {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !failed {
+		b.failures = 0
+		return
+	}
+	b.failures++
+	if b.failures >= b.threshold {
+		b.openUntil = b.now().Add(b.cooldown)
+	}
+}
+
+// PartialError is returned by GetDetails, together with everything it could
+// fetch, when some batches failed (Steam timing out, rate limiting, or down
+// for maintenance). Callers can report on what they have and flag the rest.
+type PartialError struct {
+	IDs  []string         // the IDs that could not be fetched, in request order
+	Errs map[string]error // why, per ID
+}
+
+func (e *PartialError) Error() string {
+	if len(e.IDs) == 0 {
+		return "no items could be fetched"
+	}
+	return fmt.Sprintf("%d item(s) could not be fetched: %v", len(e.IDs), e.Errs[e.IDs[0]])
+}
+
+// Unwrap returns the distinct underlying errors, so errors.Is sees through.
+func (e *PartialError) Unwrap() []error {
+	var out []error
+	seen := map[error]bool{}
+	for _, id := range e.IDs {
+		if err := e.Errs[id]; err != nil && !seen[err] {
+			seen[err] = true
+			out = append(out, err)
+		}
+	}
+	return out
+}
@@ -0,0 +1,195 @@
+package steam
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// noSleep records requested waits instead of sleeping.
+func noSleep(c *Client) *[]time.Duration {
+	var mu sync.Mutex
+	var waits []time.Duration
+	c.sleep = func(_ context.Context, d time.Duration) error {
+		mu.Lock()
+		defer mu.Unlock()
+		waits = append(waits, d)
+		return nil
+	}
+	return &waits
+}
+
+func TestRetryAfterHonoured(t *testing.T) {
+	var requests atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		if requests.Add(1) == 1 {
+			w.Header().Set("Retry-After", "7")
+			w.WriteHeader(http.StatusTooManyRequests)
+			return
+		}
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"result":1,"publishedfileid":"1"}]}}`))
+	})
+	waits := noSleep(client)
+
+	items, _, err := client.GetDetails(context.Background(), []string{"1"})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("items = %v, err = %v", items, err)
+	}
+	if len(*waits) != 1 || (*waits)[0] != 7*time.Second {
+		t.Errorf("waits = %v; want [7s] from Retry-After", *waits)
+	}
+}
+
+func TestRetryAfterTooLongFails(t *testing.T) {
+	var requests atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.Header().Set("Retry-After", "3600")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}, WithRetries(3, time.Minute))
+	noSleep(client)
+
+	if _, _, err := client.GetDetails(context.Background(), []string{"1"}); err == nil {
+		t.Fatal("want an error when Steam asks for an hour")
+	}
+	if n := requests.Load(); n != 1 {
+		t.Errorf("requests = %d; want 1 (no point retrying)", n)
+	}
+}
+
+func TestCircuitBreakerOpens(t *testing.T) {
+	cur := time.Unix(1000, 0)
+	var requests atomic.Int32
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		w.WriteHeader(http.StatusBadGateway)
+	}, WithRetries(0, 0), WithCircuitBreaker(2, time.Minute), WithClock(func() time.Time { return cur }))
+
+	for range 2 {
+		if _, err := client.QueryFiles(context.Background(), Query{}); err == nil {
+			t.Fatal("want 502 error")
+		}
+	}
+	if _, err := client.QueryFiles(context.Background(), Query{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err = %v; want ErrCircuitOpen", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d; want 2 (third rejected locally)", n)
+	}
+
+	cur = cur.Add(2 * time.Minute)
+	if _, err := client.QueryFiles(context.Background(), Query{}); errors.Is(err, ErrCircuitOpen) {
+		t.Error("breaker still open after cooldown")
+	}
+	if n := requests.Load(); n != 3 {
+		t.Errorf("requests = %d; want a trial request after cooldown", n)
+	}
+}
+
+func TestCircuitBreakerSingleProbe(t *testing.T) {
+	var mu sync.Mutex
+	cur := time.Unix(1000, 0)
+	now := func() time.Time { mu.Lock(); defer mu.Unlock(); return cur }
+	var requests atomic.Int32
+	var healthy atomic.Bool
+	arrived, release := make(chan struct{}), make(chan struct{})
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		requests.Add(1)
+		if !healthy.Load() {
+			w.WriteHeader(http.StatusBadGateway)
+			return
+		}
+		arrived <- struct{}{}
+		<-release
+		w.Write([]byte(`{"response":{"total":0}}`))
+	}, WithRetries(0, 0), WithCircuitBreaker(1, time.Minute), WithClock(now))
+
+	if _, err := client.QueryFiles(context.Background(), Query{}); err == nil {
+		t.Fatal("want 502 error")
+	}
+	mu.Lock()
+	cur = cur.Add(2 * time.Minute)
+	mu.Unlock()
+	healthy.Store(true)
+
+	done := make(chan error)
+	go func() {
+		_, err := client.QueryFiles(context.Background(), Query{})
+		done <- err
+	}()
+	<-arrived
+	if _, err := client.QueryFiles(context.Background(), Query{}); !errors.Is(err, ErrCircuitOpen) {
+		t.Errorf("err during probe = %v; want ErrCircuitOpen", err)
+	}
+	close(release)
+	if err := <-done; err != nil {
+		t.Fatalf("probe err = %v", err)
+	}
+	if n := requests.Load(); n != 2 {
+		t.Errorf("requests = %d; want 2 (one failure, one probe)", n)
+	}
+	go func() { <-arrived }()
+	if _, err := client.QueryFiles(context.Background(), Query{}); err != nil {
+		t.Errorf("err after a good probe = %v; want the breaker closed", err)
+	}
+}
+
+func TestGetDetailsPartial(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		ids := requestedIDs(r.URL.Query())
+		if ids[0] == "2" {
+			w.WriteHeader(http.StatusInternalServerError)
+			return
+		}
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"result":1,"publishedfileid":"` + ids[0] + `"}]}}`))
+	}, WithChunkSize(1), WithRetries(1, 0), WithCircuitBreaker(0, 0))
+	noSleep(client)
+
+	items, _, err := client.GetDetails(context.Background(), []string{"1", "2", "3"})
+	var partial *PartialError
+	if !errors.As(err, &partial) {
+		t.Fatalf("err = %v; want *PartialError", err)
+	}
+	if len(partial.IDs) != 1 || partial.IDs[0] != "2" {
+		t.Errorf("failed IDs = %v; want [2]", partial.IDs)
+	}
+	if len(items) != 2 || items[0].PublishedFileID != "1" || items[1].PublishedFileID != "3" {
+		t.Errorf("items = %+v; want 1 and 3", items)
+	}
+
+	// With nothing fetched at all it is a plain error.
+	_, _, err = client.GetDetails(context.Background(), []string{"2"})
+	if err == nil || errors.As(err, &partial) {
+		t.Errorf("err = %v; want a plain error", err)
+	}
+}
+
+func TestGetDetailsInvalidKeyNotPartial(t *testing.T) {
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}, WithChunkSize(1))
+
+	if _, _, err := client.GetDetails(context.Background(), []string{"1", "2"}); !errors.Is(err, ErrInvalidAPIKey) {
+		t.Errorf("err = %v; want ErrInvalidAPIKey", err)
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2024, 1, 1, 12, 0, 0, 0, time.UTC)
+	cases := map[string]time.Duration{
+		"":                              0,
+		"30":                            30 * time.Second,
+		"soon":                          0,
+		"Mon, 01 Jan 2024 12:00:10 GMT": 10 * time.Second,
+		"Mon, 01 Jan 2024 11:00:00 GMT": 0,
+	}
+	for h, want := range cases {
+		if got := parseRetryAfter(h, now); got != want {
+			t.Errorf("parseRetryAfter(%q) = %v; want %v", h, got, want)
+		}
+	}
+}
@@ -0,0 +1,131 @@
+package steam
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+)
+
+// SnapshotVersion is the current Workshop snapshot format.
+const SnapshotVersion = 1
+
+// ErrNotInSnapshot is returned by SnapshotAPI for lookups a snapshot can't
+// answer: searches and author listings.
+var ErrNotInSnapshot = errors.New("not available from a Workshop snapshot")
+
+// Snapshot is a frozen set of Workshop items, written by `workshop snapshot`
+// so validation can be reproduced exactly, offline, and attached to bug
+// reports.
+type Snapshot struct {
+	Version int               `json:"version"`
+	Created time.Time         `json:"created"`
+	Items   []WorkshopItem    `json:"items"`
+	Missing map[string]Result `json:"missing,omitempty"` // IDs Steam reported unavailable, and why
+	Names   map[string]string `json:"names,omitempty"`   // creator SteamID -> persona name
+}
+
+// LoadSnapshot reads a snapshot file.
+func LoadSnapshot(path string) (*Snapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var s Snapshot
+	if err := json.Unmarshal(data, &s); err != nil {
+		return nil, fmt.Errorf("workshop snapshot %s: %w", path, err)
+	}
+	if s.Version > SnapshotVersion {
+		return nil, fmt.Errorf("workshop snapshot %s is version %d; this pzmod reads up to %d", path, s.Version, SnapshotVersion)
+	}
+	return &s, nil
+}
+
+// Save writes the snapshot to path, items sorted by ID so snapshots diff
+// cleanly.
+func (s *Snapshot) Save(path string) error {
+	s.Version = SnapshotVersion
+	sort.Slice(s.Items, func(i, j int) bool { return s.Items[i].PublishedFileID < s.Items[j].PublishedFileID })
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// SnapshotAPI answers GetDetails from a Snapshot and never touches the
+// network. IDs not in the snapshot are reported missing.
+type SnapshotAPI struct {
+	items   map[string]WorkshopItem
+	missing map[string]Result
+	names   map[string]string
+}
+
+var (
+	_ API            = (*SnapshotAPI)(nil)
+	_ MissingReasons = (*SnapshotAPI)(nil)
+)
+
+// NewSnapshotAPI returns an API backed by s.
+func NewSnapshotAPI(s *Snapshot) *SnapshotAPI {
+	a := &SnapshotAPI{items: make(map[string]WorkshopItem, len(s.Items)), missing: s.Missing, names: s.Names}
+	for _, it := range s.Items {
+		a.items[it.PublishedFileID] = it
+	}
+	return a
+}
+
+// GetDetails returns the snapshotted items among ids; the rest are missing.
+func (a *SnapshotAPI) GetDetails(_ context.Context, ids []string) ([]WorkshopItem, []string, error) {
+	var items []WorkshopItem
+	var missing []string
+	seen := map[string]bool{}
+	for _, id := range ids {
+		if id == "" || seen[id] {
+			continue
+		}
+		seen[id] = true
+		if it, ok := a.items[id]; ok {
+			items = append(items, it)
+		} else {
+			missing = append(missing, id)
+		}
+	}
+	return items, missing, nil
+}
+
+// MissingResult implements MissingReasons with the result recorded when the
+// snapshot was taken, or ResultNotInSnapshot for an item it never fetched.
+func (a *SnapshotAPI) MissingResult(id string) Result {
+	if r, ok := a.missing[id]; ok {
+		return r
+	}
+	if _, ok := a.items[id]; ok {
+		return ResultUnknown
+	}
+	return ResultNotInSnapshot
+}
+
+// QueryFiles always fails: a snapshot can't be searched.
+func (a *SnapshotAPI) QueryFiles(context.Context, Query) (Page, error) {
+	return Page{}, fmt.Errorf("search: %w", ErrNotInSnapshot)
+}
+
+// UserFiles always fails: a snapshot holds no author listings.
+func (a *SnapshotAPI) UserFiles(context.Context, string, int, int) (Page, error) {
+	return Page{}, fmt.Errorf("author listing: %w", ErrNotInSnapshot)
+}
+
+// PlayerNames returns the names recorded in the snapshot.
+func (a *SnapshotAPI) PlayerNames(_ context.Context, steamIDs []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, id := range steamIDs {
+		if name, ok := a.names[id]; ok {
+			out[id] = name
+		}
+	}
+	return out, nil
+}
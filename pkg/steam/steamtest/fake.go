@@ -3,6 +3,7 @@ package steamtest
 
 import (
 	"context"
+	"sort"
 	"strings"
 
 	"github.com/kldzj/pzmod/pkg/steam"
@@ -20,6 +21,20 @@ type Fake struct {
 	// DetailsCalls counts GetDetails invocations (to assert caching upstream).
 	DetailsCalls int
 	QueryCalls   int
+	UserCalls    int
+
+	// Fail makes GetDetails fail these IDs, returning the rest with a
+	// *steam.PartialError as the client does when some batches fail.
+	Fail map[string]error
+
+	// Results gives the Steam result MissingResult reports for unknown IDs.
+	Results map[string]steam.Result
+
+	// Names maps SteamIDs to persona names for PlayerNames.
+	Names map[string]string
+
+	// LastQuery is the most recent QueryFiles argument.
+	LastQuery steam.Query
 }
 
 // New returns a Fake seeded with the given items.
@@ -41,23 +56,36 @@ func (f *Fake) GetDetails(_ context.Context, ids []string) ([]steam.WorkshopItem
 	}
 	var items []steam.WorkshopItem
 	var missing []string
+	var partial *steam.PartialError
 	for _, id := range ids {
 		if id == "" {
 			continue
 		}
-		if it, ok := f.Items[id]; ok {
+		if _, ok := f.Fail[id]; ok {
+			if partial == nil {
+				partial = &steam.PartialError{Errs: f.Fail}
+			}
+			partial.IDs = append(partial.IDs, id)
+		} else if it, ok := f.Items[id]; ok {
 			items = append(items, it)
 		} else {
 			missing = append(missing, id)
 		}
 	}
+	if partial != nil {
+		return items, missing, partial
+	}
 	return items, missing, nil
 }
 
+// MissingResult implements steam.MissingReasons from Results.
+func (f *Fake) MissingResult(id string) steam.Result { return f.Results[id] }
+
 // QueryFiles returns items whose title contains the (case-insensitive) search
 // text; with no search text it returns all items. Paging is ignored.
 func (f *Fake) QueryFiles(_ context.Context, q steam.Query) (steam.Page, error) {
 	f.QueryCalls++
+	f.LastQuery = q
 	if f.QueryErr != nil {
 		return steam.Page{}, f.QueryErr
 	}
@@ -69,3 +97,31 @@ func (f *Fake) QueryFiles(_ context.Context, q steam.Query) (steam.Page, error)
 	}
 	return steam.Page{Items: items, Total: len(items)}, nil
 }
+
+// UserFiles returns the items whose Creator is steamID, sorted by ID. Paging is
+// ignored.
+func (f *Fake) UserFiles(_ context.Context, steamID string, page, perPage int) (steam.Page, error) {
+	f.UserCalls++
+	if f.QueryErr != nil {
+		return steam.Page{}, f.QueryErr
+	}
+	var items []steam.WorkshopItem
+	for _, it := range f.Items {
+		if it.Creator == steamID {
+			items = append(items, it)
+		}
+	}
+	sort.Slice(items, func(i, j int) bool { return items[i].PublishedFileID < items[j].PublishedFileID })
+	return steam.Page{Items: items, Total: len(items)}, nil
+}
+
+// PlayerNames returns the known names from Names.
+func (f *Fake) PlayerNames(_ context.Context, steamIDs []string) (map[string]string, error) {
+	out := map[string]string{}
+	for _, id := range steamIDs {
+		if name, ok := f.Names[id]; ok {
+			out[id] = name
+		}
+	}
+	return out, nil
+}
@@ -0,0 +1,294 @@
+package steam
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/dustin/go-humanize"
+)
+
+// Trace entry kinds.
+const (
+	TraceHTTP  = "http"  // a request to the API
+	TraceCache = "cache" // a GetDetails lookup answered (or not) by the cache
+	TraceItem  = "item"  // an item the API reported as unavailable
+)
+
+// TraceEntry is one recorded Steam lookup.
+type TraceEntry struct {
+	Kind     string        `json:"kind"`
+	Time     time.Time     `json:"time"`
+	Method   string        `json:"method,omitempty"`
+	URL      string        `json:"url,omitempty"` // with the API key redacted
+	Status   int           `json:"status,omitempty"`
+	Duration time.Duration `json:"durationNs,omitempty"`
+	Bytes    int64         `json:"bytes,omitempty"`
+	ID       string        `json:"id,omitempty"`
+	Cache    string        `json:"cache,omitempty"` // hit, stale, negative, or miss
+	Note     string        `json:"note,omitempty"`
+	Error    string        `json:"error,omitempty"`
+}
+
+// Tracer receives every Steam request and cache lookup a Client makes. It
+// must be safe for concurrent use.
+type Tracer interface {
+	Record(TraceEntry)
+}
+
+// WithTracer records the client's requests and cache lookups to t.
+func WithTracer(t Tracer) Option { return func(c *Client) { c.tracer = t } }
+
+func (c *Client) trace(e TraceEntry) {
+	if c.tracer != nil {
+		if e.Time.IsZero() {
+			e.Time = c.now()
+		}
+		c.tracer.Record(e)
+	}
+}
+
+// traceCache records a GetDetails cache outcome for id.
+func (c *Client) traceCache(id, outcome string) {
+	if c.tracer != nil {
+		c.trace(TraceEntry{Kind: TraceCache, ID: id, Cache: outcome})
+	}
+}
+
+// redactURL hides the API key in a request URL.
+func redactURL(u *url.URL) string {
+	cp := *u
+	q := cp.Query()
+	if q.Has("key") {
+		q.Set("key", "REDACTED")
+		cp.RawQuery = q.Encode()
+	}
+	return cp.String()
+}
+
+// tracingTransport records each round trip once its body has been read.
+type tracingTransport struct {
+	base http.RoundTripper
+	c    *Client
+}
+
+func (t tracingTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	start := time.Now()
+	e := TraceEntry{Kind: TraceHTTP, Time: t.c.now(), Method: req.Method, URL: redactURL(req.URL), Cache: "miss"}
+	resp, err := t.base.RoundTrip(req)
+	if err != nil {
+		e.Duration = time.Since(start)
+		e.Error = err.Error()
+		t.c.trace(e)
+		return nil, err
+	}
+	e.Status = resp.StatusCode
+	resp.Body = &tracedBody{ReadCloser: resp.Body, done: func(n int64) {
+		e.Duration = time.Since(start)
+		e.Bytes = n
+		t.c.trace(e)
+	}}
+	return resp, nil
+}
+
+type tracedBody struct {
+	io.ReadCloser
+	n    int64
+	once sync.Once
+	done func(n int64)
+}
+
+func (b *tracedBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *tracedBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.once.Do(func() { b.done(b.n) })
+	return err
+}
+
+// traceHTTP wraps the client's transport so requests are recorded. It works
+// on a copy, leaving an injected *http.Client untouched.
+func (c *Client) traceHTTP() {
+	hc := *c.http
+	base := hc.Transport
+	if base == nil {
+		base = http.DefaultTransport
+	}
+	hc.Transport = tracingTransport{base: base, c: c}
+	c.http = &hc
+}
+
+// TraceFile is a Tracer writing to a file: NDJSON (one entry per line, as
+// they happen) or, for a .har path, an HTTP Archive written on Close.
+type TraceFile struct {
+	mu      sync.Mutex
+	path    string
+	f       *os.File
+	enc     *json.Encoder
+	har     bool
+	entries []TraceEntry
+	summary TraceSummary
+	closed  bool
+}
+
+// TraceSummary tallies a trace.
+type TraceSummary struct {
+	Requests  int           // HTTP requests made
+	Failed    int           // of which failed (no response, or not 200)
+	Bytes     int64         // response bytes read
+	Time      time.Duration // summed request time
+	CacheHits int           // lookups answered by the cache (fresh, stale, or negative)
+	Misses    int           // lookups that went to Steam
+}
+
+func (s TraceSummary) String() string {
+	return fmt.Sprintf("%d request(s), %d failed, %s in %s; %d cache hit(s), %d miss(es)",
+		s.Requests, s.Failed, humanize.Bytes(uint64(s.Bytes)), s.Time.Round(time.Millisecond), s.CacheHits, s.Misses)
+}
+
+// OpenTraceFile creates (truncating) the trace file at path.
+func OpenTraceFile(path string) (*TraceFile, error) {
+	f, err := os.Create(path)
+	if err != nil {
+		return nil, err
+	}
+	t := &TraceFile{path: path, f: f, har: strings.EqualFold(filepath.Ext(path), ".har")}
+	if !t.har {
+		t.enc = json.NewEncoder(f)
+	}
+	return t, nil
+}
+
+// Path is the file being written.
+func (t *TraceFile) Path() string { return t.path }
+
+// Record implements Tracer. Entries arriving after Close (a background
+// refresh outliving the command) are dropped.
+func (t *TraceFile) Record(e TraceEntry) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if t.closed {
+		return
+	}
+	switch e.Kind {
+	case TraceHTTP:
+		t.summary.Requests++
+		t.summary.Bytes += e.Bytes
+		t.summary.Time += e.Duration
+		if e.Error != "" || e.Status != http.StatusOK {
+			t.summary.Failed++
+		}
+	case TraceCache:
+		if e.Cache == "miss" {
+			t.summary.Misses++
+		} else {
+			t.summary.CacheHits++
+		}
+	}
+	if t.har {
+		t.entries = append(t.entries, e)
+		return
+	}
+	_ = t.enc.Encode(e)
+}
+
+// Summary returns the tallies so far.
+func (t *TraceFile) Summary() TraceSummary {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	return t.summary
+}
+
+// Close writes the HAR document (for .har files) and closes the file.
+func (t *TraceFile) Close() error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.closed = true
+	if t.har {
+		enc := json.NewEncoder(t.f)
+		enc.SetIndent("", "  ")
+		if err := enc.Encode(harLog(t.entries)); err != nil {
+			t.f.Close()
+			return err
+		}
+	}
+	return t.f.Close()
+}
+
+// HAR 1.2, as much of it as viewers require. Cache lookups and unavailable
+// items are not requests, so they ride along as a custom _pzmod field on the
+// log rather than as entries.
+type harDoc struct {
+	Log struct {
+		Version string         `json:"version"`
+		Creator map[string]any `json:"creator"`
+		Entries []harEntry     `json:"entries"`
+		Lookups []TraceEntry   `json:"_pzmod"`
+	} `json:"log"`
+}
+
+type harEntry struct {
+	Started  string         `json:"startedDateTime"`
+	Time     float64        `json:"time"`
+	Request  map[string]any `json:"request"`
+	Response map[string]any `json:"response"`
+	Cache    map[string]any `json:"cache"`
+	Timings  map[string]any `json:"timings"`
+	Comment  string         `json:"comment,omitempty"`
+}
+
+func harLog(entries []TraceEntry) harDoc {
+	var doc harDoc
+	doc.Log.Version = "1.2"
+	doc.Log.Creator = map[string]any{"name": "pzmod", "version": ""}
+	doc.Log.Entries = []harEntry{}
+	doc.Log.Lookups = []TraceEntry{}
+	for _, e := range entries {
+		if e.Kind != TraceHTTP {
+			doc.Log.Lookups = append(doc.Log.Lookups, e)
+			continue
+		}
+		ms := float64(e.Duration) / float64(time.Millisecond)
+		var query []map[string]string
+		if u, err := url.Parse(e.URL); err == nil {
+			for k, vs := range u.Query() {
+				for _, v := range vs {
+					query = append(query, map[string]string{"name": k, "value": v})
+				}
+			}
+		}
+		if query == nil {
+			query = []map[string]string{}
+		}
+		doc.Log.Entries = append(doc.Log.Entries, harEntry{
+			Started: e.Time.Format(time.RFC3339Nano),
+			Time:    ms,
+			Request: map[string]any{
+				"method": e.Method, "url": e.URL, "httpVersion": "HTTP/1.1",
+				"headers": []any{}, "queryString": query, "cookies": []any{},
+				"headersSize": -1, "bodySize": -1,
+			},
+			Response: map[string]any{
+				"status": e.Status, "statusText": http.StatusText(e.Status), "httpVersion": "HTTP/1.1",
+				"headers": []any{}, "cookies": []any{}, "redirectURL": "",
+				"content":     map[string]any{"size": e.Bytes, "mimeType": "application/json"},
+				"headersSize": -1, "bodySize": e.Bytes,
+			},
+			Cache:   map[string]any{},
+			Timings: map[string]any{"send": 0, "wait": ms, "receive": 0},
+			Comment: e.Error,
+		})
+	}
+	return doc
+}
@@ -0,0 +1,133 @@
+package steam
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestTraceFileNDJSON(t *testing.T) {
+	body := `{"response":{"publishedfiledetails":[{"result":1,"publishedfileid":"1"},{"result":9,"publishedfileid":"2"}]}}`
+	path := filepath.Join(t.TempDir(), "trace.ndjson")
+	tf, err := OpenTraceFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(body))
+	}, WithTracer(tf))
+
+	ctx := context.Background()
+	if _, _, err := client.GetDetails(ctx, []string{"1", "2"}); err != nil {
+		t.Fatal(err)
+	}
+	if _, _, err := client.GetDetails(ctx, []string{"1"}); err != nil {
+		t.Fatal(err)
+	}
+	sum := tf.Summary()
+	if err := tf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	if sum.Requests != 1 || sum.Failed != 0 || sum.Misses != 2 || sum.CacheHits != 1 {
+		t.Errorf("summary = %+v; want 1 request, 2 misses, 1 hit", sum)
+	}
+	if sum.Bytes != int64(len(body)) {
+		t.Errorf("bytes = %d; want %d", sum.Bytes, len(body))
+	}
+
+	f, err := os.Open(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	kinds := map[string]int{}
+	sc := bufio.NewScanner(f)
+	for sc.Scan() {
+		var e TraceEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			t.Fatalf("line %q: %v", sc.Text(), err)
+		}
+		kinds[e.Kind]++
+		if e.Kind == TraceHTTP {
+			if strings.Contains(e.URL, "TESTKEY") || !strings.Contains(e.URL, "key=REDACTED") {
+				t.Errorf("url = %q; want the key redacted", e.URL)
+			}
+			if e.Status != http.StatusOK {
+				t.Errorf("status = %d", e.Status)
+			}
+		}
+		if e.Kind == TraceItem && e.ID != "2" {
+			t.Errorf("unavailable item = %q; want 2", e.ID)
+		}
+	}
+	if kinds[TraceHTTP] != 1 || kinds[TraceCache] != 3 || kinds[TraceItem] != 1 {
+		t.Errorf("entries by kind = %v", kinds)
+	}
+}
+
+func TestTraceFileHAR(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "trace.har")
+	tf, err := OpenTraceFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := testClient(t, func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}, WithTracer(tf))
+	client.GetDetails(context.Background(), []string{"1"})
+	if err := tf.Close(); err != nil {
+		t.Fatal(err)
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var doc struct {
+		Log struct {
+			Version string `json:"version"`
+			Entries []struct {
+				Request struct {
+					URL string `json:"url"`
+				} `json:"request"`
+				Response struct {
+					Status int `json:"status"`
+				} `json:"response"`
+			} `json:"entries"`
+			Lookups []TraceEntry `json:"_pzmod"`
+		} `json:"log"`
+	}
+	if err := json.Unmarshal(data, &doc); err != nil {
+		t.Fatal(err)
+	}
+	if doc.Log.Version != "1.2" || len(doc.Log.Entries) == 0 {
+		t.Fatalf("har = %s", data)
+	}
+	e := doc.Log.Entries[0]
+	if e.Response.Status != http.StatusForbidden || strings.Contains(e.Request.URL, "TESTKEY") {
+		t.Errorf("entry = %+v", e)
+	}
+	if len(doc.Log.Lookups) != 1 || doc.Log.Lookups[0].Cache != "miss" {
+		t.Errorf("lookups = %+v; want one cache miss", doc.Log.Lookups)
+	}
+}
+
+func TestTraceFileDropsLateEntries(t *testing.T) {
+	tf, err := OpenTraceFile(filepath.Join(t.TempDir(), "steam.ndjson"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tf.Close(); err != nil {
+		t.Fatal(err)
+	}
+	tf.Record(TraceEntry{Kind: TraceHTTP, Status: 200})
+	if s := tf.Summary(); s.Requests != 0 {
+		t.Errorf("summary = %+v; want entries after Close dropped", s)
+	}
+}
@@ -0,0 +1,55 @@
+package steam
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"time"
+)
+
+// DefaultTimeout bounds one HTTP request to the API.
+const DefaultTimeout = 30 * time.Second
+
+// Transport is how a Client reaches Steam: optionally through a proxy, and
+// trusting extra CA certificates (as a TLS-intercepting proxy needs).
+type Transport struct {
+	Proxy   string        // proxy URL; empty falls back to HTTPS_PROXY/HTTP_PROXY
+	CAFiles []string      // PEM files trusted in addition to the system roots
+	Timeout time.Duration // per request; 0 means DefaultTimeout
+}
+
+// HTTPClient builds an HTTP client for t, for use with WithHTTPClient.
+func (t Transport) HTTPClient() (*http.Client, error) {
+	tr := http.DefaultTransport.(*http.Transport).Clone()
+	if t.Proxy != "" {
+		u, err := url.Parse(t.Proxy)
+		if err != nil || u.Host == "" {
+			return nil, fmt.Errorf("invalid proxy URL %q", t.Proxy)
+		}
+		tr.Proxy = http.ProxyURL(u)
+	}
+	if len(t.CAFiles) > 0 {
+		pool, err := x509.SystemCertPool()
+		if err != nil || pool == nil {
+			pool = x509.NewCertPool()
+		}
+		for _, f := range t.CAFiles {
+			pem, err := os.ReadFile(f)
+			if err != nil {
+				return nil, fmt.Errorf("reading CA certificates: %w", err)
+			}
+			if !pool.AppendCertsFromPEM(pem) {
+				return nil, fmt.Errorf("no PEM certificates found in %s", f)
+			}
+		}
+		tr.TLSClientConfig = &tls.Config{RootCAs: pool, MinVersion: tls.VersionTLS12}
+	}
+	timeout := t.Timeout
+	if timeout <= 0 {
+		timeout = DefaultTimeout
+	}
+	return &http.Client{Transport: tr, Timeout: timeout}, nil
+}
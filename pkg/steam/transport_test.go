@@ -0,0 +1,64 @@
+package steam
+
+import (
+	"encoding/pem"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestTransportTrustsExtraCA(t *testing.T) {
+	srv := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	t.Cleanup(srv.Close)
+
+	plain, err := Transport{}.HTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := plain.Get(srv.URL); err == nil {
+		t.Fatal("self-signed server trusted without its CA")
+	}
+
+	ca := filepath.Join(t.TempDir(), "ca.pem")
+	cert := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: srv.Certificate().Raw})
+	if err := os.WriteFile(ca, cert, 0644); err != nil {
+		t.Fatal(err)
+	}
+	hc, err := Transport{CAFiles: []string{ca}}.HTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	resp, err := hc.Get(srv.URL)
+	if err != nil {
+		t.Fatalf("with CA: %v", err)
+	}
+	resp.Body.Close()
+
+	if _, err := (Transport{CAFiles: []string{filepath.Join(t.TempDir(), "none.pem")}}).HTTPClient(); err == nil {
+		t.Error("missing CA file accepted")
+	}
+}
+
+func TestTransportUsesProxy(t *testing.T) {
+	var seen string
+	proxy := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = r.URL.String() // a proxied request carries the absolute URL
+		w.Write([]byte(`{"response":{"publishedfiledetails":[{"result":1,"publishedfileid":"1"}]}}`))
+	}))
+	t.Cleanup(proxy.Close)
+
+	hc, err := Transport{Proxy: proxy.URL}.HTTPClient()
+	if err != nil {
+		t.Fatal(err)
+	}
+	client := New("KEY", WithHTTPClient(hc), WithBaseURL("http://steam.invalid"), WithRateLimiter(nil))
+	items, _, err := client.GetDetails(t.Context(), []string{"1"})
+	if err != nil || len(items) != 1 {
+		t.Fatalf("items = %v, err = %v", items, err)
+	}
+	if want := "http://steam.invalid/IPublishedFileService/GetDetails/v1/"; len(seen) < len(want) || seen[:len(want)] != want {
+		t.Errorf("proxy saw %q; want a request for %s", seen, want)
+	}
+}
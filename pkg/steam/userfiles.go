@@ -0,0 +1,38 @@
+package steam
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+)
+
+// UserFiles lists the Workshop items a user published for the configured app,
+// one 1-based page at a time (perPage defaults to 50, max 100 per the API).
+func (c *Client) UserFiles(ctx context.Context, steamID string, page, perPage int) (Page, error) {
+	if c.offline {
+		return Page{}, fmt.Errorf("author listing: %w", ErrOffline)
+	}
+	if page < 1 {
+		page = 1
+	}
+	if perPage <= 0 {
+		perPage = 50
+	}
+
+	v := c.baseQuery()
+	v.Set("steamid", steamID)
+	v.Set("appid", strconv.Itoa(c.appID))
+	v.Set("type", "myfiles")
+	v.Set("page", strconv.Itoa(page))
+	v.Set("numperpage", strconv.Itoa(perPage))
+	v.Set("return_metadata", "true")
+	v.Set("return_short_description", "true")
+	v.Set("return_children", "true")
+	v.Set("return_tags", "true")
+
+	var resp queryResponse
+	if err := c.doGet(ctx, "/IPublishedFileService/GetUserFiles/v1/", v, &resp); err != nil {
+		return Page{}, err
+	}
+	return Page{Items: resp.Response.PublishedFileDetails, Total: resp.Response.Total}, nil
+}
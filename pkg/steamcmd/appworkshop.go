@@ -0,0 +1,56 @@
+package steamcmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"github.com/kldzj/pzmod/pkg/vdf"
+)
+
+// InstalledItem is a Workshop item Steam (or steamcmd) records as downloaded
+// in steamapps/workshop/appworkshop_108600.acf.
+type InstalledItem struct {
+	ID          string
+	Size        int64 // bytes on disk
+	TimeUpdated int64 // Unix time of the Workshop revision that was downloaded
+}
+
+// ManifestPath is the appworkshop manifest for a content directory of the
+// form .../steamapps/workshop/content/108600.
+func ManifestPath(contentPath string) string {
+	workshop := filepath.Dir(filepath.Dir(filepath.Clean(contentPath)))
+	return filepath.Join(workshop, fmt.Sprintf("appworkshop_%d.acf", AppID))
+}
+
+// ReadManifest parses an appworkshop manifest into its installed items, keyed
+// by item ID.
+func ReadManifest(path string) (map[string]InstalledItem, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	doc, err := vdf.Parse(f)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	root := doc.Obj("AppWorkshop")
+	if root == nil {
+		return nil, fmt.Errorf("%s: no AppWorkshop block", path)
+	}
+
+	items := map[string]InstalledItem{}
+	for id, v := range root.Obj("WorkshopItemsInstalled") {
+		entry, ok := v.(vdf.Object)
+		if !ok {
+			continue
+		}
+		it := InstalledItem{ID: id}
+		it.Size, _ = strconv.ParseInt(entry.Get("size"), 10, 64)
+		it.TimeUpdated, _ = strconv.ParseInt(entry.Get("timeupdated"), 10, 64)
+		items[id] = it
+	}
+	return items, nil
+}
@@ -0,0 +1,165 @@
+// Package steamcmd downloads Workshop content by driving Valve's steamcmd
+// client, and reads the appworkshop manifest recording what is installed. It
+// only runs the binary and reads its files; deciding what to download and
+// checking where it landed is the service layer's job.
+package steamcmd
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+// AppID is the Steam application ID Workshop items are downloaded for
+// (Project Zomboid).
+const AppID = 108600
+
+// DefaultBinary is run when no path is configured; it is looked up on PATH.
+const DefaultBinary = "steamcmd"
+
+// ErrNotFound is returned when the steamcmd binary can't be found.
+var ErrNotFound = errors.New("steamcmd not found - install it or set its path with `pzmod steamcmd <path>`")
+
+// EventKind classifies an Event.
+type EventKind int
+
+const (
+	EventStart  EventKind = iota // steamcmd began downloading the item
+	EventDone                    // the item downloaded
+	EventFailed                  // the item failed to download
+)
+
+// Event reports progress on one item.
+type Event struct {
+	Kind  EventKind
+	ID    string
+	Path  string // where steamcmd says the item landed (EventDone)
+	Bytes int64  // item size (EventDone)
+	Err   string // steamcmd's reason (EventFailed)
+}
+
+// Runner runs steamcmd.
+type Runner struct {
+	// Binary is the steamcmd executable; empty means DefaultBinary on PATH.
+	Binary string
+	// InstallDir is passed as +force_install_dir, so items land under
+	// InstallDir/steamapps/workshop/content/108600. Empty leaves steamcmd's own
+	// default.
+	InstallDir string
+	// Login is the Steam account to log in as; empty means anonymous, which
+	// works for Project Zomboid Workshop items.
+	Login string
+}
+
+// Args are the steamcmd arguments to download ids.
+func (r Runner) Args(ids []string) []string {
+	var args []string
+	if r.InstallDir != "" {
+		args = append(args, "+force_install_dir", r.InstallDir)
+	}
+	login := r.Login
+	if login == "" {
+		login = "anonymous"
+	}
+	args = append(args, "+login", login)
+	for _, id := range ids {
+		args = append(args, "+workshop_download_item", strconv.Itoa(AppID), id)
+	}
+	return append(args, "+quit")
+}
+
+// Download fetches ids in one steamcmd session, calling progress (which may
+// be nil) as each item starts, finishes, or fails. It returns the items that
+// finished; items steamcmd never reported on are neither finished nor failed,
+// and the caller should treat them as failed. The error is for steamcmd
+// itself failing to run, not for individual items.
+func (r Runner) Download(ctx context.Context, ids []string, progress func(Event)) (map[string]Event, error) {
+	bin := r.Binary
+	if bin == "" {
+		bin = DefaultBinary
+	}
+	path, err := exec.LookPath(bin)
+	if err != nil {
+		return nil, fmt.Errorf("%w (%s)", ErrNotFound, bin)
+	}
+	cmd := exec.CommandContext(ctx, path, r.Args(ids)...)
+	out, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	var stderr strings.Builder
+	cmd.Stderr = &stderr
+	if err := cmd.Start(); err != nil {
+		return nil, err
+	}
+
+	done := map[string]Event{}
+	Parse(out, func(e Event) {
+		if e.Kind == EventDone {
+			done[e.ID] = e
+		}
+		if progress != nil {
+			progress(e)
+		}
+	})
+	if err := cmd.Wait(); err != nil && len(done) == 0 {
+		if ctx.Err() != nil {
+			return done, ctx.Err()
+		}
+		msg := strings.TrimSpace(stderr.String())
+		if msg == "" {
+			return done, fmt.Errorf("steamcmd: %w", err)
+		}
+		return done, fmt.Errorf("steamcmd: %w: %s", err, msg)
+	}
+	return done, nil
+}
+
+var (
+	startRe  = regexp.MustCompile(`Downloading item (\d+)`)
+	doneRe   = regexp.MustCompile(`Success\. Downloaded item (\d+) to "([^"]+)"(?: \((\d+) bytes\))?`)
+	failedRe = regexp.MustCompile(`ERROR! Download item (\d+) failed \(([^)]*)\)`)
+)
+
+// Parse reads steamcmd output and reports the item events in it. steamcmd
+// sometimes ends progress lines with a bare carriage return, so those split
+// lines too.
+func Parse(r io.Reader, emit func(Event)) {
+	sc := bufio.NewScanner(r)
+	sc.Buffer(make([]byte, 64*1024), 1024*1024)
+	sc.Split(scanLines)
+	for sc.Scan() {
+		line := sc.Text()
+		switch {
+		case doneRe.MatchString(line):
+			m := doneRe.FindStringSubmatch(line)
+			n, _ := strconv.ParseInt(m[3], 10, 64)
+			emit(Event{Kind: EventDone, ID: m[1], Path: filepath.Clean(m[2]), Bytes: n})
+		case failedRe.MatchString(line):
+			m := failedRe.FindStringSubmatch(line)
+			emit(Event{Kind: EventFailed, ID: m[1], Err: m[2]})
+		case startRe.MatchString(line):
+			emit(Event{Kind: EventStart, ID: startRe.FindStringSubmatch(line)[1]})
+		}
+	}
+}
+
+// scanLines is bufio.ScanLines that also breaks on a lone '\r'.
+func scanLines(data []byte, atEOF bool) (int, []byte, error) {
+	for i, b := range data {
+		if b == '\n' || b == '\r' {
+			return i + 1, data[:i], nil
+		}
+	}
+	if atEOF && len(data) > 0 {
+		return len(data), data, nil
+	}
+	return 0, nil, nil
+}
@@ -0,0 +1,36 @@
+package steamcmd
+
+import (
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestArgs(t *testing.T) {
+	got := Runner{InstallDir: "/srv/pz"}.Args([]string{"1", "2"})
+	want := []string{"+force_install_dir", "/srv/pz", "+login", "anonymous",
+		"+workshop_download_item", "108600", "1", "+workshop_download_item", "108600", "2", "+quit"}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("args = %v; want %v", got, want)
+	}
+}
+
+func TestParse(t *testing.T) {
+	out := "Redirecting stderr to '/home/steam/Steam/logs/stderr.txt'\n" +
+		"Logging in user 'anonymous' to Steam Public...OK\r" +
+		"Downloading item 2392709985 ...\r" +
+		"Success. Downloaded item 2392709985 to \"/srv/pz/steamapps/workshop/content/108600/2392709985\" (1523312 bytes) \n" +
+		"Downloading item 666 ...\n" +
+		"ERROR! Download item 666 failed (File Not Found).\n"
+	var got []Event
+	Parse(strings.NewReader(out), func(e Event) { got = append(got, e) })
+	want := []Event{
+		{Kind: EventStart, ID: "2392709985"},
+		{Kind: EventDone, ID: "2392709985", Path: "/srv/pz/steamapps/workshop/content/108600/2392709985", Bytes: 1523312},
+		{Kind: EventStart, ID: "666"},
+		{Kind: EventFailed, ID: "666", Err: "File Not Found"},
+	}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("events = %+v\nwant %+v", got, want)
+	}
+}
@@ -0,0 +1,440 @@
+package store
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/pbkdf2"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"sort"
+)
+
+var (
+	// ErrPassphraseRequired is returned when importing an encrypted archive
+	// without a passphrase.
+	ErrPassphraseRequired = errors.New("backup archive is encrypted; a passphrase is required")
+	// ErrBadPassphrase is returned when an archive cannot be decrypted.
+	ErrBadPassphrase = errors.New("wrong passphrase or corrupted backup archive")
+)
+
+// archiveVersion is the manifest format version written by ExportBackups.
+const archiveVersion = 1
+
+// Encrypted archives are "PZMODENC" + version byte, a PBKDF2 salt, an AES-GCM
+// nonce, then the sealed tar.gz. The whole archive is encrypted, so the
+// Password= values inside the snapshots are never readable at rest.
+const (
+	encMagic      = "PZMODENC\x01"
+	encSaltSize   = 16
+	encIterations = 600_000
+)
+
+// ArchiveManifest describes an exported backup archive.
+type ArchiveManifest struct {
+	Version    int     `json:"version"`
+	ExportedAt string  `json:"exported_at"` // RFC3339 UTC
+	Profile    Profile `json:"profile"`
+}
+
+// BackupArchive is a parsed export: the manifest, the source index, the
+// snapshot bytes keyed by entry ID, and any archived Workshop content (content
+// manifests keyed by entry ID, objects by SHA256).
+type BackupArchive struct {
+	Manifest ArchiveManifest
+	Entries  []BackupEntry
+	data     map[string][]byte
+	content  map[string]ContentManifest
+	objects  map[string][]byte
+}
+
+// ImportResult reports what ImportBackups merged.
+type ImportResult struct {
+	Imported []BackupEntry // newly stored entries
+	Skipped  int           // entries whose SHA256 was already present
+}
+
+// ExportBackups writes a profile's snapshots, their index, and the profile
+// metadata as a tar.gz to w, along with any Workshop content archived with
+// the snapshots (each manifest, and every object they reference once). A
+// non-empty passphrase encrypts the archive. The Steam API key and any proxy
+// credentials are never included. It returns the number of snapshots written.
+func (s *Store) ExportBackups(p Profile, w io.Writer, passphrase string) (int, error) {
+	entries, err := s.loadBackupIndex(p.ID)
+	if err != nil {
+		return 0, err
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+
+	var buf bytes.Buffer
+	gz := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gz)
+	add := func(name string, data []byte) error {
+		if err := tw.WriteHeader(&tar.Header{Name: name, Mode: 0644, Size: int64(len(data)), ModTime: s.now()}); err != nil {
+			return err
+		}
+		_, err := tw.Write(data)
+		return err
+	}
+
+	if p.Network != nil {
+		n := p.Network.WithoutCredentials()
+		p.Network = &n
+	}
+	manifest, err := json.MarshalIndent(ArchiveManifest{
+		Version:    archiveVersion,
+		ExportedAt: s.now().UTC().Format("2006-01-02T15:04:05Z07:00"),
+		Profile:    p,
+	}, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := add("manifest.json", manifest); err != nil {
+		return 0, err
+	}
+	index, err := json.MarshalIndent(entries, "", "  ")
+	if err != nil {
+		return 0, err
+	}
+	if err := add("index.json", index); err != nil {
+		return 0, err
+	}
+	for _, e := range entries {
+		data, err := os.ReadFile(filepath.Join(s.profileBackupDir(p.ID), e.File))
+		if err != nil {
+			return 0, err
+		}
+		if err := add("snapshots/"+e.File, data); err != nil {
+			return 0, err
+		}
+	}
+	written := map[string]bool{}
+	for _, e := range entries {
+		if e.Content == "" {
+			continue
+		}
+		m, err := s.BackupContent(p.ID, e.ID)
+		if err != nil {
+			return 0, fmt.Errorf("snapshot %s: reading archived content: %w", e.ID, err)
+		}
+		data, err := json.Marshal(m)
+		if err != nil {
+			return 0, err
+		}
+		if err := add("content/"+e.ID+".content.json", data); err != nil {
+			return 0, err
+		}
+		for _, files := range m.Items {
+			for _, f := range files {
+				if written[f.SHA256] {
+					continue
+				}
+				obj, err := os.ReadFile(s.objectPath(f.SHA256))
+				if err != nil {
+					return 0, fmt.Errorf("snapshot %s: %s is missing from the content store", e.ID, f.Path)
+				}
+				if err := add("objects/"+f.SHA256, obj); err != nil {
+					return 0, err
+				}
+				written[f.SHA256] = true
+			}
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return 0, err
+	}
+	if err := gz.Close(); err != nil {
+		return 0, err
+	}
+
+	out := buf.Bytes()
+	if passphrase != "" {
+		if out, err = sealArchive(out, passphrase); err != nil {
+			return 0, err
+		}
+	}
+	if _, err := w.Write(out); err != nil {
+		return 0, err
+	}
+	return len(entries), nil
+}
+
+// ReadBackupArchive parses an archive written by ExportBackups, decrypting it
+// with passphrase when needed. Every snapshot is checked against its recorded
+// SHA256.
+func ReadBackupArchive(r io.Reader, passphrase string) (*BackupArchive, error) {
+	raw, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if bytes.HasPrefix(raw, []byte(encMagic)) {
+		if passphrase == "" {
+			return nil, ErrPassphraseRequired
+		}
+		if raw, err = openArchive(raw, passphrase); err != nil {
+			return nil, err
+		}
+	}
+
+	gz, err := gzip.NewReader(bytes.NewReader(raw))
+	if err != nil {
+		return nil, fmt.Errorf("not a pzmod backup archive: %w", err)
+	}
+	defer gz.Close()
+
+	a := &BackupArchive{data: map[string][]byte{}, content: map[string]ContentManifest{}, objects: map[string][]byte{}}
+	files := map[string][]byte{}
+	manifests := map[string][]byte{}
+	var haveManifest bool
+	tr := tar.NewReader(gz)
+	for {
+		hdr, err := tr.Next()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+		data, err := io.ReadAll(tr)
+		if err != nil {
+			return nil, err
+		}
+		switch name := path.Clean(hdr.Name); name {
+		case "manifest.json":
+			if err := json.Unmarshal(data, &a.Manifest); err != nil {
+				return nil, fmt.Errorf("bad archive manifest: %w", err)
+			}
+			haveManifest = true
+		case "index.json":
+			if err := json.Unmarshal(data, &a.Entries); err != nil {
+				return nil, fmt.Errorf("bad archive index: %w", err)
+			}
+		default:
+			switch dir, file := path.Split(name); dir {
+			case "snapshots/":
+				files[file] = data
+			case "content/":
+				manifests[file] = data
+			case "objects/":
+				sum := sha256.Sum256(data)
+				if hex.EncodeToString(sum[:]) != file {
+					return nil, fmt.Errorf("content object %s does not match its checksum", file)
+				}
+				a.objects[file] = data
+			}
+		}
+	}
+	if !haveManifest {
+		return nil, errors.New("not a pzmod backup archive: manifest.json missing")
+	}
+	if a.Manifest.Version > archiveVersion {
+		return nil, fmt.Errorf("backup archive version %d is newer than this pzmod supports", a.Manifest.Version)
+	}
+	if id := a.Manifest.Profile.ID; id != "" && !validStem(id) {
+		return nil, fmt.Errorf("backup archive has an invalid profile ID %q", id)
+	}
+
+	for _, e := range a.Entries {
+		if !validStem(e.ID) {
+			return nil, fmt.Errorf("backup archive has an invalid snapshot ID %q", e.ID)
+		}
+		data, ok := files[e.File]
+		if !ok {
+			return nil, fmt.Errorf("backup archive is missing snapshot %s", e.File)
+		}
+		sum := sha256.Sum256(data)
+		if hex.EncodeToString(sum[:]) != e.SHA256 {
+			return nil, fmt.Errorf("snapshot %s does not match its recorded checksum", e.ID)
+		}
+		a.data[e.ID] = data
+		if raw, ok := manifests[e.ID+".content.json"]; ok && e.Content != "" {
+			var m ContentManifest
+			if err := json.Unmarshal(raw, &m); err != nil {
+				return nil, fmt.Errorf("bad content manifest for snapshot %s: %w", e.ID, err)
+			}
+			if err := m.validate(); err != nil {
+				return nil, fmt.Errorf("snapshot %s: %w", e.ID, err)
+			}
+			a.content[e.ID] = m
+		}
+	}
+	return a, nil
+}
+
+// hasContent reports whether the archive carries the content manifest of
+// snapshot id and every object it references.
+func (a *BackupArchive) hasContent(id string) bool {
+	m, ok := a.content[id]
+	if !ok {
+		return false
+	}
+	for _, files := range m.Items {
+		for _, f := range files {
+			if _, ok := a.objects[f.SHA256]; !ok {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+// ImportBackups merges an archive's snapshots into profileID's backup store.
+// Snapshots whose SHA256 is already present are skipped, so importing the same
+// archive twice is a no-op. IDs that collide with different content get a
+// unique suffix; kind, note, pin, and timestamp are kept. Archived Workshop
+// content comes along when the archive carries it; otherwise the imported
+// snapshot is recorded as having none.
+func (s *Store) ImportBackups(profileID string, a *BackupArchive) (ImportResult, error) {
+	var res ImportResult
+	if !validStem(profileID) {
+		return res, fmt.Errorf("invalid profile ID %q", profileID)
+	}
+	dir := s.profileBackupDir(profileID)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return res, err
+	}
+	entries, err := s.loadBackupIndex(profileID)
+	if err != nil {
+		return res, err
+	}
+	have := make(map[string]bool, len(entries))
+	for _, e := range entries {
+		have[e.SHA256] = true
+	}
+
+	for _, e := range a.Entries {
+		if have[e.SHA256] {
+			res.Skipped++
+			continue
+		}
+		data := a.data[e.ID]
+		srcID := e.ID
+		e.ID = uniqueStem(e.ID, entries)
+		e.File = e.ID + ".ini"
+		if err := os.WriteFile(filepath.Join(dir, e.File), data, 0644); err != nil {
+			return res, err
+		}
+		if e.Content != "" && a.hasContent(srcID) {
+			if err := s.importContent(dir, &e, a.content[srcID], a.objects); err != nil {
+				return res, err
+			}
+		} else {
+			e.Content, e.ContentItems, e.ContentSize = "", 0, 0
+		}
+		entries = append(entries, e)
+		have[e.SHA256] = true
+		res.Imported = append(res.Imported, e)
+	}
+	if len(res.Imported) == 0 {
+		return res, nil
+	}
+	sort.Slice(entries, func(i, j int) bool { return entries[i].ID < entries[j].ID })
+	return res, s.saveBackupIndex(profileID, entries)
+}
+
+// importContent stores a content manifest's objects and writes the manifest
+// under e's (possibly renamed) ID.
+func (s *Store) importContent(dir string, e *BackupEntry, m ContentManifest, objects map[string][]byte) error {
+	if err := m.validate(); err != nil {
+		return err
+	}
+	for _, files := range m.Items {
+		for _, f := range files {
+			obj := s.objectPath(f.SHA256)
+			if _, err := os.Stat(obj); err == nil {
+				continue
+			}
+			if err := os.MkdirAll(filepath.Dir(obj), 0755); err != nil {
+				return err
+			}
+			if err := os.WriteFile(obj, objects[f.SHA256], 0644); err != nil {
+				return err
+			}
+		}
+	}
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return err
+	}
+	e.Content = e.ID + ".content.json"
+	e.ContentItems = len(m.Items)
+	e.ContentSize = m.Size()
+	return os.WriteFile(filepath.Join(dir, e.Content), data, 0644)
+}
+
+// validStem reports whether id is safe to use as a backup file stem: the
+// timestamp alphabet only, so an archive cannot write outside the backup dir.
+func validStem(id string) bool {
+	if id == "" || id[0] == '.' {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= '0' && r <= '9', r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r == '-', r == '.', r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+func deriveKey(passphrase string, salt []byte) (cipher.AEAD, error) {
+	key, err := pbkdf2.Key(sha256.New, passphrase, salt, encIterations, 32)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	return cipher.NewGCM(block)
+}
+
+func sealArchive(plain []byte, passphrase string) ([]byte, error) {
+	salt := make([]byte, encSaltSize)
+	if _, err := rand.Read(salt); err != nil {
+		return nil, err
+	}
+	gcm, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, err
+	}
+	out := append([]byte(encMagic), salt...)
+	out = append(out, nonce...)
+	return gcm.Seal(out, nonce, plain, []byte(encMagic)), nil
+}
+
+func openArchive(sealed []byte, passphrase string) ([]byte, error) {
+	body := sealed[len(encMagic):]
+	if len(body) < encSaltSize {
+		return nil, ErrBadPassphrase
+	}
+	salt, body := body[:encSaltSize], body[encSaltSize:]
+	gcm, err := deriveKey(passphrase, salt)
+	if err != nil {
+		return nil, err
+	}
+	if len(body) < gcm.NonceSize() {
+		return nil, ErrBadPassphrase
+	}
+	nonce, body := body[:gcm.NonceSize()], body[gcm.NonceSize():]
+	plain, err := gcm.Open(nil, nonce, body, []byte(encMagic))
+	if err != nil {
+		return nil, ErrBadPassphrase
+	}
+	return plain, nil
+}
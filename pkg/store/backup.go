@@ -18,7 +18,14 @@ type BackupEntry struct {
 	SHA256    string `json:"sha256"`
 	Size      int64  `json:"size"`
 	Note      string `json:"note,omitempty"`
-	Kind      string `json:"kind"` // "auto" | "manual" | "pre-restore"
+	Kind      string `json:"kind"`             // "auto" | "manual" | "pre-restore"
+	Pinned    bool   `json:"pinned,omitempty"` // never pruned (see PinBackup)
+
+	// Content names the manifest of Workshop content archived with this
+	// snapshot (see ArchiveContent); empty when none was.
+	Content      string `json:"content,omitempty"`
+	ContentItems int    `json:"content_items,omitempty"`
+	ContentSize  int64  `json:"content_size,omitempty"`
 }
 
 // DefaultBackupRetention is used when a profile sets no explicit retention.
@@ -151,28 +158,35 @@ func (s *Store) DeleteBackup(profileID, backupID string) error {
 	if idx < 0 {
 		return ErrNoBackup
 	}
-	_ = os.Remove(filepath.Join(s.profileBackupDir(profileID), entries[idx].File))
+	removed := entries[idx]
+	s.removeBackupFiles(profileID, removed)
 	entries = append(entries[:idx], entries[idx+1:]...)
-	return s.saveBackupIndex(profileID, entries)
+	if err := s.saveBackupIndex(profileID, entries); err != nil {
+		return err
+	}
+	if removed.Content != "" {
+		return s.collectContent()
+	}
+	return nil
 }
 
-// Prune keeps the newest keep snapshots and deletes the rest. keep <= 0 uses
-// DefaultBackupRetention.
+// removeBackupFiles deletes a snapshot's config copy and content manifest.
+func (s *Store) removeBackupFiles(profileID string, e BackupEntry) {
+	dir := s.profileBackupDir(profileID)
+	_ = os.Remove(filepath.Join(dir, e.File))
+	if e.Content != "" {
+		_ = os.Remove(filepath.Join(dir, e.Content))
+	}
+}
+
+// Prune keeps the newest keep unpinned snapshots (plus every pinned one) and
+// deletes the rest. keep <= 0 uses DefaultBackupRetention.
 func (s *Store) Prune(profileID string, keep int) error {
 	if keep <= 0 {
 		keep = DefaultBackupRetention
 	}
-	entries, err := s.Backups(profileID) // newest first
-	if err != nil {
-		return err
-	}
-	if len(entries) <= keep {
-		return nil
-	}
-	for _, e := range entries[keep:] {
-		_ = os.Remove(filepath.Join(s.profileBackupDir(profileID), e.File))
-	}
-	return s.saveBackupIndex(profileID, entries[:keep])
+	_, err := s.PruneWithPolicy(profileID, RetentionPolicy{KeepLast: keep})
+	return err
 }
 
 func (s *Store) findBackup(profileID, backupID string) (BackupEntry, error) {
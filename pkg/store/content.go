@@ -0,0 +1,392 @@
+package store
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// ErrNoContent is returned when a backup has no archived Workshop content.
+var ErrNoContent = errors.New("backup has no archived Workshop content")
+
+// ContentFile is one file of an archived Workshop item.
+type ContentFile struct {
+	Path   string      `json:"path"` // slash-separated, relative to the item folder
+	SHA256 string      `json:"sha256"`
+	Size   int64       `json:"size"`
+	Mode   fs.FileMode `json:"mode"`
+}
+
+// ContentManifest lists the Workshop item folders archived with a backup.
+// The files themselves live once each in the content-addressed object store
+// under the config root, so unchanged mods cost nothing per snapshot.
+type ContentManifest struct {
+	Root    string                   `json:"root"`              // the WorkshopContentPath archived from
+	Items   map[string][]ContentFile `json:"items"`             // item ID -> files
+	Missing []string                 `json:"missing,omitempty"` // requested items that weren't downloaded
+}
+
+// Size is the total size of the archived files.
+func (m ContentManifest) Size() int64 {
+	var n int64
+	for _, files := range m.Items {
+		for _, f := range files {
+			n += f.Size
+		}
+	}
+	return n
+}
+
+// validate checks that every item ID is a numeric Workshop ID and every object
+// a SHA256, since both become paths: a manifest from an imported archive is
+// not trusted.
+func (m ContentManifest) validate() error {
+	for id, files := range m.Items {
+		if !validItemID(id) {
+			return fmt.Errorf("invalid Workshop item ID %q in content manifest", id)
+		}
+		for _, f := range files {
+			if !validSum(f.SHA256) {
+				return fmt.Errorf("item %s: invalid checksum %q in content manifest", id, f.SHA256)
+			}
+		}
+	}
+	return nil
+}
+
+// validItemID reports whether id is a numeric Workshop ID, the only name an
+// item folder may have.
+func validItemID(id string) bool {
+	_, err := strconv.ParseUint(id, 10, 64)
+	return err == nil
+}
+
+// validSum reports whether sum is a lowercase hex SHA256.
+func validSum(sum string) bool {
+	if len(sum) != sha256.Size*2 {
+		return false
+	}
+	_, err := hex.DecodeString(sum)
+	return err == nil && strings.ToLower(sum) == sum
+}
+
+func (s *Store) contentRoot() string   { return filepath.Join(s.root, "content") }
+func (s *Store) hashCachePath() string { return filepath.Join(s.contentRoot(), "hashes.json") }
+func (s *Store) objectPath(sum string) string {
+	return filepath.Join(s.contentRoot(), "objects", sum[:2], sum)
+}
+
+// hashCache remembers file hashes by path, size, and mtime, so archiving
+// gigabytes of unchanged content doesn't re-read it every snapshot.
+type hashCache map[string]hashRecord
+
+type hashRecord struct {
+	Size    int64  `json:"size"`
+	ModTime int64  `json:"mtime"`
+	SHA256  string `json:"sha256"`
+}
+
+func (s *Store) loadHashCache() hashCache {
+	hc := hashCache{}
+	if data, err := os.ReadFile(s.hashCachePath()); err == nil {
+		_ = json.Unmarshal(data, &hc) // a corrupt cache just means rehashing
+	}
+	return hc
+}
+
+func (s *Store) saveHashCache(hc hashCache) error {
+	data, err := json.Marshal(hc)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(s.hashCachePath(), data, 0644)
+}
+
+// ArchiveContent copies the folders of the given Workshop items under root
+// into the object store and attaches the manifest to a backup. Items not
+// downloaded are recorded as missing.
+func (s *Store) ArchiveContent(profileID, backupID, root string, ids []string) (ContentManifest, error) {
+	entries, err := s.loadBackupIndex(profileID)
+	if err != nil {
+		return ContentManifest{}, err
+	}
+	idx := -1
+	for i, e := range entries {
+		if e.ID == backupID {
+			idx = i
+		}
+	}
+	if idx < 0 {
+		return ContentManifest{}, ErrNoBackup
+	}
+	if err := os.MkdirAll(s.contentRoot(), 0755); err != nil {
+		return ContentManifest{}, err
+	}
+
+	hc := s.loadHashCache()
+	m := ContentManifest{Root: root, Items: map[string][]ContentFile{}}
+	for _, id := range ids {
+		dir := filepath.Join(root, id)
+		if info, err := os.Stat(dir); err != nil || !info.IsDir() {
+			m.Missing = append(m.Missing, id)
+			continue
+		}
+		files, err := s.archiveDir(dir, hc)
+		if err != nil {
+			return m, fmt.Errorf("archiving item %s: %w", id, err)
+		}
+		m.Items[id] = files
+	}
+	if err := s.saveHashCache(hc); err != nil {
+		return m, err
+	}
+
+	data, err := json.MarshalIndent(m, "", "  ")
+	if err != nil {
+		return m, err
+	}
+	entry := &entries[idx]
+	entry.Content = entry.ID + ".content.json"
+	entry.ContentItems = len(m.Items)
+	entry.ContentSize = m.Size()
+	if err := os.WriteFile(filepath.Join(s.profileBackupDir(profileID), entry.Content), data, 0644); err != nil {
+		return m, err
+	}
+	return m, s.saveBackupIndex(profileID, entries)
+}
+
+// archiveDir stores every regular file under dir and returns their records,
+// sorted by path.
+func (s *Store) archiveDir(dir string, hc hashCache) ([]ContentFile, error) {
+	var files []ContentFile
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		sum, err := s.storeObject(path, info, hc)
+		if err != nil {
+			return err
+		}
+		files = append(files, ContentFile{Path: filepath.ToSlash(rel), SHA256: sum, Size: info.Size(), Mode: info.Mode().Perm()})
+		return nil
+	})
+	sort.Slice(files, func(i, j int) bool { return files[i].Path < files[j].Path })
+	return files, err
+}
+
+// storeObject makes sure the object store holds path's content and returns
+// its hash. A file whose size and mtime match the cache, and whose object
+// exists, isn't read again.
+func (s *Store) storeObject(path string, info fs.FileInfo, hc hashCache) (string, error) {
+	if rec, ok := hc[path]; ok && rec.Size == info.Size() && rec.ModTime == info.ModTime().UnixNano() {
+		if _, err := os.Stat(s.objectPath(rec.SHA256)); err == nil {
+			return rec.SHA256, nil
+		}
+	}
+
+	src, err := os.Open(path)
+	if err != nil {
+		return "", err
+	}
+	defer src.Close()
+	tmp, err := os.CreateTemp(s.contentRoot(), "incoming-*")
+	if err != nil {
+		return "", err
+	}
+	defer os.Remove(tmp.Name()) // a no-op once renamed
+	h := sha256.New()
+	if _, err := io.Copy(io.MultiWriter(tmp, h), src); err != nil {
+		tmp.Close()
+		return "", err
+	}
+	if err := tmp.Close(); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+	hc[path] = hashRecord{Size: info.Size(), ModTime: info.ModTime().UnixNano(), SHA256: sum}
+
+	obj := s.objectPath(sum)
+	if _, err := os.Stat(obj); err == nil {
+		return sum, nil // already stored
+	}
+	if err := os.MkdirAll(filepath.Dir(obj), 0755); err != nil {
+		return "", err
+	}
+	return sum, os.Rename(tmp.Name(), obj)
+}
+
+// BackupContent returns the content manifest archived with a backup, or
+// ErrNoContent.
+func (s *Store) BackupContent(profileID, backupID string) (ContentManifest, error) {
+	entry, err := s.findBackup(profileID, backupID)
+	if err != nil {
+		return ContentManifest{}, err
+	}
+	if entry.Content == "" {
+		return ContentManifest{}, ErrNoContent
+	}
+	var m ContentManifest
+	data, err := os.ReadFile(filepath.Join(s.profileBackupDir(profileID), entry.Content))
+	if err != nil {
+		return m, err
+	}
+	return m, json.Unmarshal(data, &m)
+}
+
+// RestoreContent puts back the item folders archived with a backup under root
+// (normally the profile's WorkshopContentPath), replacing what is there.
+// Folders for other items are left alone. It returns the restored item IDs.
+func (s *Store) RestoreContent(profileID, backupID, root string) ([]string, error) {
+	m, err := s.BackupContent(profileID, backupID)
+	if err != nil {
+		return nil, err
+	}
+	if err := m.validate(); err != nil {
+		return nil, err
+	}
+	// Check every object first, so a damaged store fails before anything on
+	// disk is touched.
+	for id, files := range m.Items {
+		for _, f := range files {
+			if _, err := os.Stat(s.objectPath(f.SHA256)); err != nil {
+				return nil, fmt.Errorf("item %s: %s is missing from the content store", id, f.Path)
+			}
+		}
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+
+	ids := make([]string, 0, len(m.Items))
+	for id := range m.Items {
+		ids = append(ids, id)
+	}
+	sort.Strings(ids)
+	for i, id := range ids {
+		if err := s.restoreItem(root, id, m.Items[id]); err != nil {
+			return ids[:i], fmt.Errorf("restoring item %s: %w", id, err)
+		}
+	}
+	return ids, nil
+}
+
+// restoreItem rebuilds one item folder beside the live one, then swaps it in.
+func (s *Store) restoreItem(root, id string, files []ContentFile) error {
+	if !validItemID(id) {
+		return fmt.Errorf("invalid Workshop item ID %q", id)
+	}
+	staging := filepath.Join(root, ".pzmod-restore-"+id)
+	if err := os.RemoveAll(staging); err != nil {
+		return err
+	}
+	for _, f := range files {
+		dst := filepath.Join(staging, filepath.FromSlash(f.Path))
+		if !strings.HasPrefix(dst, staging+string(filepath.Separator)) {
+			return fmt.Errorf("unsafe path %q in content manifest", f.Path)
+		}
+		if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+			return err
+		}
+		if err := copyFile(s.objectPath(f.SHA256), dst, f.Mode|0200); err != nil {
+			return err
+		}
+	}
+	if len(files) == 0 {
+		if err := os.MkdirAll(staging, 0755); err != nil {
+			return err
+		}
+	}
+	live := filepath.Join(root, id)
+	if err := os.RemoveAll(live); err != nil {
+		return err
+	}
+	return os.Rename(staging, live)
+}
+
+func copyFile(src, dst string, mode fs.FileMode) error {
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.OpenFile(dst, os.O_CREATE|os.O_WRONLY|os.O_TRUNC, mode)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
+
+// collectContent deletes objects no backup of any profile references any
+// more. It runs after backups are pruned or deleted. A backup whose content
+// manifest has gone missing references nothing; it can't be restored anyway,
+// and failing here would block every later snapshot.
+func (s *Store) collectContent() error {
+	objects := filepath.Join(s.contentRoot(), "objects")
+	if _, err := os.Stat(objects); err != nil {
+		return nil // nothing was ever archived
+	}
+	used := map[string]bool{}
+	profiles, err := os.ReadDir(s.backupsRoot())
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	for _, p := range profiles {
+		if !p.IsDir() {
+			continue
+		}
+		entries, err := s.loadBackupIndex(p.Name())
+		if err != nil {
+			return err // never collect on a partial view
+		}
+		for _, e := range entries {
+			if e.Content == "" {
+				continue
+			}
+			m, err := s.BackupContent(p.Name(), e.ID)
+			if errors.Is(err, fs.ErrNotExist) {
+				continue
+			}
+			if err != nil {
+				return err
+			}
+			for _, files := range m.Items {
+				for _, f := range files {
+					used[f.SHA256] = true
+				}
+			}
+		}
+	}
+	return filepath.WalkDir(objects, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		if !used[d.Name()] {
+			return os.Remove(path)
+		}
+		return nil
+	})
+}
@@ -37,7 +37,16 @@ func (s *Store) saveCredentials(c credentials) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.credentialsPath(), data, 0600)
+	return writePrivate(s.credentialsPath(), data)
+}
+
+// writePrivate writes a file only the user can read, tightening the mode of a
+// file created before it held secrets.
+func writePrivate(path string, data []byte) error {
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return err
+	}
+	return os.Chmod(path, 0600)
 }
 
 // APIKey resolves the key for a profile, in order: a per-profile override, the
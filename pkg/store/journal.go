@@ -0,0 +1,102 @@
+package store
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// JournalDelta counts how one list changed in a journaled operation.
+type JournalDelta struct {
+	Added     int  `json:"added,omitempty"`
+	Removed   int  `json:"removed,omitempty"`
+	Reordered bool `json:"reordered,omitempty"`
+}
+
+// JournalSummary is what an operation changed in the config.
+type JournalSummary struct {
+	Mods          JournalDelta `json:"mods"`
+	WorkshopItems JournalDelta `json:"workshop_items"`
+	Maps          JournalDelta `json:"maps"`
+	ChangedFields []string     `json:"changed_fields,omitempty"`
+}
+
+// JournalEntry records one operation that wrote a profile's config.
+type JournalEntry struct {
+	ID        int            `json:"id"`        // 1-based, increasing per profile
+	Timestamp string         `json:"timestamp"` // RFC3339 UTC
+	Actor     string         `json:"actor"`     // user@host that ran it
+	Command   string         `json:"command"`   // e.g. "mods add"
+	Args      []string       `json:"args,omitempty"`
+	BackupID  string         `json:"backup_id,omitempty"` // snapshot taken just before
+	Summary   JournalSummary `json:"summary"`
+	SHA256    string         `json:"sha256"`           // config bytes after the operation
+	Undoes    []int          `json:"undoes,omitempty"` // entries this undo reverted
+}
+
+// IsUndo reports whether the entry is itself an undo.
+func (e JournalEntry) IsUndo() bool { return len(e.Undoes) > 0 }
+
+func (s *Store) journalPath(profileID string) string {
+	return filepath.Join(s.journalRoot(), profileID+".jsonl")
+}
+
+// Journal returns a profile's operation journal, oldest first. The journal is
+// append-only NDJSON, so concurrent admins never rewrite each other's entries.
+func (s *Store) Journal(profileID string) ([]JournalEntry, error) {
+	f, err := os.Open(s.journalPath(profileID))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	var out []JournalEntry
+	sc := bufio.NewScanner(f)
+	sc.Buffer(make([]byte, 0, 64*1024), 4*1024*1024)
+	for line := 1; sc.Scan(); line++ {
+		if len(sc.Bytes()) == 0 {
+			continue
+		}
+		var e JournalEntry
+		if err := json.Unmarshal(sc.Bytes(), &e); err != nil {
+			return nil, fmt.Errorf("journal line %d: %w", line, err)
+		}
+		out = append(out, e)
+	}
+	return out, sc.Err()
+}
+
+// AppendJournal assigns the next ID and timestamp to e and appends it to the
+// profile's journal.
+func (s *Store) AppendJournal(profileID string, e JournalEntry) (JournalEntry, error) {
+	entries, err := s.Journal(profileID)
+	if err != nil {
+		return e, err
+	}
+	e.ID = 1
+	if n := len(entries); n > 0 {
+		e.ID = entries[n-1].ID + 1
+	}
+	e.Timestamp = s.now().UTC().Format("2006-01-02T15:04:05Z07:00")
+	data, err := json.Marshal(e)
+	if err != nil {
+		return e, err
+	}
+	if err := os.MkdirAll(s.journalRoot(), 0755); err != nil {
+		return e, err
+	}
+	f, err := os.OpenFile(s.journalPath(profileID), os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return e, err
+	}
+	if _, err := f.Write(append(data, '\n')); err != nil {
+		f.Close()
+		return e, err
+	}
+	return e, f.Close()
+}
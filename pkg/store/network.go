@@ -0,0 +1,170 @@
+package store
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Network holds how pzmod reaches the Steam Web API. It can be set globally
+// and per profile; a profile's non-empty fields override the global ones.
+type Network struct {
+	Proxy   string   `json:"proxy,omitempty"`    // http, https, or socks5 URL
+	CAFiles []string `json:"ca_files,omitempty"` // extra PEM CA certificates
+	Timeout string   `json:"timeout,omitempty"`  // per request, as a Go duration ("45s")
+	BaseURL string   `json:"base_url,omitempty"` // Steam Web API root (a mirror or test server)
+}
+
+// IsZero reports whether n sets nothing.
+func (n Network) IsZero() bool {
+	return n.Proxy == "" && len(n.CAFiles) == 0 && n.Timeout == "" && n.BaseURL == ""
+}
+
+// Over returns n with its unset fields taken from base.
+func (n Network) Over(base Network) Network {
+	if n.Proxy == "" {
+		n.Proxy = base.Proxy
+	}
+	if len(n.CAFiles) == 0 {
+		n.CAFiles = base.CAFiles
+	}
+	if n.Timeout == "" {
+		n.Timeout = base.Timeout
+	}
+	if n.BaseURL == "" {
+		n.BaseURL = base.BaseURL
+	}
+	return n
+}
+
+// WithoutCredentials returns n with any user:password stripped from Proxy, for
+// copies that leave the machine (backup exports).
+func (n Network) WithoutCredentials() Network {
+	if u, err := url.Parse(n.Proxy); err == nil && u.User != nil {
+		u.User = nil
+		n.Proxy = u.String()
+	}
+	return n
+}
+
+// Redacted returns n with the proxy password masked, for display.
+func (n Network) Redacted() Network {
+	if u, err := url.Parse(n.Proxy); err == nil && u.User != nil {
+		n.Proxy = u.Redacted()
+	}
+	return n
+}
+
+// TimeoutDuration parses Timeout (0 when unset). Call Validate first.
+func (n Network) TimeoutDuration() time.Duration {
+	d, _ := time.ParseDuration(n.Timeout)
+	return d
+}
+
+// Validate checks the URLs, the timeout, and that the CA files exist.
+func (n Network) Validate() error {
+	if n.Proxy != "" {
+		u, err := url.Parse(n.Proxy)
+		if err != nil || u.Host == "" {
+			return fmt.Errorf("proxy %q is not a URL (e.g. http://proxy:3128)", n.Proxy)
+		}
+		switch u.Scheme {
+		case "http", "https", "socks5", "socks5h":
+		default:
+			return fmt.Errorf("proxy scheme %q is not http, https, or socks5", u.Scheme)
+		}
+	}
+	if n.BaseURL != "" {
+		u, err := url.Parse(n.BaseURL)
+		if err != nil || u.Host == "" || (u.Scheme != "http" && u.Scheme != "https") {
+			return fmt.Errorf("base URL %q is not an http(s) URL", n.BaseURL)
+		}
+	}
+	if n.Timeout != "" {
+		if d, err := time.ParseDuration(n.Timeout); err != nil || d <= 0 {
+			return fmt.Errorf("timeout %q is not a positive duration (e.g. 45s)", n.Timeout)
+		}
+	}
+	for _, f := range n.CAFiles {
+		if _, err := os.Stat(f); err != nil {
+			return fmt.Errorf("CA file: %w", err)
+		}
+	}
+	return nil
+}
+
+// settingsFile is the global settings in settings.json. The proxy URL may
+// carry credentials, so the file is private to the user (mode 0600).
+type settingsFile struct {
+	Network  Network `json:"network,omitzero"`
+	SteamCMD string  `json:"steamcmd,omitempty"` // steamcmd binary path
+}
+
+func (s *Store) settingsPath() string { return filepath.Join(s.root, "settings.json") }
+
+func (s *Store) loadSettings() (settingsFile, error) {
+	var sf settingsFile
+	data, err := os.ReadFile(s.settingsPath())
+	if os.IsNotExist(err) {
+		return settingsFile{}, nil
+	}
+	if err != nil {
+		return sf, err
+	}
+	if err := json.Unmarshal(data, &sf); err != nil {
+		return sf, err
+	}
+	return sf, nil
+}
+
+func (s *Store) saveSettings(sf settingsFile) error {
+	data, err := json.MarshalIndent(sf, "", "  ")
+	if err != nil {
+		return err
+	}
+	return writePrivate(s.settingsPath(), data)
+}
+
+// GlobalNetwork returns the global network settings.
+func (s *Store) GlobalNetwork() (Network, error) {
+	sf, err := s.loadSettings()
+	return sf.Network, err
+}
+
+// SetGlobalNetwork stores the global network settings.
+func (s *Store) SetGlobalNetwork(n Network) error {
+	if err := n.Validate(); err != nil {
+		return err
+	}
+	sf, err := s.loadSettings()
+	if err != nil {
+		return err
+	}
+	sf.Network = n
+	return s.saveSettings(sf)
+}
+
+// Network resolves the effective network settings for a profile: its own over
+// the global ones. An empty or unknown profileID (an ad-hoc --file) gets the
+// global settings alone.
+func (s *Store) Network(profileID string) (Network, error) {
+	global, err := s.GlobalNetwork()
+	if err != nil {
+		return Network{}, err
+	}
+	if profileID == "" {
+		return global, nil
+	}
+	p, err := s.Profile(profileID)
+	if errors.Is(err, ErrNoProfile) || (err == nil && p.Network == nil) {
+		return global, nil
+	}
+	if err != nil {
+		return Network{}, err
+	}
+	return p.Network.Over(global), nil
+}
@@ -18,7 +18,30 @@ type Profile struct {
 	IniPath             string `json:"ini_path"`
 	Build               string `json:"build,omitempty"` // "b41" | "b42" | ""
 	WorkshopContentPath string `json:"workshop_content_path,omitempty"`
+	LocalModsPath       string `json:"local_mods_path,omitempty"` // Zomboid/mods: mods installed without the Workshop
 	BackupRetention     int    `json:"backup_retention,omitempty"`
+
+	// Retention, when set, replaces the keep-newest-N BackupRetention with
+	// time-bucketed and per-kind rules (see RetentionPolicy).
+	Retention *RetentionPolicy `json:"retention,omitempty"`
+
+	// Network, when set, overrides the global Steam connection settings for
+	// this profile (see Store.Network).
+	Network *Network `json:"network,omitempty"`
+
+	// ArchiveContent makes every snapshot also archive the Workshop content of
+	// the configured items (see Store.ArchiveContent), so a backup can roll
+	// mods back as well as the config.
+	ArchiveContent bool `json:"archive_content,omitempty"`
+}
+
+// Redacted returns p with its proxy password masked, for display.
+func (p Profile) Redacted() Profile {
+	if p.Network != nil {
+		n := p.Network.Redacted()
+		p.Network = &n
+	}
+	return p
 }
 
 type profilesFile struct {
@@ -46,7 +69,7 @@ func (s *Store) saveProfiles(pf profilesFile) error {
 	if err != nil {
 		return err
 	}
-	return os.WriteFile(s.profilesPath(), data, 0644)
+	return writePrivate(s.profilesPath(), data) // a profile's proxy may carry credentials
 }
 
 // Profiles returns all configured profiles.
@@ -92,18 +115,23 @@ func (s *Store) DefaultProfile() (Profile, error) {
 	return Profile{}, ErrNoProfile
 }
 
-// AddProfile stores a profile, assigning a unique ID from its name when unset.
+// AddProfile stores a profile, assigning a unique ID from its name when unset;
+// a given ID must be a safe file name, as it names the profile's backup dir.
 // The first profile added becomes the default. The stored profile is returned.
 func (s *Store) AddProfile(p Profile) (Profile, error) {
 	pf, err := s.loadProfiles()
 	if err != nil {
 		return Profile{}, err
 	}
-	if abs, err := filepath.Abs(p.IniPath); err == nil {
-		p.IniPath = abs
+	if p.IniPath != "" {
+		if abs, err := filepath.Abs(p.IniPath); err == nil {
+			p.IniPath = abs
+		}
 	}
 	if p.ID == "" {
 		p.ID = uniqueID(slugify(p.Name), pf.Profiles)
+	} else if !validStem(p.ID) {
+		return Profile{}, fmt.Errorf("invalid profile ID %q", p.ID)
 	}
 	for _, existing := range pf.Profiles {
 		if existing.ID == p.ID {
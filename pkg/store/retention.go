@@ -0,0 +1,165 @@
+package store
+
+import (
+	"fmt"
+	"sort"
+	"time"
+)
+
+// RetentionPolicy decides which snapshots survive a prune. A snapshot is kept
+// when any rule selects it, so the rules add up rather than compete. Pinned
+// snapshots are always kept and do not use up a rule's slots.
+//
+// The hourly/daily/weekly rules are grandfather-father-son buckets: each keeps
+// the newest snapshot of the last N hours (days, ISO weeks) that have one, so a
+// burst of edits can no longer push the last known-good config out of history.
+type RetentionPolicy struct {
+	KeepLast int `json:"keep_last,omitempty"` // newest N snapshots of any kind
+	Hourly   int `json:"hourly,omitempty"`
+	Daily    int `json:"daily,omitempty"`
+	Weekly   int `json:"weekly,omitempty"`
+	// Kinds keeps the newest N snapshots of a kind ("auto", "manual",
+	// "pre-restore") on top of the rules above. A negative N keeps all of them.
+	Kinds map[string]int `json:"kinds,omitempty"`
+}
+
+// IsZero reports whether the policy sets no rule at all. A kind kept zero
+// times is no rule; a negative count (keep all) is one.
+func (p RetentionPolicy) IsZero() bool {
+	for _, n := range p.Kinds {
+		if n != 0 {
+			return false
+		}
+	}
+	return p.KeepLast <= 0 && p.Hourly <= 0 && p.Daily <= 0 && p.Weekly <= 0
+}
+
+// RetentionPolicy returns the profile's effective policy: its explicit
+// Retention when set, otherwise the legacy keep-newest-N BackupRetention
+// (DefaultBackupRetention when unset).
+func (p Profile) RetentionPolicy() RetentionPolicy {
+	if p.Retention != nil && !p.Retention.IsZero() {
+		return *p.Retention
+	}
+	keep := p.BackupRetention
+	if keep <= 0 {
+		keep = DefaultBackupRetention
+	}
+	return RetentionPolicy{KeepLast: keep}
+}
+
+// Select returns the IDs of the entries the policy keeps. entries must be
+// sorted newest first (as Backups returns them).
+func (p RetentionPolicy) Select(entries []BackupEntry) map[string]bool {
+	keep := make(map[string]bool, len(entries))
+	var candidates []BackupEntry
+	for _, e := range entries {
+		if e.Pinned {
+			keep[e.ID] = true
+			continue
+		}
+		candidates = append(candidates, e)
+	}
+
+	for i, e := range candidates {
+		if i >= p.KeepLast {
+			break
+		}
+		keep[e.ID] = true
+	}
+	keepBuckets(candidates, p.Hourly, keep, func(t time.Time) string { return t.Format("2006-01-02T15") })
+	keepBuckets(candidates, p.Daily, keep, func(t time.Time) string { return t.Format("2006-01-02") })
+	keepBuckets(candidates, p.Weekly, keep, func(t time.Time) string {
+		y, w := t.ISOWeek()
+		return fmt.Sprintf("%d-W%02d", y, w)
+	})
+
+	for kind, n := range p.Kinds {
+		seen := 0
+		for _, e := range candidates {
+			if e.Kind != kind {
+				continue
+			}
+			if n >= 0 && seen >= n {
+				break
+			}
+			keep[e.ID] = true
+			seen++
+		}
+	}
+	return keep
+}
+
+// keepBuckets marks the newest entry of each of the first n distinct buckets.
+// Entries with an unparseable timestamp never fill a bucket.
+func keepBuckets(entries []BackupEntry, n int, keep map[string]bool, bucket func(time.Time) string) {
+	if n <= 0 {
+		return
+	}
+	seen := map[string]bool{}
+	for _, e := range entries {
+		t, err := time.Parse(time.RFC3339, e.Timestamp)
+		if err != nil {
+			continue
+		}
+		b := bucket(t.UTC())
+		if seen[b] {
+			continue
+		}
+		if len(seen) >= n {
+			return
+		}
+		seen[b] = true
+		keep[e.ID] = true
+	}
+}
+
+// PruneWithPolicy deletes every snapshot the policy does not keep and returns
+// the removed entries, newest first.
+func (s *Store) PruneWithPolicy(profileID string, policy RetentionPolicy) ([]BackupEntry, error) {
+	entries, err := s.Backups(profileID) // newest first
+	if err != nil {
+		return nil, err
+	}
+	keep := policy.Select(entries)
+	if len(keep) == len(entries) {
+		return nil, nil
+	}
+	var kept, removed []BackupEntry
+	for _, e := range entries {
+		if keep[e.ID] {
+			kept = append(kept, e)
+		} else {
+			removed = append(removed, e)
+		}
+	}
+	hadContent := false
+	for _, e := range removed {
+		s.removeBackupFiles(profileID, e)
+		hadContent = hadContent || e.Content != ""
+	}
+	sort.Slice(kept, func(i, j int) bool { return kept[i].ID < kept[j].ID }) // index is stored oldest first
+	if err := s.saveBackupIndex(profileID, kept); err != nil {
+		return nil, err
+	}
+	if hadContent {
+		return removed, s.collectContent()
+	}
+	return removed, nil
+}
+
+// PinBackup marks a snapshot as pinned (or unpins it). Pinned snapshots are
+// never pruned, only deleted explicitly.
+func (s *Store) PinBackup(profileID, backupID string, pinned bool) error {
+	entries, err := s.loadBackupIndex(profileID)
+	if err != nil {
+		return err
+	}
+	for i := range entries {
+		if entries[i].ID == backupID {
+			entries[i].Pinned = pinned
+			return s.saveBackupIndex(profileID, entries)
+		}
+	}
+	return ErrNoBackup
+}
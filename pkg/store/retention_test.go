@@ -0,0 +1,145 @@
+package store
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+// entryAt builds an index entry stamped at t (IDs sort like real stems).
+func entryAt(t time.Time, kind string) BackupEntry {
+	stem := t.UTC().Format("20060102-150405.000000000")
+	return BackupEntry{ID: stem, File: stem + ".ini", Timestamp: t.UTC().Format(time.RFC3339), Kind: kind}
+}
+
+// newestFirst reverses chronologically built entries into Backups order.
+func newestFirst(entries []BackupEntry) []BackupEntry {
+	out := make([]BackupEntry, len(entries))
+	for i, e := range entries {
+		out[len(entries)-1-i] = e
+	}
+	return out
+}
+
+func TestRetentionBuckets(t *testing.T) {
+	base := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC) // a Monday
+	var chrono []BackupEntry
+	// Three days, with a burst of five snapshots each morning.
+	for day := 0; day < 3; day++ {
+		for i := 0; i < 5; i++ {
+			chrono = append(chrono, entryAt(base.AddDate(0, 0, day).Add(time.Duration(i)*time.Minute), "auto"))
+		}
+	}
+	entries := newestFirst(chrono)
+
+	keep := RetentionPolicy{KeepLast: 2, Daily: 3}.Select(entries)
+	// Newest two, plus the newest of each of the three days (one overlaps).
+	if len(keep) != 4 {
+		t.Fatalf("kept %d; want 4 (%v)", len(keep), keep)
+	}
+	for _, day := range []int{0, 1} {
+		newestOfDay := chrono[day*5+4]
+		if !keep[newestOfDay.ID] {
+			t.Errorf("day %d: newest snapshot %s not kept", day, newestOfDay.ID)
+		}
+	}
+
+	// Weekly collapses the same three days into one bucket.
+	if keep := (RetentionPolicy{Weekly: 4}).Select(entries); len(keep) != 1 || !keep[entries[0].ID] {
+		t.Errorf("weekly kept %v; want only the newest", keep)
+	}
+}
+
+func TestRetentionKindsAndPins(t *testing.T) {
+	base := time.Date(2024, 3, 4, 9, 0, 0, 0, time.UTC)
+	var chrono []BackupEntry
+	chrono = append(chrono, entryAt(base, "manual"))
+	pinned := entryAt(base.Add(time.Minute), "auto")
+	pinned.Pinned = true
+	chrono = append(chrono, pinned)
+	for i := 2; i < 8; i++ {
+		chrono = append(chrono, entryAt(base.Add(time.Duration(i)*time.Minute), "auto"))
+	}
+	entries := newestFirst(chrono)
+
+	keep := RetentionPolicy{KeepLast: 2, Kinds: map[string]int{"manual": -1}}.Select(entries)
+	if !keep[chrono[0].ID] {
+		t.Error("manual snapshot should outlive auto ones")
+	}
+	if !keep[pinned.ID] {
+		t.Error("pinned snapshot must always be kept")
+	}
+	// Pins don't use up KeepLast: the two newest auto snapshots are still kept.
+	if !keep[chrono[7].ID] || !keep[chrono[6].ID] {
+		t.Errorf("newest two not kept: %v", keep)
+	}
+	if len(keep) != 4 {
+		t.Errorf("kept %d; want 4", len(keep))
+	}
+}
+
+func TestPinSurvivesPrune(t *testing.T) {
+	tick := time.Unix(1700000000, 0)
+	clock := func() time.Time { t := tick; tick = tick.Add(time.Second); return t }
+	s, err := New(WithRoot(t.TempDir()), WithClock(clock))
+	if err != nil {
+		t.Fatal(err)
+	}
+	cfg := filepath.Join(t.TempDir(), "server.ini")
+	if err := os.WriteFile(cfg, []byte("Mods=a\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	golden, err := s.Snapshot("p1", cfg, "before the big update", "manual")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := s.PinBackup("p1", golden.ID, true); err != nil {
+		t.Fatal(err)
+	}
+	for i := 0; i < 5; i++ {
+		if _, err := s.Snapshot("p1", cfg, "", "auto"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	removed, err := s.PruneWithPolicy("p1", RetentionPolicy{KeepLast: 2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(removed) != 3 {
+		t.Errorf("removed %d; want 3", len(removed))
+	}
+	backups, _ := s.Backups("p1")
+	if len(backups) != 3 {
+		t.Fatalf("backups = %d; want 3 (2 newest + pinned)", len(backups))
+	}
+	if _, err := s.ReadBackup("p1", golden.ID); err != nil {
+		t.Errorf("pinned backup was pruned: %v", err)
+	}
+
+	if err := s.PinBackup("p1", "nope", true); err != ErrNoBackup {
+		t.Errorf("pin unknown = %v; want ErrNoBackup", err)
+	}
+}
+
+func TestProfileRetentionPolicyFallback(t *testing.T) {
+	if got := (Profile{}).RetentionPolicy(); got.KeepLast != DefaultBackupRetention {
+		t.Errorf("default KeepLast = %d; want %d", got.KeepLast, DefaultBackupRetention)
+	}
+	if got := (Profile{BackupRetention: 4}).RetentionPolicy(); got.KeepLast != 4 {
+		t.Errorf("legacy KeepLast = %d; want 4", got.KeepLast)
+	}
+	p := Profile{BackupRetention: 4, Retention: &RetentionPolicy{Daily: 7}}
+	if got := p.RetentionPolicy(); got.KeepLast != 0 || got.Daily != 7 {
+		t.Errorf("explicit policy = %+v; want Daily 7 only", got)
+	}
+	// Keeping a kind zero times is no rule, so the legacy count still applies.
+	p = Profile{BackupRetention: 4, Retention: &RetentionPolicy{Kinds: map[string]int{"auto": 0}}}
+	if got := p.RetentionPolicy(); got.KeepLast != 4 {
+		t.Errorf("kinds-only-zero policy = %+v; want the legacy KeepLast 4", got)
+	}
+	if (RetentionPolicy{Kinds: map[string]int{"manual": -1}}).IsZero() {
+		t.Error("keeping every manual snapshot should count as a rule")
+	}
+}
@@ -0,0 +1,17 @@
+package store
+
+// SteamCMD returns the configured steamcmd binary path ("" when unset).
+func (s *Store) SteamCMD() (string, error) {
+	sf, err := s.loadSettings()
+	return sf.SteamCMD, err
+}
+
+// SetSteamCMD stores the steamcmd binary path; "" clears it.
+func (s *Store) SetSteamCMD(path string) error {
+	sf, err := s.loadSettings()
+	if err != nil {
+		return err
+	}
+	sf.SteamCMD = path
+	return s.saveSettings(sf)
+}
@@ -60,9 +60,13 @@ func New(opts ...Option) (*Store, error) {
 // Root returns the config root directory.
 func (s *Store) Root() string { return s.root }
 
+// WorkshopCacheDir is where the on-disk Workshop metadata cache lives.
+func (s *Store) WorkshopCacheDir() string { return filepath.Join(s.root, "cache", "workshop") }
+
 func (s *Store) profilesPath() string    { return filepath.Join(s.root, "profiles.json") }
 func (s *Store) credentialsPath() string { return filepath.Join(s.root, "credentials.json") }
 func (s *Store) backupsRoot() string     { return filepath.Join(s.root, "backups") }
+func (s *Store) journalRoot() string     { return filepath.Join(s.root, "journal") }
 
 // legacyKeyPath returns the v2 plaintext key location (~/.pzmod).
 func legacyKeyPath() (string, error) {
@@ -0,0 +1,215 @@
+// Package vdf parses Valve's text KeyValues format (VDF), as used by Steam's
+// .acf manifests. Only the text form is supported, which is all Steam writes
+// under steamapps.
+package vdf
+
+import (
+	"bufio"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+)
+
+// Object is a parsed KeyValues block. Each value is a string or a nested
+// Object. Keys keep their case; use Get and Obj for Steam's case-insensitive
+// lookups.
+type Object map[string]any
+
+// Get returns the string value for key (matched case-insensitively), or "".
+func (o Object) Get(key string) string {
+	s, _ := o.lookup(key).(string)
+	return s
+}
+
+// Obj returns the nested object for key (matched case-insensitively), or nil.
+func (o Object) Obj(key string) Object {
+	v, _ := o.lookup(key).(Object)
+	return v
+}
+
+func (o Object) lookup(key string) any {
+	if v, ok := o[key]; ok {
+		return v
+	}
+	for k, v := range o {
+		if strings.EqualFold(k, key) {
+			return v
+		}
+	}
+	return nil
+}
+
+// Parse reads a VDF document. The result holds the top-level keys, usually a
+// single object such as "AppWorkshop".
+func Parse(r io.Reader) (Object, error) {
+	p := &parser{r: bufio.NewReader(r), line: 1}
+	obj, err := p.object(false)
+	if err != nil {
+		return nil, fmt.Errorf("vdf: line %d: %w", p.line, err)
+	}
+	return obj, nil
+}
+
+type parser struct {
+	r    *bufio.Reader
+	line int
+}
+
+// object reads key/value pairs until '}' (nested) or EOF (top level).
+func (p *parser) object(nested bool) (Object, error) {
+	obj := Object{}
+	for {
+		tok, quoted, err := p.token()
+		if errors.Is(err, io.EOF) {
+			if nested {
+				return nil, errors.New("unexpected end of input; missing '}'")
+			}
+			return obj, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		if !quoted && tok == "}" {
+			if !nested {
+				return nil, errors.New("unexpected '}'")
+			}
+			return obj, nil
+		}
+		if !quoted && tok == "{" {
+			return nil, errors.New("'{' without a key")
+		}
+
+		val, vquoted, err := p.token()
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = fmt.Errorf("key %q has no value", tok)
+			}
+			return nil, err
+		}
+		switch {
+		case !vquoted && val == "{":
+			child, err := p.object(true)
+			if err != nil {
+				return nil, err
+			}
+			obj[tok] = child
+		case !vquoted && val == "}":
+			return nil, fmt.Errorf("key %q has no value", tok)
+		default:
+			obj[tok] = val
+		}
+		p.skipConditional()
+	}
+}
+
+// token returns the next string, brace, or bare word, skipping whitespace
+// and // comments. quoted reports whether it was a quoted string.
+func (p *parser) token() (tok string, quoted bool, err error) {
+	for {
+		c, err := p.next()
+		if err != nil {
+			return "", false, err
+		}
+		switch {
+		case c == ' ' || c == '\t' || c == '\r' || c == '\n':
+			continue
+		case c == '/':
+			if n, _ := p.r.Peek(1); len(n) == 1 && n[0] == '/' {
+				p.skipLine()
+				continue
+			}
+			return p.bare(c)
+		case c == '{' || c == '}':
+			return string(c), false, nil
+		case c == '"':
+			s, err := p.quoted()
+			return s, true, err
+		default:
+			return p.bare(c)
+		}
+	}
+}
+
+func (p *parser) quoted() (string, error) {
+	var b strings.Builder
+	for {
+		c, err := p.next()
+		if err != nil {
+			return "", errors.New("unterminated string")
+		}
+		switch c {
+		case '"':
+			return b.String(), nil
+		case '\\':
+			e, err := p.next()
+			if err != nil {
+				return "", errors.New("unterminated string")
+			}
+			switch e {
+			case 'n':
+				b.WriteByte('\n')
+			case 't':
+				b.WriteByte('\t')
+			default: // \\ and \" - and anything else, literally
+				b.WriteByte(e)
+			}
+		default:
+			b.WriteByte(c)
+		}
+	}
+}
+
+func (p *parser) bare(first byte) (string, bool, error) {
+	b := []byte{first}
+	for {
+		n, err := p.r.Peek(1)
+		if err != nil || strings.IndexByte(" \t\r\n{}\"", n[0]) >= 0 {
+			return string(b), false, nil
+		}
+		c, _ := p.next()
+		b = append(b, c)
+	}
+}
+
+// skipConditional drops a trailing platform conditional such as [$WIN32],
+// which Steam's files may carry after a value. Conditionals are not
+// evaluated.
+func (p *parser) skipConditional() {
+	for {
+		n, err := p.r.Peek(1)
+		if err != nil {
+			return
+		}
+		switch n[0] {
+		case ' ', '\t':
+			p.next()
+		case '[':
+			for {
+				c, err := p.next()
+				if err != nil || c == ']' || c == '\n' {
+					return
+				}
+			}
+		default:
+			return
+		}
+	}
+}
+
+func (p *parser) skipLine() {
+	for {
+		c, err := p.next()
+		if err != nil || c == '\n' {
+			return
+		}
+	}
+}
+
+func (p *parser) next() (byte, error) {
+	c, err := p.r.ReadByte()
+	if c == '\n' {
+		p.line++
+	}
+	return c, err
+}
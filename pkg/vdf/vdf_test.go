@@ -0,0 +1,60 @@
+package vdf
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestParse(t *testing.T) {
+	src := `// written by steamcmd
+"AppWorkshop"
+{
+	"appid"		"108600"
+	"SizeOnDisk"		"2048"
+	"WorkshopItemsInstalled"
+	{
+		"2392709985"
+		{
+			"size"		"1523312"
+			"timeupdated"		"1690000000"
+		}
+	}
+	"Title"		"Say \"hi\"\\n"  [$WIN32]
+	bare	value
+}
+`
+	doc, err := Parse(strings.NewReader(src))
+	if err != nil {
+		t.Fatal(err)
+	}
+	app := doc.Obj("appworkshop")
+	if app == nil {
+		t.Fatalf("doc = %v; want an AppWorkshop block", doc)
+	}
+	if got := app.Get("sizeondisk"); got != "2048" {
+		t.Errorf("SizeOnDisk = %q", got)
+	}
+	if got := app.Obj("WorkshopItemsInstalled").Obj("2392709985").Get("timeupdated"); got != "1690000000" {
+		t.Errorf("timeupdated = %q", got)
+	}
+	if got := app.Get("Title"); got != `Say "hi"\n` {
+		t.Errorf("Title = %q; want escapes resolved", got)
+	}
+	if got := app.Get("bare"); got != "value" {
+		t.Errorf("bare = %q", got)
+	}
+}
+
+func TestParseErrors(t *testing.T) {
+	for _, src := range []string{
+		`"a" { "b" "c"`,
+		`"a" "unterminated`,
+		`"a" }`,
+		`}`,
+		`"lonely"`,
+	} {
+		if _, err := Parse(strings.NewReader(src)); err == nil {
+			t.Errorf("Parse(%q) succeeded; want an error", src)
+		}
+	}
+}